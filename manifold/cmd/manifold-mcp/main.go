@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/jbeshir/mcp-servers/manifold/internal/journal"
 	"github.com/jbeshir/mcp-servers/manifold/internal/server"
 )
 
@@ -19,8 +20,24 @@ func main() {
 		apiURL = "https://api.manifold.markets"
 	}
 
+	wsURL := os.Getenv("MANIFOLD_WS_URL")
+	if wsURL == "" {
+		wsURL = "wss://api.manifold.markets/ws"
+	}
+
 	apiClient := client.NewClient(apiURL, apiKey)
-	srv := server.NewServer(apiClient)
+	liveClient := client.NewLiveClient(wsURL)
+
+	journalPath, err := journal.DefaultPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+	journalStore, err := journal.NewStore(journalPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := server.NewServer(apiClient, liveClient, journalStore)
 
 	if err := srv.Run(); err != nil {
 		log.Fatal(err)
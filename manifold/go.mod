@@ -2,13 +2,19 @@ module github.com/jbeshir/mcp-servers/manifold
 
 go 1.25.5
 
-require github.com/mark3labs/mcp-go v0.55.1
+require (
+	github.com/gobwas/ws v1.4.0
+	github.com/mark3labs/mcp-go v0.55.1
+)
 
 require (
+	github.com/gobwas/httphead v0.1.0 // indirect
+	github.com/gobwas/pool v0.2.1 // indirect
 	github.com/google/jsonschema-go v0.4.2 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2 // indirect
 	github.com/spf13/cast v1.7.1 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	golang.org/x/sys v0.6.0 // indirect
 	golang.org/x/text v0.25.0 // indirect
 )
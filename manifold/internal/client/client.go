@@ -181,6 +181,41 @@ func (c *Client) CancelBet(ctx context.Context, betID string) error {
 	return nil
 }
 
+// ListGroups lists topics, newest first, using query parameters.
+func (c *Client) ListGroups(ctx context.Context, params url.Values) ([]Group, error) {
+	path := "/v0/groups"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var groups []Group
+	if err := c.do(ctx, http.MethodGet, path, nil, &groups); err != nil {
+		return nil, fmt.Errorf("listing groups: %w", err)
+	}
+	return groups, nil
+}
+
+// SearchGroups searches for topics using query parameters.
+func (c *Client) SearchGroups(ctx context.Context, params url.Values) ([]Group, error) {
+	path := "/v0/search-groups"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var groups []Group
+	if err := c.do(ctx, http.MethodGet, path, nil, &groups); err != nil {
+		return nil, fmt.Errorf("searching groups: %w", err)
+	}
+	return groups, nil
+}
+
+// GetGroup retrieves a topic by its slug.
+func (c *Client) GetGroup(ctx context.Context, slug string) (*Group, error) {
+	var group Group
+	if err := c.do(ctx, http.MethodGet, "/v0/group/"+slug, nil, &group); err != nil {
+		return nil, fmt.Errorf("getting group %s: %w", slug, err)
+	}
+	return &group, nil
+}
+
 // CreateMarket creates a new market.
 func (c *Client) CreateMarket(ctx context.Context, req CreateMarketRequest) (*LiteMarket, error) {
 	var market LiteMarket
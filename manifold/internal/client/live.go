@@ -0,0 +1,465 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// Kinds of live event, derived from the websocket topic they arrived on.
+const (
+	LiveEventBet     = "bet"
+	LiveEventComment = "comment"
+	LiveEventMarket  = "market"
+)
+
+const (
+	defaultMaxLiveEvents = 1000
+	livePingInterval     = 30 * time.Second
+	liveMaxBackoff       = 30 * time.Second
+)
+
+// LiveEvent is a single update received over the websocket for a watched market.
+type LiveEvent struct {
+	Seq        int64          `json:"seq"`
+	Kind       string         `json:"kind"`
+	ContractID string         `json:"contractId"`
+	ReceivedAt int64          `json:"receivedAt"`
+	Bets       []Bet          `json:"bets,omitempty"`
+	Comment    *Comment       `json:"comment,omitempty"`
+	Market     map[string]any `json:"market,omitempty"`
+}
+
+// LiveMarketState is the latest known state of a watched market, built up
+// from the events received since it started being watched.
+type LiveMarketState struct {
+	ContractID      string   `json:"contractId"`
+	WatchingSince   int64    `json:"watchingSince"`
+	Probability     *float64 `json:"probability,omitempty"`
+	Volume          *float64 `json:"volume,omitempty"`
+	LastBetTime     *int64   `json:"lastBetTime,omitempty"`
+	LastCommentTime *int64   `json:"lastCommentTime,omitempty"`
+	CloseTime       *int64   `json:"closeTime,omitempty"`
+	IsResolved      bool     `json:"isResolved"`
+	Resolution      *string  `json:"resolution,omitempty"`
+	BetCount        int      `json:"betCount"`
+	CommentCount    int      `json:"commentCount"`
+	LastEventSeq    int64    `json:"lastEventSeq"`
+}
+
+// liveMessage is the envelope of messages exchanged with the websocket API.
+type liveMessage struct {
+	Type    string          `json:"type"`
+	TxID    int             `json:"txid,omitempty"`
+	Topics  []string        `json:"topics,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Success *bool           `json:"success,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// wsConn adapts a dialed connection for wsutil: reads drain any bytes the
+// handshake buffered before reading the socket, and each Write is atomic so
+// control frame replies from the read loop cannot interleave with messages.
+type wsConn struct {
+	net.Conn
+	r  io.Reader
+	mu sync.Mutex
+}
+
+func (c *wsConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *wsConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Conn.Write(p)
+}
+
+// LiveClient maintains a websocket connection to the Manifold API and an
+// in-memory view of the markets it watches. It connects lazily on the first
+// Watch and reconnects, resubscribing, if the connection drops.
+type LiveClient struct {
+	url       string
+	maxEvents int
+
+	mu       sync.Mutex
+	conn     *wsConn
+	txid     int
+	closed   bool
+	watched  map[string]*LiveMarketState
+	events   []LiveEvent
+	lastSeq  int64
+	onUpdate func(contractID string)
+}
+
+// NewLiveClient creates a websocket client for the given URL, e.g.
+// "wss://api.manifold.markets/ws".
+func NewLiveClient(wsURL string) *LiveClient {
+	return &LiveClient{
+		url:       wsURL,
+		maxEvents: defaultMaxLiveEvents,
+		watched:   make(map[string]*LiveMarketState),
+	}
+}
+
+// OnUpdate registers a callback invoked, outside any lock, after each event
+// for a watched market is recorded.
+func (c *LiveClient) OnUpdate(fn func(contractID string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onUpdate = fn
+}
+
+func contractTopics(contractID string) []string {
+	return []string{
+		"contract/" + contractID,
+		"contract/" + contractID + "/new-bet",
+		"contract/" + contractID + "/new-comment",
+	}
+}
+
+// Watch starts watching the given markets, connecting if necessary.
+func (c *LiveClient) Watch(ctx context.Context, contractIDs ...string) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return fmt.Errorf("live client is closed")
+	}
+	var topics []string
+	now := time.Now().UnixMilli()
+	for _, id := range contractIDs {
+		if _, ok := c.watched[id]; ok {
+			continue
+		}
+		c.watched[id] = &LiveMarketState{ContractID: id, WatchingSince: now}
+		topics = append(topics, contractTopics(id)...)
+	}
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		// Connecting subscribes to every watched market, including these.
+		return c.connect(ctx)
+	}
+	if len(topics) == 0 {
+		return nil
+	}
+	return c.send(conn, "subscribe", topics)
+}
+
+// Unwatch stops watching the given markets and discards their state.
+func (c *LiveClient) Unwatch(contractIDs ...string) error {
+	c.mu.Lock()
+	var topics []string
+	for _, id := range contractIDs {
+		if _, ok := c.watched[id]; !ok {
+			continue
+		}
+		delete(c.watched, id)
+		topics = append(topics, contractTopics(id)...)
+	}
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil || len(topics) == 0 {
+		return nil
+	}
+	return c.send(conn, "unsubscribe", topics)
+}
+
+// Watched returns the state of every watched market.
+func (c *LiveClient) Watched() []LiveMarketState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	states := make([]LiveMarketState, 0, len(c.watched))
+	for _, s := range c.watched {
+		states = append(states, *s)
+	}
+	return states
+}
+
+// State returns the state of a watched market.
+func (c *LiveClient) State(contractID string) (LiveMarketState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.watched[contractID]
+	if !ok {
+		return LiveMarketState{}, false
+	}
+	return *s, true
+}
+
+// EventsSince returns buffered events with a sequence number greater than
+// cursor, optionally limited to one market, and the cursor to pass next time.
+// Only the most recent events are buffered, so a stale cursor may miss some.
+func (c *LiveClient) EventsSince(cursor int64, contractID string) ([]LiveEvent, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var events []LiveEvent
+	for _, e := range c.events {
+		if e.Seq <= cursor || (contractID != "" && e.ContractID != contractID) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, c.lastSeq
+}
+
+// Close disconnects and stops any reconnection attempts.
+func (c *LiveClient) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// connect dials the websocket, subscribes to all watched markets, and starts
+// the read and ping loops for the new connection.
+func (c *LiveClient) connect(ctx context.Context) error {
+	raw, br, _, err := ws.Dial(ctx, c.url)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", c.url, err)
+	}
+	conn := &wsConn{Conn: raw, r: raw}
+	if br != nil {
+		conn.r = io.MultiReader(br, raw)
+	}
+
+	c.mu.Lock()
+	if c.closed || c.conn != nil {
+		// Closed, or another caller connected first.
+		c.mu.Unlock()
+		_ = raw.Close()
+		return nil
+	}
+	c.conn = conn
+	var topics []string
+	for id := range c.watched {
+		topics = append(topics, contractTopics(id)...)
+	}
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go c.readLoop(conn, done)
+	go c.pingLoop(conn, done)
+
+	if len(topics) == 0 {
+		return nil
+	}
+	return c.send(conn, "subscribe", topics)
+}
+
+// send writes a message of the given type as a single websocket frame.
+func (c *LiveClient) send(conn *wsConn, msgType string, topics []string) error {
+	c.mu.Lock()
+	c.txid++
+	msg := liveMessage{Type: msgType, TxID: c.txid, Topics: topics}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling %s message: %w", msgType, err)
+	}
+	var buf bytes.Buffer
+	if err := wsutil.WriteClientMessage(&buf, ws.OpText, data); err != nil {
+		return fmt.Errorf("encoding %s message: %w", msgType, err)
+	}
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("sending %s message: %w", msgType, err)
+	}
+	return nil
+}
+
+func (c *LiveClient) pingLoop(conn *wsConn, done <-chan struct{}) {
+	ticker := time.NewTicker(livePingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := c.send(conn, "ping", nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *LiveClient) readLoop(conn *wsConn, done chan<- struct{}) {
+	defer close(done)
+	for {
+		data, op, err := wsutil.ReadServerData(conn)
+		if err != nil {
+			break
+		}
+		if op == ws.OpText {
+			c.handleMessage(data)
+		}
+	}
+	_ = conn.Close()
+
+	c.mu.Lock()
+	dropped := c.conn == conn
+	if dropped {
+		c.conn = nil
+	}
+	reconnect := dropped && !c.closed && len(c.watched) > 0
+	c.mu.Unlock()
+
+	if reconnect {
+		go c.reconnect()
+	}
+}
+
+// reconnect retries connecting with exponential backoff until it succeeds,
+// the client is closed, or nothing is watched any more.
+func (c *LiveClient) reconnect() {
+	backoff := time.Second
+	for {
+		c.mu.Lock()
+		stop := c.closed || c.conn != nil || len(c.watched) == 0
+		c.mu.Unlock()
+		if stop {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), liveMaxBackoff)
+		err := c.connect(ctx)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff = min(backoff*2, liveMaxBackoff)
+	}
+}
+
+// parseTopic extracts the contract ID and event kind from a topic such as
+// "contract/abc/new-bet".
+func parseTopic(topic string) (contractID, kind string, ok bool) {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 2 || parts[0] != "contract" {
+		return "", "", false
+	}
+	switch {
+	case len(parts) == 2:
+		return parts[1], LiveEventMarket, true
+	case parts[2] == "new-bet":
+		return parts[1], LiveEventBet, true
+	case parts[2] == "new-comment":
+		return parts[1], LiveEventComment, true
+	}
+	return "", "", false
+}
+
+// broadcastData is the union of payloads carried by broadcast messages.
+type broadcastData struct {
+	Bets     []Bet          `json:"bets,omitempty"`
+	Comment  *Comment       `json:"comment,omitempty"`
+	Contract map[string]any `json:"contract,omitempty"`
+}
+
+func (c *LiveClient) handleMessage(data []byte) {
+	var msg liveMessage
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Type != "broadcast" {
+		return
+	}
+	contractID, kind, ok := parseTopic(msg.Topic)
+	if !ok {
+		return
+	}
+	var payload broadcastData
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	state, watched := c.watched[contractID]
+	if !watched {
+		c.mu.Unlock()
+		return
+	}
+	c.lastSeq++
+	event := LiveEvent{
+		Seq:        c.lastSeq,
+		Kind:       kind,
+		ContractID: contractID,
+		ReceivedAt: time.Now().UnixMilli(),
+		Bets:       payload.Bets,
+		Comment:    payload.Comment,
+		Market:     payload.Contract,
+	}
+	c.events = append(c.events, event)
+	if len(c.events) > c.maxEvents {
+		c.events = c.events[len(c.events)-c.maxEvents:]
+	}
+	state.apply(event)
+	onUpdate := c.onUpdate
+	c.mu.Unlock()
+
+	if onUpdate != nil {
+		onUpdate(contractID)
+	}
+}
+
+// apply folds an event into the market state.
+func (s *LiveMarketState) apply(e LiveEvent) {
+	s.LastEventSeq = e.Seq
+	switch e.Kind {
+	case LiveEventBet:
+		for i := range e.Bets {
+			b := &e.Bets[i]
+			s.BetCount++
+			s.LastBetTime = &b.CreatedTime
+			// Answer-level bets move the answer's probability, not the market's.
+			if b.AnswerID == nil {
+				s.Probability = &b.ProbAfter
+			}
+		}
+	case LiveEventComment:
+		s.CommentCount++
+		if e.Comment != nil {
+			s.LastCommentTime = &e.Comment.CreatedTime
+		}
+	case LiveEventMarket:
+		s.applyMarketUpdate(e.Market)
+	}
+}
+
+// applyMarketUpdate applies the fields present in a partial contract update.
+// Contract updates use the internal schema, e.g. "prob" for probability.
+func (s *LiveMarketState) applyMarketUpdate(m map[string]any) {
+	if v, ok := m["prob"].(float64); ok {
+		s.Probability = &v
+	}
+	if v, ok := m["volume"].(float64); ok {
+		s.Volume = &v
+	}
+	if v, ok := m["closeTime"].(float64); ok {
+		t := int64(v)
+		s.CloseTime = &t
+	}
+	if v, ok := m["isResolved"].(bool); ok {
+		s.IsResolved = v
+	}
+	if v, ok := m["resolution"].(string); ok {
+		s.Resolution = &v
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// wsStandIn is a local stand-in for the Manifold websocket API. It hands each
+// accepted connection and its subscribe messages to the test.
+type wsStandIn struct {
+	server *httptest.Server
+	conns  chan net.Conn
+	subs   chan liveMessage
+}
+
+func newWSStandIn(t *testing.T) *wsStandIn {
+	t.Helper()
+	s := &wsStandIn{
+		conns: make(chan net.Conn, 4),
+		subs:  make(chan liveMessage, 16),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		s.conns <- conn
+		go func() {
+			for {
+				data, _, err := wsutil.ReadClientData(conn)
+				if err != nil {
+					return
+				}
+				var msg liveMessage
+				if json.Unmarshal(data, &msg) == nil && msg.Type == "subscribe" {
+					s.subs <- msg
+				}
+			}
+		}()
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *wsStandIn) url() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *wsStandIn) broadcast(t *testing.T, conn net.Conn, topic, data string) {
+	t.Helper()
+	msg := `{"type":"broadcast","topic":"` + topic + `","data":` + data + `}`
+	if err := wsutil.WriteServerMessage(conn, ws.OpText, []byte(msg)); err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for websocket activity")
+	}
+	var zero T
+	return zero
+}
+
+func TestLiveClient_WatchAndReceive(t *testing.T) {
+	standIn := newWSStandIn(t)
+	live := NewLiveClient(standIn.url())
+	t.Cleanup(func() { _ = live.Close() })
+
+	updates := make(chan string, 8)
+	live.OnUpdate(func(id string) { updates <- id })
+
+	if err := live.Watch(context.Background(), "abc"); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	conn := receive(t, standIn.conns)
+	sub := receive(t, standIn.subs)
+	if len(sub.Topics) != 3 || sub.Topics[0] != "contract/abc" {
+		t.Errorf("unexpected subscription topics: %v", sub.Topics)
+	}
+
+	standIn.broadcast(t, conn, "contract/abc/new-bet",
+		`{"bets":[{"id":"b1","contractId":"abc","createdTime":100,"probBefore":0.4,"probAfter":0.45}]}`)
+	standIn.broadcast(t, conn, "contract/abc/new-comment",
+		`{"comment":{"id":"c1","contractId":"abc","createdTime":200}}`)
+	standIn.broadcast(t, conn, "contract/abc", `{"contract":{"id":"abc","prob":0.5,"volume":1234}}`)
+	standIn.broadcast(t, conn, "contract/other/new-bet", `{"bets":[]}`)
+	for range 3 {
+		if id := receive(t, updates); id != "abc" {
+			t.Errorf("expected update for abc, got %s", id)
+		}
+	}
+
+	events, cursor := live.EventsSince(0, "")
+	if len(events) != 3 || cursor != 3 {
+		t.Fatalf("expected 3 events and cursor 3, got %d events, cursor %d", len(events), cursor)
+	}
+	kinds := []string{events[0].Kind, events[1].Kind, events[2].Kind}
+	if kinds[0] != LiveEventBet || kinds[1] != LiveEventComment || kinds[2] != LiveEventMarket {
+		t.Errorf("unexpected event kinds: %v", kinds)
+	}
+
+	if more, _ := live.EventsSince(cursor, ""); len(more) != 0 {
+		t.Errorf("expected no events after cursor, got %d", len(more))
+	}
+
+	state, ok := live.State("abc")
+	if !ok {
+		t.Fatal("expected state for watched market")
+	}
+	if state.Probability == nil || *state.Probability != 0.5 {
+		t.Errorf("expected probability 0.5 from market update, got %v", state.Probability)
+	}
+	if state.BetCount != 1 || state.CommentCount != 1 || state.LastEventSeq != 3 {
+		t.Errorf("unexpected state counters: %+v", state)
+	}
+}
+
+func TestLiveClient_ReconnectResubscribes(t *testing.T) {
+	standIn := newWSStandIn(t)
+	live := NewLiveClient(standIn.url())
+	t.Cleanup(func() { _ = live.Close() })
+
+	if err := live.Watch(context.Background(), "abc"); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	first := receive(t, standIn.conns)
+	receive(t, standIn.subs)
+
+	_ = first.Close()
+
+	receive(t, standIn.conns)
+	sub := receive(t, standIn.subs)
+	if len(sub.Topics) != 3 || sub.Topics[0] != "contract/abc" {
+		t.Errorf("expected resubscription to abc, got %v", sub.Topics)
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	cases := []struct {
+		topic, id, kind string
+		ok              bool
+	}{
+		{"contract/abc", "abc", LiveEventMarket, true},
+		{"contract/abc/new-bet", "abc", LiveEventBet, true},
+		{"contract/abc/new-comment", "abc", LiveEventComment, true},
+		{"contract/abc/orders", "", "", false},
+		{"global/new-bet", "", "", false},
+	}
+	for _, c := range cases {
+		id, kind, ok := parseTopic(c.topic)
+		if id != c.id || kind != c.kind || ok != c.ok {
+			t.Errorf("parseTopic(%q) = %q, %q, %v; want %q, %q, %v", c.topic, id, kind, ok, c.id, c.kind, c.ok)
+		}
+	}
+}
@@ -21,6 +21,7 @@ type LiteMarket struct {
 	IsResolved        bool     `json:"isResolved"`
 	Resolution        *string  `json:"resolution,omitempty"`
 	ResolutionTime    *int64   `json:"resolutionTime,omitempty"`
+	ResolutionProb    *float64 `json:"resolutionProbability,omitempty"`
 	LastBetTime       *int64   `json:"lastBetTime,omitempty"`
 	LastCommentTime   *int64   `json:"lastCommentTime,omitempty"`
 	LastUpdatedTime   *int64   `json:"lastUpdatedTime,omitempty"`
@@ -41,10 +42,13 @@ type LiteMarketExtraFields struct {
 // FullMarket embeds LiteMarket and adds detailed fields.
 type FullMarket struct {
 	LiteMarket
-	Answers         []Answer `json:"answers,omitempty"`
-	Description     any      `json:"description,omitempty"`
-	TextDescription string   `json:"textDescription,omitempty"`
-	CoverImageURL   *string  `json:"coverImageUrl,omitempty"`
+	Answers []Answer `json:"answers,omitempty"`
+	// ShouldAnswersSumToOne is set on multiple choice markets whose answers
+	// are mutually exclusive, so their probabilities sum to one.
+	ShouldAnswersSumToOne *bool   `json:"shouldAnswersSumToOne,omitempty"`
+	Description           any     `json:"description,omitempty"`
+	TextDescription       string  `json:"textDescription,omitempty"`
+	CoverImageURL         *string `json:"coverImageUrl,omitempty"`
 }
 
 // Answer represents a possible answer in a multiple choice market.
@@ -59,6 +63,24 @@ type Answer struct {
 	Pool           any      `json:"pool,omitempty"`
 	Resolution     *string  `json:"resolution,omitempty"`
 	ResolutionTime *int64   `json:"resolutionTime,omitempty"`
+	AnswerExtraFields
+}
+
+// AnswerExtraFields holds answer fields that only appear in some responses.
+// The bulk portfolio endpoint reports answer probabilities as "prob" and
+// includes per-answer probability changes.
+type AnswerExtraFields struct {
+	Prob        *float64     `json:"prob,omitempty"`
+	ProbChanges *ProbChanges `json:"probChanges,omitempty"`
+}
+
+// CurrentProb returns the answer's probability from whichever field the
+// response populated, or nil if neither is present.
+func (a *Answer) CurrentProb() *float64 {
+	if a.Probability != nil {
+		return a.Probability
+	}
+	return a.Prob
 }
 
 // User represents a Manifold user profile.
@@ -75,6 +97,18 @@ type User struct {
 	ProfitCached  *ProfitCached `json:"profitCached,omitempty"`
 }
 
+// Group represents a Manifold topic (called a group in the API).
+type Group struct {
+	ID            string `json:"id"`
+	Slug          string `json:"slug"`
+	Name          string `json:"name"`
+	CreatorID     string `json:"creatorId"`
+	CreatedTime   int64  `json:"createdTime"`
+	TotalMembers  int    `json:"totalMembers"`
+	PrivacyStatus string `json:"privacyStatus,omitempty"`
+	About         any    `json:"about,omitempty"`
+}
+
 // ProfitCached holds cached profit information.
 type ProfitCached struct {
 	Daily   float64 `json:"daily"`
@@ -128,6 +162,8 @@ type Comment struct {
 	CreatedTime  int64  `json:"createdTime"`
 	Content      any    `json:"content,omitempty"`
 	Markdown     string `json:"markdown,omitempty"`
+	// ReplyToCommentID is set when this comment is a reply to another comment.
+	ReplyToCommentID *string `json:"replyToCommentId,omitempty"`
 }
 
 // ContractMetric represents a user's position in a market.
@@ -166,6 +202,10 @@ type PortfolioMarket struct {
 	Volume24Hours   float64     `json:"volume24Hours"`
 	ProbChanges     ProbChanges `json:"probChanges"`
 	CreatorUsername string      `json:"creatorUsername"`
+	Min             *float64    `json:"min,omitempty"`
+	Max             *float64    `json:"max,omitempty"`
+	IsLogScale      *bool       `json:"isLogScale,omitempty"`
+	Answers         []Answer    `json:"answers,omitempty"`
 }
 
 // URL returns the full Manifold URL for this market.
@@ -208,6 +248,7 @@ type CreateMarketRequest struct {
 	Max         *float64 `json:"max,omitempty"`
 	IsLogScale  *bool    `json:"isLogScale,omitempty"`
 	Answers     []string `json:"answers,omitempty"`
+	GroupIDs    []string `json:"groupIds,omitempty"`
 }
 
 // ResolveMarketRequest is the request body for resolving a market.
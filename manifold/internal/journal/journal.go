@@ -0,0 +1,118 @@
+// Package journal persists executed trades together with the reasoning
+// behind them, so theses can be reviewed once markets resolve.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+// Trade actions recorded in the journal.
+const (
+	ActionBuy  = "buy"
+	ActionSell = "sell"
+)
+
+// Entry is a single executed trade and the metadata supplied with it.
+type Entry struct {
+	RecordedAt int64      `json:"recordedAt"`
+	Action     string     `json:"action"`
+	Bet        client.Bet `json:"bet"`
+	Rationale  string     `json:"rationale,omitempty"`
+	// Confidence is the trader's probability that the trade's outcome is
+	// correct, in [0, 1].
+	Confidence *float64 `json:"confidence,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// Store appends journal entries to a JSON Lines file on disk.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a Store writing to the given file, creating its directory.
+func NewStore(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create journal dir: %w", err)
+	}
+	return &Store{path: path}, nil
+}
+
+// DefaultPath returns the journal file path.
+// If MANIFOLD_JOURNAL_PATH is set, that path is used directly.
+// Otherwise falls back to os.UserConfigDir.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("MANIFOLD_JOURNAL_PATH"); path != "" {
+		return path, nil
+	}
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("get config dir: %w", err)
+	}
+	return filepath.Join(cfgDir, "manifold-mcp", "journal.jsonl"), nil
+}
+
+// Append records an entry, stamping RecordedAt if unset.
+func (s *Store) Append(e Entry) error {
+	if e.RecordedAt == 0 {
+		e.RecordedAt = time.Now().UnixMilli()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode journal entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write journal: %w", err)
+	}
+	return f.Close()
+}
+
+// Load reads every entry in the journal, oldest first.
+// Returns nil, nil if the journal does not exist yet.
+func (s *Store) Load() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("decode journal line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	return entries, nil
+}
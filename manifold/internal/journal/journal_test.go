@@ -0,0 +1,66 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func TestStore_AppendAndLoad(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "nested", "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.Load()
+	if err != nil || entries != nil {
+		t.Fatalf("expected empty journal, got %v, %v", entries, err)
+	}
+
+	confidence := 0.7
+	if err := store.Append(Entry{
+		Action:     ActionBuy,
+		Bet:        client.Bet{ID: "b1", ContractID: "m1", Amount: 10, Shares: 20, Outcome: "YES"},
+		Rationale:  "polls moving",
+		Confidence: &confidence,
+		Tags:       []string{"politics"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(Entry{Action: ActionSell, Bet: client.Bet{ID: "b2", ContractID: "m1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	first := entries[0]
+	if first.Bet.ID != "b1" || first.Rationale != "polls moving" || *first.Confidence != 0.7 {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if len(first.Tags) != 1 || first.Tags[0] != "politics" {
+		t.Errorf("expected politics tag, got %v", first.Tags)
+	}
+	if first.RecordedAt == 0 {
+		t.Error("expected RecordedAt to be stamped")
+	}
+	if entries[1].Action != ActionSell {
+		t.Errorf("expected sell entry, got %s", entries[1].Action)
+	}
+}
+
+func TestDefaultPath_Env(t *testing.T) {
+	t.Setenv("MANIFOLD_JOURNAL_PATH", "/tmp/custom.jsonl")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/tmp/custom.jsonl" {
+		t.Errorf("expected env override, got %s", path)
+	}
+}
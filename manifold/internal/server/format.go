@@ -3,91 +3,216 @@ package server
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/jbeshir/mcp-servers/manifold/internal/client"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-func formatMarkets(markets []client.LiteMarket) (*mcp.CallToolResult, error) {
-	if len(markets) == 0 {
-		return mcp.NewToolResultText("No markets found."), nil
+// newStructuredResult returns a tool result carrying v as structured content,
+// with the one-line summary as its text content.
+func newStructuredResult(summary string, v any) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent(summary)},
+		StructuredContent: v,
+	}, nil
+}
+
+// formatProb renders an optional probability as a percentage.
+func formatProb(p *float64) string {
+	if p == nil {
+		return "n/a"
 	}
-	data, err := json.MarshalIndent(markets, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format markets: %v", err)), nil
+	return fmt.Sprintf("%.1f%%", *p*100)
+}
+
+// marketList is the structured output for tools returning markets.
+type marketList struct {
+	Markets []client.LiteMarket `json:"markets"`
+}
+
+func formatMarkets(markets []client.LiteMarket) (*mcp.CallToolResult, error) {
+	if markets == nil {
+		markets = []client.LiteMarket{}
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d market(s):\n\n%s", len(markets), string(data))), nil
+	return newStructuredResult(fmt.Sprintf("Found %d market(s).", len(markets)), marketList{Markets: markets})
 }
 
 func formatLiteMarket(market *client.LiteMarket) (*mcp.CallToolResult, error) {
-	data, err := json.MarshalIndent(market, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format market: %v", err)), nil
-	}
-	return mcp.NewToolResultText(string(data)), nil
+	return newStructuredResult(fmt.Sprintf("Market %s: %s (%s, %s) %s",
+		market.ID, market.Question, market.OutcomeType, formatProb(market.Probability), market.URL), market)
+}
+
+// fullMarketView is a FullMarket with its rich-text description rendered to
+// markdown. The redundant plain-text description is dropped.
+type fullMarketView struct {
+	*client.FullMarket
+	Description     string `json:"description,omitempty"`
+	TextDescription string `json:"textDescription,omitempty"`
 }
 
 func formatFullMarket(market *client.FullMarket) (*mcp.CallToolResult, error) {
-	data, err := json.MarshalIndent(market, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format market: %v", err)), nil
+	view := fullMarketView{
+		FullMarket:  market,
+		Description: tiptapToMarkdown(market.Description),
+	}
+	if view.Description == "" {
+		view.Description = market.TextDescription
+	}
+	summary := fmt.Sprintf("Market %s: %s (%s, %s", market.ID, market.Question, market.OutcomeType,
+		formatProb(market.Probability))
+	if len(market.Answers) > 0 {
+		summary += fmt.Sprintf(", %d answers", len(market.Answers))
 	}
-	return mcp.NewToolResultText(string(data)), nil
+	if market.IsResolved && market.Resolution != nil {
+		summary += ", resolved " + *market.Resolution
+	}
+	return newStructuredResult(summary+")", view)
 }
 
 func formatUser(user *client.User) (*mcp.CallToolResult, error) {
-	data, err := json.MarshalIndent(user, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format user: %v", err)), nil
-	}
-	return mcp.NewToolResultText(string(data)), nil
+	return newStructuredResult(fmt.Sprintf("User %s (@%s), balance %.0f mana.",
+		user.Name, user.Username, user.Balance), user)
+}
+
+// betList is the structured output for tools returning bets.
+type betList struct {
+	Bets []client.Bet `json:"bets"`
 }
 
 func formatBets(bets []client.Bet) (*mcp.CallToolResult, error) {
-	if len(bets) == 0 {
-		return mcp.NewToolResultText("No bets found."), nil
-	}
-	data, err := json.MarshalIndent(bets, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format bets: %v", err)), nil
+	if bets == nil {
+		bets = []client.Bet{}
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d bet(s):\n\n%s", len(bets), string(data))), nil
+	return newStructuredResult(fmt.Sprintf("Found %d bet(s).", len(bets)), betList{Bets: bets})
 }
 
 func formatBet(bet *client.Bet) (*mcp.CallToolResult, error) {
-	data, err := json.MarshalIndent(bet, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format bet: %v", err)), nil
+	return newStructuredResult(fmt.Sprintf("Bet %s: %.2f mana for %.2f %s shares, probability %.1f%% -> %.1f%%.",
+		bet.ID, bet.Amount, bet.Shares, bet.Outcome, bet.ProbBefore*100, bet.ProbAfter*100), bet)
+}
+
+// commentView is a comment with its content rendered to markdown and its
+// replies nested beneath it.
+type commentView struct {
+	ID           string         `json:"id"`
+	ContractID   string         `json:"contractId"`
+	UserName     string         `json:"userName"`
+	UserUsername string         `json:"userUsername"`
+	CreatedTime  int64          `json:"createdTime"`
+	Markdown     string         `json:"markdown"`
+	Replies      []*commentView `json:"replies,omitempty"`
+}
+
+func newCommentView(c *client.Comment) *commentView {
+	markdown := tiptapToMarkdown(c.Content)
+	if markdown == "" {
+		markdown = c.Markdown
+	}
+	return &commentView{
+		ID:           c.ID,
+		ContractID:   c.ContractID,
+		UserName:     c.UserName,
+		UserUsername: c.UserUsername,
+		CreatedTime:  c.CreatedTime,
+		Markdown:     markdown,
 	}
-	return mcp.NewToolResultText(string(data)), nil
 }
 
-func formatComments(comments []client.Comment) (*mcp.CallToolResult, error) {
-	if len(comments) == 0 {
-		return mcp.NewToolResultText("No comments found."), nil
+// buildCommentThreads nests comments under the comments they reply to.
+// Top-level comments keep the order the API returned them in; replies are
+// ordered oldest first so conversations read naturally. Replies whose parent
+// was not fetched are treated as top-level.
+func buildCommentThreads(comments []client.Comment) []*commentView {
+	views := make(map[string]*commentView, len(comments))
+	for i := range comments {
+		views[comments[i].ID] = newCommentView(&comments[i])
+	}
+
+	var roots []*commentView
+	for i := range comments {
+		c := &comments[i]
+		view := views[c.ID]
+		if c.ReplyToCommentID != nil {
+			if parent, ok := views[*c.ReplyToCommentID]; ok && parent != view {
+				parent.Replies = append(parent.Replies, view)
+				continue
+			}
+		}
+		roots = append(roots, view)
 	}
-	data, err := json.MarshalIndent(comments, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format comments: %v", err)), nil
+
+	for _, v := range views {
+		sort.SliceStable(v.Replies, func(i, j int) bool {
+			return v.Replies[i].CreatedTime < v.Replies[j].CreatedTime
+		})
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d comment(s):\n\n%s", len(comments), string(data))), nil
+	return roots
 }
 
-func formatComment(comment *client.Comment) (*mcp.CallToolResult, error) {
-	data, err := json.MarshalIndent(comment, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format comment: %v", err)), nil
+// commentThreads is the structured output for get_comments.
+type commentThreads struct {
+	CommentCount int            `json:"commentCount"`
+	Threads      []*commentView `json:"threads"`
+}
+
+// commentViewSchema is the JSON schema for a commentView. Comment threads are
+// recursive, which the schema generator behind mcp.WithOutputSchema cannot
+// express, so the schemas for comment output are written by hand.
+const commentViewSchema = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "string"},
+		"contractId": {"type": "string"},
+		"userName": {"type": "string"},
+		"userUsername": {"type": "string"},
+		"createdTime": {"type": "integer"},
+		"markdown": {"type": "string"},
+		"replies": {"type": "array", "items": {"$ref": "#/$defs/comment"}}
+	},
+	"required": ["id", "contractId", "userName", "userUsername", "createdTime", "markdown"]
+}`
+
+var (
+	commentThreadsSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"commentCount": {"type": "integer"},
+		"threads": {"type": "array", "items": {"$ref": "#/$defs/comment"}}
+	},
+	"required": ["commentCount", "threads"],
+	"$defs": {"comment": ` + commentViewSchema + `}
+}`)
+	commentSchema = json.RawMessage(`{
+	"$ref": "#/$defs/comment",
+	"type": "object",
+	"$defs": {"comment": ` + commentViewSchema + `}
+}`)
+)
+
+func formatComments(comments []client.Comment) (*mcp.CallToolResult, error) {
+	threads := buildCommentThreads(comments)
+	if threads == nil {
+		threads = []*commentView{}
 	}
-	return mcp.NewToolResultText(string(data)), nil
+	return newStructuredResult(
+		fmt.Sprintf("Found %d comment(s) in %d thread(s).", len(comments), len(threads)),
+		commentThreads{CommentCount: len(comments), Threads: threads},
+	)
+}
+
+func formatComment(comment *client.Comment) (*mcp.CallToolResult, error) {
+	return newStructuredResult(fmt.Sprintf("Comment %s added.", comment.ID), newCommentView(comment))
+}
+
+// positionList is the structured output for get_positions.
+type positionList struct {
+	Positions []client.ContractMetric `json:"positions"`
 }
 
 func formatPositions(positions []client.ContractMetric) (*mcp.CallToolResult, error) {
-	if len(positions) == 0 {
-		return mcp.NewToolResultText("No positions found."), nil
-	}
-	data, err := json.MarshalIndent(positions, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format positions: %v", err)), nil
+	if positions == nil {
+		positions = []client.ContractMetric{}
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d position(s):\n\n%s", len(positions), string(data))), nil
+	return newStructuredResult(fmt.Sprintf("Found %d position(s).", len(positions)), positionList{Positions: positions})
 }
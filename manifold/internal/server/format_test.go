@@ -0,0 +1,73 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuildCommentThreads(t *testing.T) {
+	root, reply := "c1", "c2"
+	comments := []client.Comment{
+		// API order is newest first.
+		{ID: "c4", CreatedTime: 400, ReplyToCommentID: &root, Markdown: "late reply"},
+		{ID: "c3", CreatedTime: 300, ReplyToCommentID: &reply, Markdown: "reply to reply"},
+		{ID: "c2", CreatedTime: 200, ReplyToCommentID: &root, Markdown: "early reply"},
+		{ID: "c1", CreatedTime: 100, Markdown: "root"},
+		{ID: "c0", CreatedTime: 50, ReplyToCommentID: strPtr("missing"), Markdown: "orphan"},
+	}
+
+	threads := buildCommentThreads(comments)
+	if len(threads) != 2 {
+		t.Fatalf("expected 2 top-level threads, got %d", len(threads))
+	}
+	if threads[0].ID != "c1" || threads[1].ID != "c0" {
+		t.Errorf("expected roots c1, c0; got %s, %s", threads[0].ID, threads[1].ID)
+	}
+
+	replies := threads[0].Replies
+	if len(replies) != 2 || replies[0].ID != "c2" || replies[1].ID != "c4" {
+		t.Fatalf("expected replies c2, c4 oldest first, got %+v", replies)
+	}
+	if len(replies[0].Replies) != 1 || replies[0].Replies[0].ID != "c3" {
+		t.Errorf("expected c3 nested under c2, got %+v", replies[0].Replies)
+	}
+}
+
+func TestNewCommentView_RendersContent(t *testing.T) {
+	c := client.Comment{
+		ID: "c1",
+		Content: map[string]any{
+			"type": "doc",
+			"content": []any{
+				map[string]any{"type": "paragraph", "content": []any{
+					map[string]any{"type": "text", "text": "hello"},
+				}},
+			},
+		},
+	}
+	if got := newCommentView(&c).Markdown; got != "hello" {
+		t.Errorf("expected rendered markdown %q, got %q", "hello", got)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestNewStructuredResult(t *testing.T) {
+	result, err := newStructuredResult("Found 1 market(s).", marketList{Markets: []client.LiteMarket{{ID: "m"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) != 1 {
+		t.Fatalf("expected one content block, got %d", len(result.Content))
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "Found 1 market(s)." {
+		t.Errorf("expected only the summary as text, got %+v", result.Content[0])
+	}
+	if list, ok := result.StructuredContent.(marketList); !ok || list.Markets[0].ID != "m" {
+		t.Errorf("expected the market list as structured content, got %+v", result.StructuredContent)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/jbeshir/mcp-servers/manifold/internal/journal"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -9,31 +10,56 @@ import (
 // Server is the MCP server for Manifold Markets.
 type Server struct {
 	client    *client.Client
+	live      *client.LiveClient
+	journal   *journal.Store
+	subs      *liveSubscriptions
 	mcpServer *server.MCPServer
 }
 
-// NewServer creates a new MCP server with the given client.
-func NewServer(apiClient *client.Client) *Server {
+// NewServer creates a new MCP server with the given REST and websocket clients,
+// recording executed trades to the given journal.
+func NewServer(apiClient *client.Client, liveClient *client.LiveClient, journalStore *journal.Store) *Server {
 	s := &Server{
-		client: apiClient,
+		client:  apiClient,
+		live:    liveClient,
+		journal: journalStore,
+		subs:    newLiveSubscriptions(),
 	}
 
+	hooks := &server.Hooks{}
+	s.subs.addHooks(hooks)
 	s.mcpServer = server.NewMCPServer(
 		"manifold",
 		"0.1.0",
 		server.WithLogging(),
+		server.WithResourceCapabilities(true, false),
+		server.WithHooks(hooks),
 	)
 
 	s.registerTools()
+	s.registerResources()
+	s.live.OnUpdate(s.notifyLiveUpdate)
 
 	return s
 }
 
 // Run starts the MCP server with stdio transport.
 func (s *Server) Run() error {
+	defer func() { _ = s.live.Close() }()
 	return server.ServeStdio(s.mcpServer)
 }
 
+func (s *Server) registerResources() {
+	s.mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(
+		liveResourcePrefix+"{contractId}",
+		"Live market state",
+		mcp.WithTemplateDescription(
+			"Live state and recent websocket events for a market being watched via get_live_updates. "+
+				"Subscribe to receive resource-updated notifications as bets, comments, and market updates arrive."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), s.handleReadLiveResource)
+}
+
 func (s *Server) registerTools() {
 	s.mcpServer.AddTool(mcp.NewTool("search_markets",
 		mcp.WithDescription(
@@ -60,14 +86,18 @@ func (s *Server) registerTools() {
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of results (default: 100, max: 1000)"),
 		),
+		mcp.WithOutputSchema[marketList](),
 	), s.handleSearchMarkets)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_market",
-		mcp.WithDescription("Get full details of a specific Manifold market including answers and description."),
+		mcp.WithDescription(
+			"Get full details of a specific Manifold market including answers and description. "+
+				"The description is rendered as markdown."),
 		mcp.WithString("marketId",
 			mcp.Required(),
 			mcp.Description("The market ID or slug"),
 		),
+		mcp.WithOutputSchema[fullMarketView](),
 	), s.handleGetMarket)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_user",
@@ -76,10 +106,12 @@ func (s *Server) registerTools() {
 			mcp.Required(),
 			mcp.Description("The username to look up"),
 		),
+		mcp.WithOutputSchema[client.User](),
 	), s.handleGetUser)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_me",
 		mcp.WithDescription("Get the authenticated user's own Manifold profile."),
+		mcp.WithOutputSchema[client.User](),
 	), s.handleGetMe)
 
 	s.mcpServer.AddTool(mcp.NewTool("list_bets",
@@ -99,10 +131,13 @@ func (s *Server) registerTools() {
 		mcp.WithString("kinds",
 			mcp.Description("Comma-separated bet kinds to include"),
 		),
+		mcp.WithOutputSchema[betList](),
 	), s.handleListBets)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_comments",
-		mcp.WithDescription("Get comments on Manifold markets."),
+		mcp.WithDescription(
+			"Get comments on Manifold markets. "+
+				"Comments are rendered as markdown and grouped into threads, with replies nested under the comment they answer."),
 		mcp.WithString("contractId",
 			mcp.Description("Filter by market/contract ID"),
 		),
@@ -112,6 +147,7 @@ func (s *Server) registerTools() {
 		mcp.WithString("userId",
 			mcp.Description("Filter by user ID"),
 		),
+		mcp.WithRawOutputSchema(commentThreadsSchema),
 	), s.handleGetComments)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_positions",
@@ -132,12 +168,14 @@ func (s *Server) registerTools() {
 		mcp.WithString("userId",
 			mcp.Description("Filter to a specific user's position"),
 		),
+		mcp.WithOutputSchema[positionList](),
 	), s.handleGetPositions)
 
 	s.mcpServer.AddTool(mcp.NewTool("place_bet",
 		mcp.WithDescription(
 			"Place a bet or limit order on a Manifold market. "+
-				"Use dryRun=true to simulate without executing."),
+				"Use dryRun=true to simulate without executing. "+
+				"Executed bets are recorded in the trade journal with any rationale, confidence, and tags."),
 		mcp.WithNumber("amount",
 			mcp.Required(),
 			mcp.Description("Amount of mana to bet"),
@@ -158,10 +196,22 @@ func (s *Server) registerTools() {
 		mcp.WithBoolean("dryRun",
 			mcp.Description("If true, simulates the bet without executing it"),
 		),
+		mcp.WithString("rationale",
+			mcp.Description("Why you are making this trade, recorded in the trade journal"),
+		),
+		mcp.WithNumber("confidence",
+			mcp.Description("Your probability (0-1) that this trade's outcome is correct, recorded in the trade journal"),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated tags for grouping this trade in the trade journal"),
+		),
+		mcp.WithOutputSchema[client.Bet](),
 	), s.handlePlaceBet)
 
 	s.mcpServer.AddTool(mcp.NewTool("sell_shares",
-		mcp.WithDescription("Sell shares in a Manifold market."),
+		mcp.WithDescription(
+			"Sell shares in a Manifold market. "+
+				"Sales are recorded in the trade journal with any rationale, confidence, and tags."),
 		mcp.WithString("marketId",
 			mcp.Required(),
 			mcp.Description("The market ID to sell shares in"),
@@ -175,6 +225,16 @@ func (s *Server) registerTools() {
 		mcp.WithString("answerId",
 			mcp.Description("Answer ID for multiple choice markets"),
 		),
+		mcp.WithString("rationale",
+			mcp.Description("Why you are making this trade, recorded in the trade journal"),
+		),
+		mcp.WithNumber("confidence",
+			mcp.Description("Your probability (0-1) that this trade's outcome is correct, recorded in the trade journal"),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated tags for grouping this trade in the trade journal"),
+		),
+		mcp.WithOutputSchema[client.Bet](),
 	), s.handleSellShares)
 
 	s.mcpServer.AddTool(mcp.NewTool("cancel_bet",
@@ -183,6 +243,7 @@ func (s *Server) registerTools() {
 			mcp.Required(),
 			mcp.Description("The bet/limit order ID to cancel"),
 		),
+		mcp.WithOutputSchema[cancelBetResult](),
 	), s.handleCancelBet)
 
 	s.mcpServer.AddTool(mcp.NewTool("create_market",
@@ -220,6 +281,7 @@ func (s *Server) registerTools() {
 		mcp.WithString("answers",
 			mcp.Description("Comma-separated list of answers for MULTIPLE_CHOICE markets"),
 		),
+		mcp.WithOutputSchema[client.LiteMarket](),
 	), s.handleCreateMarket)
 
 	s.mcpServer.AddTool(mcp.NewTool("resolve_market",
@@ -241,6 +303,7 @@ func (s *Server) registerTools() {
 		mcp.WithString("answerId",
 			mcp.Description("Answer ID for resolving MULTIPLE_CHOICE markets"),
 		),
+		mcp.WithOutputSchema[marketActionResult](),
 	), s.handleResolveMarket)
 
 	s.mcpServer.AddTool(mcp.NewTool("close_market",
@@ -252,6 +315,7 @@ func (s *Server) registerTools() {
 		mcp.WithNumber("closeTime",
 			mcp.Description("New closing time as Unix timestamp in milliseconds (omit to close immediately)"),
 		),
+		mcp.WithOutputSchema[marketActionResult](),
 	), s.handleCloseMarket)
 
 	s.mcpServer.AddTool(mcp.NewTool("add_comment",
@@ -264,6 +328,7 @@ func (s *Server) registerTools() {
 			mcp.Required(),
 			mcp.Description("Comment content in markdown format"),
 		),
+		mcp.WithRawOutputSchema(commentSchema),
 	), s.handleAddComment)
 
 	s.mcpServer.AddTool(mcp.NewTool("add_liquidity",
@@ -276,6 +341,7 @@ func (s *Server) registerTools() {
 			mcp.Required(),
 			mcp.Description("Amount of mana to add as liquidity"),
 		),
+		mcp.WithOutputSchema[marketActionResult](),
 	), s.handleAddLiquidity)
 
 	s.mcpServer.AddTool(mcp.NewTool("send_mana",
@@ -291,6 +357,7 @@ func (s *Server) registerTools() {
 		mcp.WithString("message",
 			mcp.Description("Optional message to include with the mana transfer"),
 		),
+		mcp.WithOutputSchema[sendManaResult](),
 	), s.handleSendMana)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_baseline",
@@ -306,6 +373,7 @@ func (s *Server) registerTools() {
 		mcp.WithNumber("lookbackHours",
 			mcp.Description("How many hours back to compute the baseline from (default: 24)"),
 		),
+		mcp.WithOutputSchema[baselineResponse](),
 	), s.handleGetBaseline)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_portfolio_pnl",
@@ -313,6 +381,8 @@ func (s *Server) registerTools() {
 			"Get a full portfolio P&L summary for a user. "+
 				"Discovers all positions, computes current value, cost basis, total P&L, "+
 				"24h probability changes, and 24h P&L for each position. "+
+				"Multiple choice positions are valued per answer, and PSEUDO_NUMERIC positions "+
+				"include the market's expected value. "+
 				"Returns open positions sorted by |24h P&L|, significant movers (>2pp change), "+
 				"and recently resolved markets (last 7 days). "+
 				"May take 30-60 seconds for large portfolios."),
@@ -320,5 +390,355 @@ func (s *Server) registerTools() {
 			mcp.Required(),
 			mcp.Description("The Manifold user ID to compute portfolio P&L for"),
 		),
+		mcp.WithOutputSchema[portfolioResponse](),
 	), s.handleGetPortfolioPnl)
+
+	s.mcpServer.AddTool(mcp.NewTool("scan_arbitrage",
+		mcp.WithDescription(
+			"Scan Manifold markets for internally inconsistent prices. "+
+				"Checks multiple choice markets whose answers should sum to 100%, "+
+				"sibling contracts that should trade at the same probability "+
+				"(a mana market and its cash twin, reported with a caveat since the tokens can't be exchanged), "+
+				"and user-supplied groups of logically linked markets. "+
+				"Returns opportunities with the trades that exploit them (one share per leg) "+
+				"and the guaranteed edge after taker fees, sorted by edge. "+
+				"Edges use current prices and do not account for slippage."),
+		mcp.WithString("marketIds",
+			mcp.Description("Comma-separated market IDs to check"),
+		),
+		mcp.WithString("term",
+			mcp.Description("Search term to find open multiple choice and sibling markets to check"),
+		),
+		mcp.WithString("topicSlug",
+			mcp.Description("Topic/group slug to find open multiple choice and sibling markets to check"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of search results to check (default: 100)"),
+		),
+		mcp.WithArray("links",
+			mcp.Description(
+				"Logical relationships between markets. Each is an object with a type and a list of "+
+					"market references (market ID, or marketId:answerId for an answer). Types: "+
+					"implies (exactly 2 markets, first implies second), equivalent (exactly 2), "+
+					"exclusive (at most one resolves YES), exhaustive (at least one resolves YES)"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type": map[string]any{
+						"type": "string",
+						"enum": []string{linkImplies, linkEquivalent, linkExclusive, linkExhaustive},
+					},
+					"markets": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"type": "string"},
+					},
+				},
+				"required": []string{"type", "markets"},
+			}),
+		),
+		mcp.WithNumber("minEdge",
+			mcp.Description("Minimum edge after fees, in mana per share set, to report (default: 0.01)"),
+		),
+		mcp.WithOutputSchema[arbitrageResponse](),
+	), s.handleScanArbitrage)
+
+	s.mcpServer.AddTool(mcp.NewTool("get_live_updates",
+		mcp.WithDescription(
+			"Watch Manifold markets in real time over the websocket API and get events since a cursor. "+
+				"Pass watch to start receiving bets, comments, and market updates for markets; "+
+				"call again with the returned cursor to get only new events. "+
+				"Returns the live state of every watched market and the new events. "+
+				"Each watched market is also available as the resource manifold://live/{contractId}."),
+		mcp.WithString("watch",
+			mcp.Description("Comma-separated market IDs to start watching"),
+		),
+		mcp.WithString("unwatch",
+			mcp.Description("Comma-separated market IDs to stop watching"),
+		),
+		mcp.WithNumber("cursor",
+			mcp.Description("Return only events after this cursor (from a previous call; omit for all buffered events)"),
+		),
+		mcp.WithString("contractId",
+			mcp.Description("Only return events for this market"),
+		),
+		mcp.WithOutputSchema[liveUpdatesResponse](),
+	), s.handleGetLiveUpdates)
+
+	s.mcpServer.AddTool(mcp.NewTool("get_creator_dashboard",
+		mcp.WithDescription(
+			"List all markets created by the authenticated user, flagging markets past their close time "+
+				"but unresolved (overdue) and markets closing soon, with volume, bettor count, and "+
+				"unanswered comment threads. Markets needing attention are listed first. "+
+				"Optionally applies a bulk action (extend_close or resolve_na) to the given markets, "+
+				"or to every overdue market if marketIds is omitted. "+
+				"Actions are previewed unless dryRun=false."),
+		mcp.WithNumber("closingWithinHours",
+			mcp.Description("Flag markets closing within this many hours as closing soon (default: 48)"),
+		),
+		mcp.WithBoolean("includeResolved",
+			mcp.Description("Include resolved markets in the list (default: false)"),
+		),
+		mcp.WithString("action",
+			mcp.Description("Bulk action: extend_close (set a new close time) or resolve_na (resolve as CANCEL)"),
+		),
+		mcp.WithString("marketIds",
+			mcp.Description("Comma-separated market IDs to apply the action to (default: all overdue markets)"),
+		),
+		mcp.WithNumber("closeTime",
+			mcp.Description("New close time for extend_close, as Unix timestamp in milliseconds"),
+		),
+		mcp.WithNumber("extendDays",
+			mcp.Description("For extend_close, set the close time this many days from now"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true (the default), previews the action without changing any market"),
+		),
+		mcp.WithOutputSchema[creatorDashboardResponse](),
+	), s.handleGetCreatorDashboard)
+
+	s.mcpServer.AddTool(mcp.NewTool("review_journal",
+		mcp.WithDescription(
+			"Review trades recorded in the trade journal against how their markets have since moved or resolved. "+
+				"Limit orders are reconciled with their current fills; unfilled orders are listed but not scored. "+
+				"Returns each trade with its rationale, confidence, status (open, won, lost, cancelled, unfilled), "+
+				"and P&L at the current or resolved price, newest first, "+
+				"plus per-tag totals with realized and unrealized P&L, hit rate, "+
+				"and a Brier score of stated confidence on resolved buys."),
+		mcp.WithString("tag",
+			mcp.Description("Only review trades with this tag"),
+		),
+		mcp.WithString("contractId",
+			mcp.Description("Only review trades on this market"),
+		),
+		mcp.WithNumber("sinceDays",
+			mcp.Description("Only review trades made in the last this many days"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of trades to list (default: 50); totals cover all matching trades"),
+		),
+		mcp.WithOutputSchema[journalReviewResponse](),
+	), s.handleReviewJournal)
+
+	s.mcpServer.AddTool(mcp.NewTool("reduce_position",
+		mcp.WithDescription(
+			"Sell down a position in a Manifold market until a target is reached: the position's value "+
+				"at the post-sale price (targetValue), the shares held and so the payout if the outcome wins "+
+				"(targetExposure), or the market probability (targetProb). "+
+				"The share amount is found by simulating sells against the market's liquidity pool, "+
+				"so the result accounts for price impact. "+
+				"If the target cannot be reached, the whole position is sold. "+
+				"The sale is previewed unless dryRun=false. Sales are recorded in the trade journal."),
+		mcp.WithString("marketId",
+			mcp.Required(),
+			mcp.Description("The market ID to reduce the position in"),
+		),
+		mcp.WithString("answerId",
+			mcp.Description("Answer ID for multiple choice markets"),
+		),
+		mcp.WithString("outcome",
+			mcp.Description("Which outcome's shares to sell: YES or NO (default: the side with more shares)"),
+		),
+		mcp.WithNumber("targetValue",
+			mcp.Description("Sell until the remaining position is worth at most this much mana"),
+		),
+		mcp.WithNumber("targetExposure",
+			mcp.Description("Sell until at most this many shares remain"),
+		),
+		mcp.WithNumber("targetProb",
+			mcp.Description("Sell until the market probability reaches this value (0-1)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true (the default), previews the sale without executing it"),
+		),
+		mcp.WithString("rationale",
+			mcp.Description("Why you are making this trade, recorded in the trade journal"),
+		),
+		mcp.WithNumber("confidence",
+			mcp.Description("Your probability (0-1) that this trade's outcome is correct, recorded in the trade journal"),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated tags for grouping this trade in the trade journal"),
+		),
+		mcp.WithOutputSchema[reduceResult](),
+	), s.handleReducePosition)
+
+	s.mcpServer.AddTool(mcp.NewTool("rebalance_portfolio",
+		mcp.WithDescription(
+			"Trim every open position in the authenticated user's portfolio whose current value exceeds "+
+				"a fraction of net worth (cash balance plus open position value), selling each down to that limit. "+
+				"Sale sizes are found by simulating sells, as for reduce_position. "+
+				"Trims are previewed unless dryRun=false. Sales are recorded in the trade journal. "+
+				"May take 30-60 seconds for large portfolios."),
+		mcp.WithNumber("maxFraction",
+			mcp.Required(),
+			mcp.Description("Maximum fraction of net worth (0-1) any one position may be worth, e.g. 0.1"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true (the default), previews the trims without selling"),
+		),
+		mcp.WithString("rationale",
+			mcp.Description("Why you are making this trade, recorded in the trade journal"),
+		),
+		mcp.WithNumber("confidence",
+			mcp.Description("Your probability (0-1) that this trade's outcome is correct, recorded in the trade journal"),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated tags for grouping this trade in the trade journal"),
+		),
+		mcp.WithOutputSchema[rebalanceResponse](),
+	), s.handleRebalancePortfolio)
+
+	s.mcpServer.AddTool(mcp.NewTool("create_markets_batch",
+		mcp.WithDescription(
+			"Create a series of related Manifold markets from a list of market specs. "+
+				"Spec fields left empty fall back to the batch-level outcomeType, questionTemplate, "+
+				"descriptionTemplate, and closeDate, which may contain {{name}} placeholders filled from "+
+				"each spec's vars ({{question}} is also available in descriptionTemplate). "+
+				"Every spec is validated, and every topic looked up, before any market is created; "+
+				"if anything is invalid, nothing is created and all problems are reported. "+
+				"Returns per-market results, the estimated creation cost of each market and of the batch, "+
+				"and the total mana spent. Use dryRun=true to validate and preview the cost only."),
+		mcp.WithArray("markets",
+			mcp.Required(),
+			mcp.Description("Market specs to create, in order"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"question":    map[string]any{"type": "string"},
+					"outcomeType": map[string]any{"type": "string"},
+					"description": map[string]any{"type": "string", "description": "Markdown description"},
+					"closeTime": map[string]any{
+						"type": "number", "description": "Unix timestamp in milliseconds",
+					},
+					"closeDate": map[string]any{
+						"type": "string", "description": "YYYY-MM-DD (end of day UTC), RFC 3339, or +Nd/+Nw/+Nh",
+					},
+					"initialProb": map[string]any{"type": "number", "description": "1-99, for BINARY"},
+					"min":         map[string]any{"type": "number"},
+					"max":         map[string]any{"type": "number"},
+					"isLogScale":  map[string]any{"type": "boolean"},
+					"answers": map[string]any{
+						"type": "array", "items": map[string]any{"type": "string"},
+						"description": "Answers for MULTIPLE_CHOICE and POLL markets",
+					},
+					"topics": map[string]any{
+						"type": "array", "items": map[string]any{"type": "string"},
+						"description": "Topic slugs, in addition to the batch-level topics",
+					},
+					"vars": map[string]any{
+						"type": "object", "additionalProperties": map[string]any{"type": "string"},
+						"description": "Values for {{name}} placeholders in the batch templates",
+					},
+				},
+			}),
+		),
+		mcp.WithString("outcomeType",
+			mcp.Description("Default market type: BINARY, MULTIPLE_CHOICE, FREE_RESPONSE, PSEUDO_NUMERIC, BOUNTY, POLL, NUMBER"),
+		),
+		mcp.WithString("questionTemplate",
+			mcp.Description("Question for specs without one, e.g. \"Will X happen by {{month}}?\""),
+		),
+		mcp.WithString("descriptionTemplate",
+			mcp.Description("Markdown description for specs without one"),
+		),
+		mcp.WithString("closeDate",
+			mcp.Description("Close date for specs without one: YYYY-MM-DD (end of day UTC), RFC 3339, or +Nd/+Nw/+Nh; "+
+				"may use {{name}} placeholders, e.g. \"{{month}}-28\""),
+		),
+		mcp.WithString("topics",
+			mcp.Description("Comma-separated topic slugs to add every market to"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true, validates and previews the batch without creating anything"),
+		),
+		mcp.WithOutputSchema[batchCreateResponse](),
+	), s.handleCreateMarketsBatch)
+
+	s.mcpServer.AddTool(mcp.NewTool("list_topics",
+		mcp.WithDescription(
+			"List or search Manifold topics (groups). "+
+				"With a term, searches topics by name; otherwise lists topics newest first. "+
+				"Topic descriptions are rendered as markdown. Use a topic's slug with get_topic_markets, "+
+				"topic_summary, or the topicSlug filter of search_markets."),
+		mcp.WithString("term",
+			mcp.Description("Search query to match in topic names"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of topics to return"),
+		),
+		mcp.WithNumber("beforeTime",
+			mcp.Description("When listing, return topics created before this Unix timestamp in milliseconds (for pagination)"),
+		),
+		mcp.WithOutputSchema[topicList](),
+	), s.handleListTopics)
+
+	s.mcpServer.AddTool(mcp.NewTool("get_topic_markets",
+		mcp.WithDescription(
+			"Get the markets in a Manifold topic, a page at a time. "+
+				"Returns nextOffset when there may be more markets to fetch."),
+		mcp.WithString("topicSlug",
+			mcp.Required(),
+			mcp.Description("The topic slug"),
+		),
+		mcp.WithString("sort",
+			mcp.Description(
+				"Sort order: score, newest, resolve-date, close-date, "+
+					"liquidity, last-updated, last-bet-time, "+
+					"last-comment-time, most-popular, daily-score"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Filter by status: all, open, closed, resolved"),
+		),
+		mcp.WithString("contractType",
+			mcp.Description("Filter by type: ALL, BINARY, MULTIPLE_CHOICE, FREE_RESPONSE, PSEUDO_NUMERIC, BOUNTY, POLL, NUMBER"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of markets per page (default: 100, max: 1000)"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of markets to skip (use nextOffset from the previous page)"),
+		),
+		mcp.WithOutputSchema[topicMarketsResponse](),
+	), s.handleGetTopicMarkets)
+
+	s.mcpServer.AddTool(mcp.NewTool("topic_summary",
+		mcp.WithDescription(
+			"Summarize activity in a Manifold topic: market counts, total and 24h volume, "+
+				"the busiest markets by 24h volume, the biggest 24h probability movers "+
+				"(computed from recent bets as in get_baseline, among the most traded open markets), "+
+				"and newly created markets."),
+		mcp.WithString("topicSlug",
+			mcp.Required(),
+			mcp.Description("The topic slug"),
+		),
+		mcp.WithNumber("newWithinDays",
+			mcp.Description("List markets created within this many days as new (default: 7)"),
+		),
+		mcp.WithNumber("moverLimit",
+			mcp.Description("Maximum number of movers to return (default: 10)"),
+		),
+		mcp.WithOutputSchema[topicSummaryResponse](),
+	), s.handleTopicSummary)
+
+	s.mcpServer.AddTool(mcp.NewTool("get_portfolio_history",
+		mcp.WithDescription(
+			"Reconstruct a user's portfolio over the last N days by replaying their bets against "+
+				"each market's probability history. Returns a daily series (UTC day ends, the last being now) of "+
+				"open position value and realized, unrealized, total, and daily P&L, plus the markets "+
+				"contributing most to gains and losses over the window. "+
+				"Positions are realized once their market (or answer) resolves or they are fully sold. "+
+				"Covers binary, multiple choice, and PSEUDO_NUMERIC markets. "+
+				"May take a minute or more for users with many bets."),
+		mcp.WithString("userId",
+			mcp.Required(),
+			mcp.Description("The Manifold user ID to reconstruct history for"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Number of days to cover (default: 30, max: 365)"),
+		),
+		mcp.WithNumber("top",
+			mcp.Description("Number of top gaining and losing markets to return (default: 5)"),
+		),
+		mcp.WithOutputSchema[historyResponse](),
+	), s.handleGetPortfolioHistory)
 }
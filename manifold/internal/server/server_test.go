@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newValidatingServer registers every tool on an MCP server that checks
+// structured results against the tools' output schemas.
+func newValidatingServer(apiURL string) *Server {
+	s := &Server{client: client.NewClient(apiURL, "key")}
+	s.mcpServer = server.NewMCPServer("manifold", "test", server.WithOutputSchemaValidation())
+	s.registerTools()
+	return s
+}
+
+func TestToolsHaveOutputSchemas(t *testing.T) {
+	s := newValidatingServer("http://unused")
+	tools := s.mcpServer.ListTools()
+	if len(tools) == 0 {
+		t.Fatal("expected registered tools")
+	}
+	for name, tool := range tools {
+		if len(tool.Tool.RawOutputSchema) > 0 {
+			var schema map[string]any
+			if err := json.Unmarshal(tool.Tool.RawOutputSchema, &schema); err != nil {
+				t.Errorf("%s: invalid raw output schema: %v", name, err)
+			}
+			continue
+		}
+		if tool.Tool.OutputSchema.Type != "object" || len(tool.Tool.OutputSchema.Properties) == 0 {
+			t.Errorf("%s: missing output schema", name)
+		}
+	}
+}
+
+// callTool invokes a tool through the MCP server and returns the decoded
+// tool result.
+func callTool(t *testing.T, s *Server, name string, args map[string]any) map[string]any {
+	t.Helper()
+	req, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s.mcpServer.HandleMessage(context.Background(), req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Result map[string]any `json:"result"`
+		Error  any            `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s: JSON-RPC error: %v", name, resp.Error)
+	}
+	return resp.Result
+}
+
+func TestStructuredOutputMatchesSchemas(t *testing.T) {
+	market := `{"id":"m1","question":"Will it rain?","url":"https://manifold.markets/a/rain",` +
+		`"outcomeType":"BINARY","probability":0.6,"closeTime":1700000000000,"isResolved":false}`
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/search-markets":
+			_, _ = fmt.Fprintf(w, "[%s]", market)
+		case "/v0/market/m1":
+			_, _ = w.Write([]byte(`{"id":"m1","question":"Will it rain?","outcomeType":"MULTIPLE_CHOICE",` +
+				`"description":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Hi"}]}]},` +
+				`"answers":[{"id":"a1","text":"Yes","probability":0.4}]}`))
+		case "/v0/comments":
+			_, _ = w.Write([]byte(`[{"id":"c1","contractId":"m1","userName":"A","userUsername":"a","createdTime":1,` +
+				`"markdown":"first"},{"id":"c2","contractId":"m1","userName":"B","userUsername":"b","createdTime":2,` +
+				`"markdown":"reply","replyToCommentId":"c1"}]`))
+		case "/v0/me":
+			_, _ = w.Write([]byte(`{"id":"u1","name":"Alice","username":"alice","balance":100}`))
+		case "/v0/bets":
+			_, _ = w.Write([]byte(`[]`))
+		case "/v0/bet/cancel/b1":
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	s := newValidatingServer(api.URL)
+	calls := []struct {
+		tool string
+		args map[string]any
+	}{
+		{"search_markets", map[string]any{"term": "rain"}},
+		{"get_market", map[string]any{"marketId": "m1"}},
+		{"get_comments", map[string]any{"contractId": "m1"}},
+		{"get_me", map[string]any{}},
+		{"list_bets", map[string]any{}},
+		{"cancel_bet", map[string]any{"betId": "b1"}},
+	}
+	for _, c := range calls {
+		result := callTool(t, s, c.tool, c.args)
+		if isErr, _ := result["isError"].(bool); isErr {
+			t.Errorf("%s: unexpected error result: %v", c.tool, result["content"])
+			continue
+		}
+		if result["structuredContent"] == nil {
+			t.Errorf("%s: expected structured content", c.tool)
+		}
+	}
+
+	threads := callTool(t, s, "get_comments", map[string]any{"contractId": "m1"})["structuredContent"]
+	got, _ := threads.(map[string]any)
+	if got["commentCount"] != 2.0 {
+		t.Errorf("expected 2 comments in structured output, got %v", got)
+	}
+}
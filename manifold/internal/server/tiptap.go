@@ -0,0 +1,227 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// tiptapNode is a node in a TipTap (ProseMirror) rich-text document, the
+// format Manifold uses for market descriptions and comment content.
+type tiptapNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []tiptapMark   `json:"marks,omitempty"`
+	Content []tiptapNode   `json:"content,omitempty"`
+}
+
+// tiptapMark is an inline formatting mark applied to a text node.
+type tiptapMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// tiptapToMarkdown converts rich-text content as decoded from the API (a
+// TipTap document, or occasionally a plain string) to markdown. Content that
+// cannot be interpreted as a TipTap document is rendered as an empty string.
+func tiptapToMarkdown(content any) string {
+	switch v := content.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	var doc tiptapNode
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(renderBlock(doc))
+}
+
+// renderBlocks renders a sequence of block nodes separated by blank lines.
+func renderBlocks(nodes []tiptapNode, sep string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if s := renderBlock(n); strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func renderBlock(n tiptapNode) string {
+	switch n.Type {
+	case "doc":
+		return renderBlocks(n.Content, "\n\n")
+	case "paragraph":
+		return renderInline(n.Content)
+	case "heading":
+		level := attrInt(n.Attrs, "level", 1)
+		return strings.Repeat("#", min(max(level, 1), 6)) + " " + renderInline(n.Content)
+	case "bulletList":
+		return renderList(n, false)
+	case "orderedList":
+		return renderList(n, true)
+	case "blockquote":
+		return prefixLines(renderBlocks(n.Content, "\n\n"), "> ", "> ")
+	case "codeBlock":
+		return "```" + attrString(n.Attrs, "language") + "\n" + plainText(n.Content) + "\n```"
+	case "horizontalRule":
+		return "---"
+	}
+	// Unknown nodes: recurse as blocks unless they directly wrap text.
+	if len(n.Content) > 0 && !hasTextChild(n) {
+		return renderBlocks(n.Content, "\n\n")
+	}
+	return renderInline([]tiptapNode{n})
+}
+
+// renderList renders a bullet or ordered list. List items are kept tight, and
+// continuation lines are indented under the item marker so nested lists nest.
+func renderList(n tiptapNode, ordered bool) string {
+	start := attrInt(n.Attrs, "start", 1)
+	items := make([]string, 0, len(n.Content))
+	for i, item := range n.Content {
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		body := renderBlocks(item.Content, "\n")
+		items = append(items, prefixLines(body, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// prefixLines prefixes the first line of s with first and every later line with rest.
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line == "":
+			lines[i] = strings.TrimRight(rest, " ")
+		default:
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderInline(nodes []tiptapNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(renderInlineNode(n))
+	}
+	return b.String()
+}
+
+func renderInlineNode(n tiptapNode) string {
+	switch n.Type {
+	case "text":
+		return applyMarks(n.Text, n.Marks)
+	case "hardBreak":
+		return "\n"
+	case "mention":
+		return "@" + firstAttr(n.Attrs, "label", "id")
+	case "contract-mention":
+		return "%" + firstAttr(n.Attrs, "label", "id")
+	case "image":
+		return "![" + attrString(n.Attrs, "alt") + "](" + attrString(n.Attrs, "src") + ")"
+	case "iframe":
+		return "[embed](" + attrString(n.Attrs, "src") + ")"
+	case "tiptapTweet":
+		return "[tweet](https://twitter.com/i/web/status/" + attrString(n.Attrs, "tweetId") + ")"
+	case "linkPreview":
+		url := attrString(n.Attrs, "url")
+		return "[" + firstNonEmpty(attrString(n.Attrs, "title"), url) + "](" + url + ")"
+	}
+	return renderInline(n.Content)
+}
+
+// applyMarks wraps text in markdown syntax for each of its marks. Code is
+// applied innermost and links outermost so the result stays well formed.
+func applyMarks(text string, marks []tiptapMark) string {
+	var link string
+	hasLink := false
+	for _, m := range marks {
+		if m.Type == "code" {
+			text = "`" + text + "`"
+		}
+	}
+	for _, m := range marks {
+		switch m.Type {
+		case "bold":
+			text = "**" + text + "**"
+		case "italic":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			link = attrString(m.Attrs, "href")
+			hasLink = true
+		}
+	}
+	if hasLink {
+		text = "[" + text + "](" + link + ")"
+	}
+	return text
+}
+
+// plainText concatenates the raw text of nodes, ignoring marks.
+func plainText(nodes []tiptapNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(n.Text)
+		b.WriteString(plainText(n.Content))
+	}
+	return b.String()
+}
+
+func hasTextChild(n tiptapNode) bool {
+	for _, c := range n.Content {
+		if c.Type == "text" {
+			return true
+		}
+	}
+	return false
+}
+
+func attrString(attrs map[string]any, key string) string {
+	if v, ok := attrs[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func attrInt(attrs map[string]any, key string, def int) int {
+	if v, ok := attrs[key].(float64); ok {
+		return int(v)
+	}
+	return def
+}
+
+// firstAttr returns the first non-empty string attribute among keys.
+func firstAttr(attrs map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if v := attrString(attrs, k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
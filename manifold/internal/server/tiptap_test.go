@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// decodeDoc decodes a JSON document the way the API client does, into `any`.
+func decodeDoc(t *testing.T, raw string) any {
+	t.Helper()
+	var doc any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatalf("invalid test document: %v", err)
+	}
+	return doc
+}
+
+func TestTiptapToMarkdown_Nil(t *testing.T) {
+	if got := tiptapToMarkdown(nil); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
+
+func TestTiptapToMarkdown_PlainString(t *testing.T) {
+	if got := tiptapToMarkdown("already text"); got != "already text" {
+		t.Errorf("expected string passthrough, got %q", got)
+	}
+}
+
+func TestTiptapToMarkdown_ParagraphsAndMarks(t *testing.T) {
+	doc := decodeDoc(t, `{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Criteria"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Resolves "},
+			{"type":"text","text":"YES","marks":[{"type":"bold"}]},
+			{"type":"text","text":" per "},
+			{"type":"text","text":"source","marks":[{"type":"link","attrs":{"href":"https://example.com"}},{"type":"italic"}]},
+			{"type":"text","text":" using "},
+			{"type":"text","text":"x > 1","marks":[{"type":"code"}]}
+		]},
+		{"type":"paragraph"},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"line one"},{"type":"hardBreak"},{"type":"text","text":"line two"}
+		]}
+	]}`)
+
+	want := "## Criteria\n\n" +
+		"Resolves **YES** per [*source*](https://example.com) using `x > 1`\n\n" +
+		"line one\nline two"
+	if got := tiptapToMarkdown(doc); got != want {
+		t.Errorf("unexpected markdown:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestTiptapToMarkdown_MentionsAndEmbeds(t *testing.T) {
+	doc := decodeDoc(t, `{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"mention","attrs":{"id":"u1","label":"alice"}},
+			{"type":"text","text":" see "},
+			{"type":"contract-mention","attrs":{"id":"c1","label":"will-it-rain"}}
+		]},
+		{"type":"image","attrs":{"src":"https://img.example/a.png","alt":"chart"}},
+		{"type":"iframe","attrs":{"src":"https://www.youtube.com/embed/xyz"}},
+		{"type":"tiptapTweet","attrs":{"tweetId":"123"}},
+		{"type":"linkPreview","attrs":{"url":"https://news.example/story","title":"Story"}}
+	]}`)
+
+	want := "@alice see %will-it-rain\n\n" +
+		"![chart](https://img.example/a.png)\n\n" +
+		"[embed](https://www.youtube.com/embed/xyz)\n\n" +
+		"[tweet](https://twitter.com/i/web/status/123)\n\n" +
+		"[Story](https://news.example/story)"
+	if got := tiptapToMarkdown(doc); got != want {
+		t.Errorf("unexpected markdown:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestTiptapToMarkdown_NestedLists(t *testing.T) {
+	doc := decodeDoc(t, `{"type":"doc","content":[
+		{"type":"orderedList","attrs":{"start":3},"content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"first"}]},
+				{"type":"bulletList","content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"inner"}]}]}
+				]}
+			]},
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}
+		]},
+		{"type":"blockquote","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}
+		]}
+	]}`)
+
+	want := "3. first\n   - inner\n4. second\n\n> quoted"
+	if got := tiptapToMarkdown(doc); got != want {
+		t.Errorf("unexpected markdown:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestTiptapToMarkdown_CodeBlock(t *testing.T) {
+	doc := decodeDoc(t, `{"type":"doc","content":[
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]}
+	]}`)
+
+	want := "```go\nx := 1\n```"
+	if got := tiptapToMarkdown(doc); got != want {
+		t.Errorf("unexpected markdown:\ngot:  %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,522 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// takerFeeConstant is Manifold's taker fee coefficient: buying a share at
+	// probability p costs an extra takerFeeConstant * p * (1-p) mana.
+	takerFeeConstant = 0.07
+	defaultMinEdge   = 0.01
+
+	linkImplies    = "implies"
+	linkExclusive  = "exclusive"
+	linkExhaustive = "exhaustive"
+	linkEquivalent = "equivalent"
+
+	arbKindSumOver  = "sum-to-one-over"
+	arbKindSumUnder = "sum-to-one-under"
+	arbKindSibling  = "sibling"
+
+	tokenMana = "MANA"
+)
+
+// arbLink is a user-supplied logical relationship between markets. Each
+// market reference is a market ID, or "marketId:answerId" for an answer.
+type arbLink struct {
+	Type    string   `json:"type"`
+	Markets []string `json:"markets"`
+}
+
+// arbLeg is one trade in an arbitrage: buying a share of Outcome in a market
+// or answer currently trading at Prob (the YES probability).
+type arbLeg struct {
+	ContractID string  `json:"contractId"`
+	AnswerID   string  `json:"answerId,omitempty"`
+	Label      string  `json:"label"`
+	Token      string  `json:"token,omitempty"`
+	Outcome    string  `json:"outcome"`
+	Prob       float64 `json:"prob"`
+	Price      float64 `json:"price"`
+}
+
+// arbOpportunity is a set of trades with a guaranteed minimum payout. All
+// amounts are per unit: buying one share on every leg. Edges use current
+// marginal prices, so slippage on larger trades will reduce them.
+type arbOpportunity struct {
+	Kind             string   `json:"kind"`
+	Description      string   `json:"description"`
+	Legs             []arbLeg `json:"legs"`
+	Cost             float64  `json:"cost"`
+	Fees             float64  `json:"fees"`
+	GuaranteedPayout float64  `json:"guaranteedPayout"`
+	Edge             float64  `json:"edge"`
+	EdgePct          float64  `json:"edgePct"`
+	// Caveat qualifies the edge, such as legs paying out in different tokens.
+	Caveat string `json:"caveat,omitempty"`
+}
+
+// arbitrageResponse is the JSON output for scan_arbitrage.
+type arbitrageResponse struct {
+	ScannedMarkets int              `json:"scannedMarkets"`
+	Opportunities  []arbOpportunity `json:"opportunities"`
+	Errors         []string         `json:"errors,omitempty"`
+}
+
+// pricedRef is a resolved market or answer reference with its current price.
+type pricedRef struct {
+	contractID string
+	answerID   string
+	label      string
+	token      string
+	prob       float64
+}
+
+func (p pricedRef) leg(outcome string) arbLeg {
+	price := p.prob
+	if outcome == outcomeNo {
+		price = 1 - p.prob
+	}
+	return arbLeg{
+		ContractID: p.contractID,
+		AnswerID:   p.answerID,
+		Label:      p.label,
+		Token:      p.token,
+		Outcome:    outcome,
+		Prob:       p.prob,
+		Price:      price,
+	}
+}
+
+// takerFee returns the fee for buying one share at YES probability p.
+func takerFee(p float64) float64 {
+	return takerFeeConstant * p * (1 - p)
+}
+
+// newOpportunity totals the cost and fees of legs against a guaranteed payout.
+func newOpportunity(kind, description string, legs []arbLeg, payout float64) arbOpportunity {
+	opp := arbOpportunity{
+		Kind:             kind,
+		Description:      description,
+		Legs:             legs,
+		GuaranteedPayout: payout,
+	}
+	for _, l := range legs {
+		opp.Cost += l.Price
+		opp.Fees += takerFee(l.Prob)
+	}
+	opp.Edge = payout - opp.Cost - opp.Fees
+	if opp.Cost > 0 {
+		opp.EdgePct = opp.Edge / opp.Cost * 100
+	}
+	return opp
+}
+
+func sumProbs(refs []pricedRef) float64 {
+	total := 0.0
+	for _, r := range refs {
+		total += r.prob
+	}
+	return total
+}
+
+func legsFor(refs []pricedRef, outcome string) []arbLeg {
+	legs := make([]arbLeg, len(refs))
+	for i, r := range refs {
+		legs[i] = r.leg(outcome)
+	}
+	return legs
+}
+
+// checkAtMostOne handles outcomes of which at most one can resolve YES. If
+// their probabilities sum above 1, buying NO on all of them pays at least n-1.
+func checkAtMostOne(kind, description string, refs []pricedRef) (arbOpportunity, bool) {
+	if len(refs) < 2 || sumProbs(refs) <= 1 {
+		return arbOpportunity{}, false
+	}
+	return newOpportunity(kind, description, legsFor(refs, outcomeNo), float64(len(refs)-1)), true
+}
+
+// checkAtLeastOne handles outcomes of which at least one must resolve YES. If
+// their probabilities sum below 1, buying YES on all of them pays at least 1.
+func checkAtLeastOne(kind, description string, refs []pricedRef) (arbOpportunity, bool) {
+	if len(refs) < 2 || sumProbs(refs) >= 1 {
+		return arbOpportunity{}, false
+	}
+	return newOpportunity(kind, description, legsFor(refs, outcomeYes), 1), true
+}
+
+// checkImplies handles A implies B, which requires P(A) <= P(B). If A trades
+// above B, buying NO on A and YES on B pays at least 1.
+func checkImplies(description string, a, b pricedRef) (arbOpportunity, bool) {
+	if a.prob <= b.prob {
+		return arbOpportunity{}, false
+	}
+	return newOpportunity(linkImplies, description, []arbLeg{a.leg(outcomeNo), b.leg(outcomeYes)}, 1), true
+}
+
+// checkEquivalent handles two outcomes that must resolve the same way. Buying
+// YES on the cheaper and NO on the dearer pays exactly 1.
+func checkEquivalent(kind, description string, a, b pricedRef) (arbOpportunity, bool) {
+	if a.prob == b.prob {
+		return arbOpportunity{}, false
+	}
+	if a.prob > b.prob {
+		a, b = b, a
+	}
+	return newOpportunity(kind, description, []arbLeg{a.leg(outcomeYes), b.leg(outcomeNo)}, 1), true
+}
+
+// scanSumToOne checks a multiple choice market whose answers must sum to one.
+func scanSumToOne(m *client.FullMarket) []arbOpportunity {
+	if m.IsResolved || m.OutcomeType != outcomeTypeMultipleChoice ||
+		m.ShouldAnswersSumToOne == nil || !*m.ShouldAnswersSumToOne {
+		return nil
+	}
+
+	var refs []pricedRef
+	for i := range m.Answers {
+		a := &m.Answers[i]
+		prob := a.CurrentProb()
+		if a.Resolution != nil || prob == nil {
+			continue
+		}
+		refs = append(refs, pricedRef{
+			contractID: m.ID, answerID: a.ID, label: a.Text, token: m.Token, prob: *prob,
+		})
+	}
+
+	sum := sumProbs(refs)
+	description := fmt.Sprintf("%q: answer probabilities sum to %.1f%%", m.Question, sum*100)
+	var opps []arbOpportunity
+	if opp, ok := checkAtMostOne(arbKindSumOver, description, refs); ok {
+		opps = append(opps, opp)
+	}
+	if opp, ok := checkAtLeastOne(arbKindSumUnder, description, refs); ok {
+		opps = append(opps, opp)
+	}
+	return opps
+}
+
+// scanSibling compares a market against its sibling contract, which should
+// trade at the same probability. Siblings are usually a mana market and its
+// cash twin; mana and cash can't be exchanged, so their divergence is
+// reported with a caveat rather than as one netted arbitrage.
+func scanSibling(m, sibling *client.FullMarket) (arbOpportunity, bool) {
+	if m.IsResolved || sibling.IsResolved || m.Probability == nil || sibling.Probability == nil {
+		return arbOpportunity{}, false
+	}
+	a, b := marketRef(m), marketRef(sibling)
+	description := fmt.Sprintf(
+		"%q trades at %.1f%% (%s) vs sibling at %.1f%% (%s)",
+		m.Question, a.prob*100, tokenOf(a.token), b.prob*100, tokenOf(b.token),
+	)
+	opp, ok := checkEquivalent(arbKindSibling, description, a, b)
+	if ok && tokenOf(a.token) != tokenOf(b.token) {
+		opp.Caveat = fmt.Sprintf(
+			"legs trade in %s and %s, which can't be exchanged, so each leg is paid out in its own token "+
+				"and the edge can't be netted as one arbitrage",
+			tokenOf(a.token), tokenOf(b.token),
+		)
+	}
+	return opp, ok
+}
+
+// tokenOf returns a market's token, treating an unset token as mana.
+func tokenOf(token string) string {
+	if token == "" {
+		return tokenMana
+	}
+	return token
+}
+
+func marketRef(m *client.FullMarket) pricedRef {
+	return pricedRef{contractID: m.ID, label: m.Question, token: m.Token, prob: *m.Probability}
+}
+
+// splitMarketRef splits "marketId:answerId" into its parts.
+func splitMarketRef(ref string) (marketID, answerID string) {
+	marketID, answerID, _ = strings.Cut(strings.TrimSpace(ref), ":")
+	return marketID, answerID
+}
+
+// resolveRef prices a market or answer reference against fetched markets.
+func resolveRef(markets map[string]*client.FullMarket, ref string) (pricedRef, error) {
+	marketID, answerID := splitMarketRef(ref)
+	m, ok := markets[marketID]
+	if !ok {
+		return pricedRef{}, fmt.Errorf("market %s not fetched", marketID)
+	}
+	if m.IsResolved {
+		return pricedRef{}, fmt.Errorf("market %s is resolved", marketID)
+	}
+	if answerID == "" {
+		if m.Probability == nil {
+			return pricedRef{}, fmt.Errorf("market %s has no probability; reference an answer as marketId:answerId", marketID)
+		}
+		return marketRef(m), nil
+	}
+	for i := range m.Answers {
+		a := &m.Answers[i]
+		if a.ID != answerID {
+			continue
+		}
+		prob := a.CurrentProb()
+		if prob == nil || a.Resolution != nil {
+			return pricedRef{}, fmt.Errorf("answer %s has no open probability", ref)
+		}
+		return pricedRef{
+			contractID: m.ID, answerID: a.ID, label: m.Question + ": " + a.Text, token: m.Token, prob: *prob,
+		}, nil
+	}
+	return pricedRef{}, fmt.Errorf("answer %s not found in market %s", answerID, marketID)
+}
+
+// scanLink evaluates a user-supplied relationship between markets.
+func scanLink(markets map[string]*client.FullMarket, link arbLink) (arbOpportunity, bool, error) {
+	refs := make([]pricedRef, 0, len(link.Markets))
+	for _, ref := range link.Markets {
+		r, err := resolveRef(markets, ref)
+		if err != nil {
+			return arbOpportunity{}, false, err
+		}
+		if len(refs) > 0 && tokenOf(r.token) != tokenOf(refs[0].token) {
+			return arbOpportunity{}, false, fmt.Errorf("link mixes %s and %s markets", tokenOf(refs[0].token), tokenOf(r.token))
+		}
+		refs = append(refs, r)
+	}
+
+	labels := make([]string, len(refs))
+	for i, r := range refs {
+		labels[i] = fmt.Sprintf("%q (%.1f%%)", r.label, r.prob*100)
+	}
+
+	switch link.Type {
+	case linkImplies, linkEquivalent:
+		if len(refs) != 2 {
+			return arbOpportunity{}, false, fmt.Errorf("%s link needs exactly 2 markets, got %d", link.Type, len(refs))
+		}
+		if link.Type == linkImplies {
+			opp, ok := checkImplies(labels[0]+" implies "+labels[1], refs[0], refs[1])
+			return opp, ok, nil
+		}
+		opp, ok := checkEquivalent(linkEquivalent, labels[0]+" is equivalent to "+labels[1], refs[0], refs[1])
+		return opp, ok, nil
+	case linkExclusive:
+		opp, ok := checkAtMostOne(linkExclusive, "at most one of "+strings.Join(labels, ", "), refs)
+		return opp, ok, nil
+	case linkExhaustive:
+		opp, ok := checkAtLeastOne(linkExhaustive, "at least one of "+strings.Join(labels, ", "), refs)
+		return opp, ok, nil
+	}
+	return arbOpportunity{}, false, fmt.Errorf("unknown link type %q", link.Type)
+}
+
+// parseArbLinks decodes the links argument, an array of link objects.
+func parseArbLinks(raw any) ([]arbLink, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid links: %w", err)
+	}
+	var links []arbLink
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("invalid links: %w", err)
+	}
+	return links, nil
+}
+
+// fetchFullMarkets fetches markets concurrently, returning those that could be
+// fetched and an error message for each that could not.
+func (s *Server) fetchFullMarkets(ctx context.Context, ids []string) (map[string]*client.FullMarket, []string) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, maxConcurrency)
+		markets = make(map[string]*client.FullMarket, len(ids))
+		errs    []string
+	)
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			m, err := s.client.GetMarket(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err.Error())
+				return
+			}
+			markets[id] = m
+		}()
+	}
+	wg.Wait()
+	sort.Strings(errs)
+	return markets, errs
+}
+
+// arbitrageCandidates collects the market IDs to scan from explicit IDs and
+// an optional search.
+func (s *Server) arbitrageCandidates(ctx context.Context, args map[string]any) ([]string, error) {
+	var ids []string
+	if v, ok := args["marketIds"].(string); ok && v != "" {
+		ids = splitIDs(v)
+	}
+
+	params := url.Values{}
+	setOptionalString(params, args, "term")
+	setOptionalString(params, args, "topicSlug")
+	if len(params) > 0 {
+		params.Set("filter", "open")
+		setOptionalLimit(params, args)
+		markets, err := s.client.SearchMarkets(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range markets {
+			if m.OutcomeType == outcomeTypeMultipleChoice || m.SiblingContractID != nil {
+				ids = append(ids, m.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// linkMarketIDs returns the IDs of markets referenced by links that are not
+// already in have.
+func linkMarketIDs(links []arbLink, have map[string]*client.FullMarket) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, l := range links {
+		for _, ref := range l.Markets {
+			id, _ := splitMarketRef(ref)
+			if _, ok := have[id]; ok || seen[id] || id == "" {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// siblingIDs returns the sibling contract IDs of markets not already in markets.
+func siblingIDs(markets map[string]*client.FullMarket) []string {
+	var ids []string
+	for _, m := range markets {
+		if m.SiblingContractID == nil {
+			continue
+		}
+		if _, ok := markets[*m.SiblingContractID]; !ok {
+			ids = append(ids, *m.SiblingContractID)
+		}
+	}
+	return ids
+}
+
+func mergeMarkets(dst, src map[string]*client.FullMarket) {
+	for id, m := range src {
+		dst[id] = m
+	}
+}
+
+// scanMarkets runs every check over the fetched markets and links.
+func scanMarkets(
+	markets map[string]*client.FullMarket, candidates []string, links []arbLink,
+) ([]arbOpportunity, []string) {
+	var opps []arbOpportunity
+	var errs []string
+	seenSibling := map[string]bool{}
+
+	for _, id := range candidates {
+		m, ok := markets[id]
+		if !ok {
+			continue
+		}
+		opps = append(opps, scanSumToOne(m)...)
+
+		if m.SiblingContractID == nil || seenSibling[m.ID] {
+			continue
+		}
+		sibling, ok := markets[*m.SiblingContractID]
+		if !ok {
+			continue
+		}
+		seenSibling[m.ID], seenSibling[sibling.ID] = true, true
+		if opp, ok := scanSibling(m, sibling); ok {
+			opps = append(opps, opp)
+		}
+	}
+
+	for _, l := range links {
+		opp, ok, err := scanLink(markets, l)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if ok {
+			opps = append(opps, opp)
+		}
+	}
+	return opps, errs
+}
+
+func (s *Server) handleScanArbitrage(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	links, err := parseArbLinks(args["links"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	minEdge := defaultMinEdge
+	if v, ok := args["minEdge"].(float64); ok {
+		minEdge = v
+	}
+
+	candidates, err := s.arbitrageCandidates(ctx, args)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search markets: %v", err)), nil
+	}
+	if len(candidates) == 0 && len(links) == 0 {
+		return mcp.NewToolResultError("provide marketIds, a search term or topicSlug, or links to scan"), nil
+	}
+
+	markets, errs := s.fetchFullMarkets(ctx, candidates)
+	more, moreErrs := s.fetchFullMarkets(ctx, append(siblingIDs(markets), linkMarketIDs(links, markets)...))
+	mergeMarkets(markets, more)
+	errs = append(errs, moreErrs...)
+
+	opps, scanErrs := scanMarkets(markets, candidates, links)
+	errs = append(errs, scanErrs...)
+
+	resp := arbitrageResponse{ScannedMarkets: len(markets), Errors: errs}
+	for _, o := range opps {
+		if o.Edge >= minEdge {
+			resp.Opportunities = append(resp.Opportunities, o)
+		}
+	}
+	sort.Slice(resp.Opportunities, func(i, j int) bool {
+		return resp.Opportunities[i].Edge > resp.Opportunities[j].Edge
+	})
+
+	return newStructuredResult(fmt.Sprintf("Scanned %d market(s), found %d opportunity(ies).",
+		resp.ScannedMarkets, len(resp.Opportunities)), resp)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func TestScanSumToOne_Overpriced(t *testing.T) {
+	m := &client.FullMarket{
+		LiteMarket: client.LiteMarket{ID: "m", Question: "Who?", OutcomeType: "MULTIPLE_CHOICE"},
+		Answers: []client.Answer{
+			{ID: "a", Text: "A", Probability: floatPtr(0.5)},
+			{ID: "b", Text: "B", Probability: floatPtr(0.4)},
+			{ID: "c", Text: "C", Probability: floatPtr(0.3)},
+		},
+		ShouldAnswersSumToOne: boolPtr(true),
+	}
+
+	opps := scanSumToOne(m)
+	if len(opps) != 1 {
+		t.Fatalf("expected 1 opportunity, got %d", len(opps))
+	}
+	opp := opps[0]
+	if opp.Kind != arbKindSumOver {
+		t.Errorf("expected kind %s, got %s", arbKindSumOver, opp.Kind)
+	}
+	for _, l := range opp.Legs {
+		if l.Outcome != outcomeNo {
+			t.Errorf("expected NO on every answer, got %s on %s", l.Outcome, l.AnswerID)
+		}
+	}
+	// Cost 0.5+0.6+0.7 = 1.8, payout 2, fees 0.07*(0.25+0.24+0.21) = 0.049.
+	if math.Abs(opp.Cost-1.8) > 1e-9 || opp.GuaranteedPayout != 2 {
+		t.Errorf("unexpected cost/payout: %f/%f", opp.Cost, opp.GuaranteedPayout)
+	}
+	if math.Abs(opp.Edge-(0.2-0.049)) > 1e-9 {
+		t.Errorf("expected edge 0.151, got %f", opp.Edge)
+	}
+}
+
+func TestScanSumToOne_SkipsIndependentAnswers(t *testing.T) {
+	m := &client.FullMarket{
+		LiteMarket: client.LiteMarket{ID: "m", OutcomeType: "MULTIPLE_CHOICE"},
+		Answers: []client.Answer{
+			{ID: "a", Probability: floatPtr(0.9)},
+			{ID: "b", Probability: floatPtr(0.9)},
+		},
+		ShouldAnswersSumToOne: boolPtr(false),
+	}
+	if opps := scanSumToOne(m); len(opps) != 0 {
+		t.Errorf("expected no opportunities for independent answers, got %d", len(opps))
+	}
+}
+
+func TestScanSibling(t *testing.T) {
+	sibID := "cash"
+	mana := &client.FullMarket{LiteMarket: client.LiteMarket{
+		ID: "mana", Probability: floatPtr(0.40),
+		LiteMarketExtraFields: client.LiteMarketExtraFields{Token: "MANA", SiblingContractID: &sibID},
+	}}
+	cash := &client.FullMarket{LiteMarket: client.LiteMarket{
+		ID: "cash", Probability: floatPtr(0.55),
+		LiteMarketExtraFields: client.LiteMarketExtraFields{Token: "CASH"},
+	}}
+
+	opp, ok := scanSibling(mana, cash)
+	if !ok {
+		t.Fatal("expected a sibling opportunity")
+	}
+	if opp.Legs[0].ContractID != "mana" || opp.Legs[0].Outcome != outcomeYes {
+		t.Errorf("expected YES on the cheaper contract, got %+v", opp.Legs[0])
+	}
+	if opp.Legs[1].ContractID != "cash" || opp.Legs[1].Outcome != outcomeNo {
+		t.Errorf("expected NO on the dearer contract, got %+v", opp.Legs[1])
+	}
+	if math.Abs(opp.Cost-0.85) > 1e-9 {
+		t.Errorf("expected cost 0.85, got %f", opp.Cost)
+	}
+	if !strings.Contains(opp.Caveat, "MANA and CASH") {
+		t.Errorf("expected a token caveat, got %q", opp.Caveat)
+	}
+}
+
+func TestScanLink_MixedTokens(t *testing.T) {
+	markets := map[string]*client.FullMarket{
+		"a": {LiteMarket: client.LiteMarket{ID: "a", Probability: floatPtr(0.6)}},
+		"b": {LiteMarket: client.LiteMarket{
+			ID: "b", Probability: floatPtr(0.3),
+			LiteMarketExtraFields: client.LiteMarketExtraFields{Token: "CASH"},
+		}},
+	}
+
+	if _, _, err := scanLink(markets, arbLink{Type: linkEquivalent, Markets: []string{"a", "b"}}); err == nil {
+		t.Error("expected an error linking MANA and CASH markets")
+	}
+}
+
+func TestScanLink_Implies(t *testing.T) {
+	markets := map[string]*client.FullMarket{
+		"a": {LiteMarket: client.LiteMarket{ID: "a", Question: "Wins primary?", Probability: floatPtr(0.6)}},
+		"b": {
+			LiteMarket: client.LiteMarket{ID: "b", Question: "Party nominee?"},
+			Answers:    []client.Answer{{ID: "x", Text: "X", Probability: floatPtr(0.5)}},
+		},
+	}
+
+	opp, ok, err := scanLink(markets, arbLink{Type: linkImplies, Markets: []string{"a", "b:x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected an implication violation")
+	}
+	if opp.Legs[0].Outcome != outcomeNo || opp.Legs[1].Outcome != outcomeYes || opp.Legs[1].AnswerID != "x" {
+		t.Errorf("expected NO on antecedent and YES on answer x, got %+v", opp.Legs)
+	}
+	if math.Abs(opp.Cost-0.9) > 1e-9 {
+		t.Errorf("expected cost 0.9, got %f", opp.Cost)
+	}
+
+	// Consistent prices produce no opportunity.
+	_, ok, err = scanLink(markets, arbLink{Type: linkImplies, Markets: []string{"b:x", "a"}})
+	if err != nil || ok {
+		t.Errorf("expected no opportunity, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestScanLink_Errors(t *testing.T) {
+	markets := map[string]*client.FullMarket{
+		"a": {LiteMarket: client.LiteMarket{ID: "a", Probability: floatPtr(0.6)}},
+	}
+	cases := []arbLink{
+		{Type: linkImplies, Markets: []string{"a"}},
+		{Type: linkExclusive, Markets: []string{"a", "missing"}},
+		{Type: "bogus", Markets: []string{"a", "a"}},
+	}
+	for _, l := range cases {
+		if _, _, err := scanLink(markets, l); err == nil {
+			t.Errorf("expected error for %+v", l)
+		}
+	}
+}
+
+func TestParseArbLinks(t *testing.T) {
+	raw := []any{map[string]any{"type": "exclusive", "markets": []any{"a", "b"}}}
+	links, err := parseArbLinks(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0].Type != linkExclusive || len(links[0].Markets) != 2 {
+		t.Errorf("unexpected links: %+v", links)
+	}
+}
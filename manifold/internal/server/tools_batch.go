@@ -0,0 +1,432 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	outcomeTypePoll         = "POLL"
+	outcomeTypeFreeResponse = "FREE_RESPONSE"
+	outcomeTypeBounty       = "BOUNTY"
+	outcomeTypeNumber       = "NUMBER"
+
+	// Manifold charges the creator an ante that seeds a new market's
+	// liquidity pool. Answer-based markets cost at least answerAnte per
+	// starting answer, polls have no pool, and bounties cost the bounty.
+	marketAnte    = 100.0
+	answerAnte    = 25.0
+	pollAnte      = 10.0
+	minimumBounty = 50.0
+)
+
+// templateVar matches a {{name}} placeholder in a batch template.
+var templateVar = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// batchMarketSpec is one market in a create_markets_batch request. Fields
+// left empty fall back to the batch-level defaults and templates.
+type batchMarketSpec struct {
+	Question    string            `json:"question"`
+	OutcomeType string            `json:"outcomeType"`
+	Description string            `json:"description"`
+	CloseTime   *int64            `json:"closeTime"`
+	CloseDate   string            `json:"closeDate"`
+	InitialProb *float64          `json:"initialProb"`
+	Min         *float64          `json:"min"`
+	Max         *float64          `json:"max"`
+	IsLogScale  *bool             `json:"isLogScale"`
+	Answers     []string          `json:"answers"`
+	Topics      []string          `json:"topics"`
+	Vars        map[string]string `json:"vars"`
+}
+
+// batchDefaults holds the batch-level settings shared by every spec.
+type batchDefaults struct {
+	outcomeType         string
+	questionTemplate    string
+	descriptionTemplate string
+	closeDateTemplate   string
+	topics              []string
+}
+
+// batchPlan is a validated market ready to be created.
+type batchPlan struct {
+	req    client.CreateMarketRequest
+	topics []string
+}
+
+// renderTemplate substitutes {{name}} placeholders from vars, failing on any
+// placeholder without a value.
+func renderTemplate(tmpl string, vars map[string]string) (string, error) {
+	var missing []string
+	out := templateVar.ReplaceAllStringFunc(tmpl, func(m string) string {
+		name := templateVar.FindStringSubmatch(m)[1]
+		v, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template variable(s) not set: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// parseCloseDate parses a close time given as an RFC 3339 timestamp, a date
+// (closing at the end of that day, UTC), or an offset from now such as "+30d",
+// "+2w", or "+12h".
+func parseCloseDate(s string, now time.Time) (int64, error) {
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "+"); ok && len(rest) > 1 {
+		n, err := strconv.Atoi(rest[:len(rest)-1])
+		if err == nil && n > 0 {
+			switch rest[len(rest)-1] {
+			case 'h':
+				return now.Add(time.Duration(n) * time.Hour).UnixMilli(), nil
+			case 'd':
+				return now.AddDate(0, 0, n).UnixMilli(), nil
+			case 'w':
+				return now.AddDate(0, 0, 7*n).UnixMilli(), nil
+			}
+		}
+		return 0, fmt.Errorf("invalid relative close date %q (expected e.g. +30d, +2w, +12h)", s)
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UnixMilli(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.Add(24*time.Hour - time.Second).UnixMilli(), nil
+	}
+	return 0, fmt.Errorf("invalid close date %q (expected YYYY-MM-DD, RFC 3339, or +Nd/+Nw/+Nh)", s)
+}
+
+// validateMarketShape checks the type-specific fields of a market request.
+func validateMarketShape(req *client.CreateMarketRequest) []string {
+	var errs []string
+	hasAnswers := len(req.Answers) > 0
+	switch req.OutcomeType {
+	case outcomeTypeBinary:
+		if req.InitialProb != nil && (*req.InitialProb < 1 || *req.InitialProb > 99) {
+			errs = append(errs, "initialProb must be between 1 and 99")
+		}
+	case outcomeTypeMultipleChoice, outcomeTypePoll:
+		if len(req.Answers) < 2 {
+			errs = append(errs, req.OutcomeType+" markets need at least 2 answers")
+		}
+		seen := map[string]bool{}
+		for _, a := range req.Answers {
+			switch {
+			case strings.TrimSpace(a) == "":
+				errs = append(errs, "answers must not be empty")
+			case seen[a]:
+				errs = append(errs, fmt.Sprintf("duplicate answer %q", a))
+			}
+			seen[a] = true
+		}
+		hasAnswers = false
+	case outcomeTypePseudoNumeric, outcomeTypeNumber:
+		if req.Min == nil || req.Max == nil {
+			errs = append(errs, req.OutcomeType+" markets need min and max")
+		} else if *req.Min >= *req.Max {
+			errs = append(errs, "min must be less than max")
+		}
+	case outcomeTypeFreeResponse, outcomeTypeBounty:
+	default:
+		errs = append(errs, fmt.Sprintf("unknown outcomeType %q", req.OutcomeType))
+	}
+	if hasAnswers {
+		errs = append(errs, "answers only apply to MULTIPLE_CHOICE and POLL markets")
+	}
+	return errs
+}
+
+// planBatchMarkets resolves each spec against the batch defaults and
+// validates it, returning every problem found so none are created unless all
+// are valid.
+func planBatchMarkets(specs []batchMarketSpec, defaults batchDefaults, now time.Time) ([]batchPlan, []string) {
+	if len(specs) == 0 {
+		return nil, []string{"markets must contain at least one market"}
+	}
+
+	var errs []string
+	plans := make([]batchPlan, 0, len(specs))
+	questions := map[string]int{}
+	for i, spec := range specs {
+		fail := func(format string, a ...any) {
+			errs = append(errs, fmt.Sprintf("market %d: ", i+1)+fmt.Sprintf(format, a...))
+		}
+
+		req := client.CreateMarketRequest{
+			OutcomeType: firstNonEmpty(spec.OutcomeType, defaults.outcomeType),
+			Question:    spec.Question,
+			Description: spec.Description,
+			CloseTime:   spec.CloseTime,
+			InitialProb: spec.InitialProb,
+			Min:         spec.Min,
+			Max:         spec.Max,
+			IsLogScale:  spec.IsLogScale,
+			Answers:     spec.Answers,
+		}
+		vars := spec.Vars
+
+		if req.Question == "" && defaults.questionTemplate != "" {
+			q, err := renderTemplate(defaults.questionTemplate, vars)
+			if err != nil {
+				fail("question: %v", err)
+			}
+			req.Question = q
+		}
+		req.Question = strings.TrimSpace(req.Question)
+		if req.Question == "" {
+			fail("question is required")
+		} else if j, dup := questions[req.Question]; dup {
+			fail("duplicate question (same as market %d)", j+1)
+		} else {
+			questions[req.Question] = i
+		}
+
+		if req.Description == "" && defaults.descriptionTemplate != "" {
+			withQuestion := map[string]string{"question": req.Question}
+			for k, v := range vars {
+				withQuestion[k] = v
+			}
+			d, err := renderTemplate(defaults.descriptionTemplate, withQuestion)
+			if err != nil {
+				fail("description: %v", err)
+			}
+			req.Description = d
+		}
+
+		if req.CloseTime == nil {
+			closeDate := spec.CloseDate
+			if closeDate == "" && defaults.closeDateTemplate != "" {
+				var err error
+				if closeDate, err = renderTemplate(defaults.closeDateTemplate, vars); err != nil {
+					fail("close date: %v", err)
+				}
+			}
+			if closeDate != "" {
+				if t, err := parseCloseDate(closeDate, now); err != nil {
+					fail("%v", err)
+				} else {
+					req.CloseTime = &t
+				}
+			}
+		}
+		if req.CloseTime != nil && *req.CloseTime <= now.UnixMilli() {
+			fail("close time is in the past")
+		}
+
+		if req.OutcomeType == "" {
+			fail("outcomeType is required")
+		} else {
+			for _, e := range validateMarketShape(&req) {
+				fail("%s", e)
+			}
+		}
+
+		plans = append(plans, batchPlan{
+			req:    req,
+			topics: mergeTopics(defaults.topics, spec.Topics),
+		})
+	}
+	return plans, errs
+}
+
+// mergeTopics combines topic slugs, dropping blanks and duplicates.
+func mergeTopics(lists ...[]string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, slug := range list {
+			slug = strings.TrimSpace(slug)
+			if slug != "" && !seen[slug] {
+				seen[slug] = true
+				out = append(out, slug)
+			}
+		}
+	}
+	return out
+}
+
+// resolveTopicIDs looks up the group ID for every topic slug used by the plans.
+func (s *Server) resolveTopicIDs(ctx context.Context, plans []batchPlan) []string {
+	ids := map[string]string{}
+	var errs []string
+	for _, p := range plans {
+		for _, slug := range p.topics {
+			if _, done := ids[slug]; done {
+				continue
+			}
+			group, err := s.client.GetGroup(ctx, slug)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("topic %s: %v", slug, err))
+				ids[slug] = ""
+				continue
+			}
+			ids[slug] = group.ID
+		}
+	}
+	for i := range plans {
+		for _, slug := range plans[i].topics {
+			if ids[slug] != "" {
+				plans[i].req.GroupIDs = append(plans[i].req.GroupIDs, ids[slug])
+			}
+		}
+	}
+	return errs
+}
+
+// batchMarketResult is the outcome (or preview) of creating one market.
+type batchMarketResult struct {
+	Index         int      `json:"index"`
+	Question      string   `json:"question"`
+	OutcomeType   string   `json:"outcomeType"`
+	CloseTime     *int64   `json:"closeTime,omitempty"`
+	Answers       []string `json:"answers,omitempty"`
+	Topics        []string `json:"topics,omitempty"`
+	ID            string   `json:"id,omitempty"`
+	URL           string   `json:"url,omitempty"`
+	EstimatedCost float64  `json:"estimatedCost"`
+	Cost          *float64 `json:"cost,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// batchCreateResponse is the JSON output for create_markets_batch.
+type batchCreateResponse struct {
+	DryRun             bool                `json:"dryRun"`
+	Created            int                 `json:"created"`
+	Failed             int                 `json:"failed"`
+	EstimatedTotalCost float64             `json:"estimatedTotalCost"`
+	TotalCost          *float64            `json:"totalCost,omitempty"`
+	Markets            []batchMarketResult `json:"markets"`
+}
+
+// estimateCreationCost returns the mana creating a market is expected to
+// cost: the ante for its type and number of answers.
+func estimateCreationCost(req client.CreateMarketRequest) float64 {
+	switch req.OutcomeType {
+	case outcomeTypePoll:
+		return pollAnte
+	case outcomeTypeBounty:
+		return minimumBounty
+	case outcomeTypeMultipleChoice, outcomeTypeFreeResponse:
+		return max(marketAnte, answerAnte*float64(len(req.Answers)))
+	}
+	return marketAnte
+}
+
+// createBatch creates the planned markets in order, continuing past failures.
+// Costs are measured from the change in the user's balance, so they include
+// any fees or liquidity the API charges.
+func (s *Server) createBatch(ctx context.Context, plans []batchPlan, results []batchMarketResult) *float64 {
+	before, err := s.client.GetMe(ctx)
+	balance := 0.0
+	haveBalance := err == nil
+	if haveBalance {
+		balance = before.Balance
+	}
+	start := balance
+
+	for i, p := range plans {
+		market, err := s.client.CreateMarket(ctx, p.req)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].ID, results[i].URL = market.ID, market.URL
+
+		if !haveBalance {
+			continue
+		}
+		me, err := s.client.GetMe(ctx)
+		if err != nil {
+			haveBalance = false
+			continue
+		}
+		cost := balance - me.Balance
+		results[i].Cost = &cost
+		balance = me.Balance
+	}
+
+	if !haveBalance {
+		return nil
+	}
+	total := start - balance
+	return &total
+}
+
+func (s *Server) handleCreateMarketsBatch(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	data, err := json.Marshal(args["markets"])
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid markets: %v", err)), nil
+	}
+	var specs []batchMarketSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid markets: %v", err)), nil
+	}
+
+	defaults := batchDefaults{}
+	defaults.outcomeType, _ = args["outcomeType"].(string)
+	defaults.questionTemplate, _ = args["questionTemplate"].(string)
+	defaults.descriptionTemplate, _ = args["descriptionTemplate"].(string)
+	defaults.closeDateTemplate, _ = args["closeDate"].(string)
+	if v, ok := args["topics"].(string); ok {
+		defaults.topics = splitIDs(v)
+	}
+	dryRun, _ := args["dryRun"].(bool)
+
+	plans, errs := planBatchMarkets(specs, defaults, time.Now())
+	if len(errs) == 0 {
+		errs = s.resolveTopicIDs(ctx, plans)
+	}
+	if len(errs) > 0 {
+		return mcp.NewToolResultError(
+			"no markets created; invalid batch:\n- " + strings.Join(errs, "\n- ")), nil
+	}
+
+	resp := batchCreateResponse{DryRun: dryRun, Markets: make([]batchMarketResult, len(plans))}
+	for i, p := range plans {
+		resp.Markets[i] = batchMarketResult{
+			Index:       i + 1,
+			Question:    p.req.Question,
+			OutcomeType: p.req.OutcomeType,
+			CloseTime:   p.req.CloseTime,
+			Answers:     p.req.Answers,
+			Topics:      p.topics,
+		}
+		resp.Markets[i].EstimatedCost = estimateCreationCost(p.req)
+		resp.EstimatedTotalCost += resp.Markets[i].EstimatedCost
+	}
+	if !dryRun {
+		resp.TotalCost = s.createBatch(ctx, plans, resp.Markets)
+		for _, m := range resp.Markets {
+			if m.Error != "" {
+				resp.Failed++
+			} else {
+				resp.Created++
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("Created %d market(s), %d failed.", resp.Created, resp.Failed)
+	if resp.DryRun {
+		summary = fmt.Sprintf("Validated %d market(s) (dry run), estimated cost %.0f mana.",
+			len(resp.Markets), resp.EstimatedTotalCost)
+	}
+	return newStructuredResult(summary, resp)
+}
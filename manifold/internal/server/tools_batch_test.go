@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	got, err := renderTemplate("Will {{ team }} win in {{year}}?", map[string]string{"team": "Ajax", "year": "2027"})
+	if err != nil || got != "Will Ajax win in 2027?" {
+		t.Errorf("unexpected render %q, err %v", got, err)
+	}
+	if _, err := renderTemplate("{{a}} {{b}}", map[string]string{"a": "x"}); err == nil ||
+		!strings.Contains(err.Error(), "b") {
+		t.Errorf("expected missing variable b, got %v", err)
+	}
+}
+
+func TestParseCloseDate(t *testing.T) {
+	now := time.Date(2027, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2027-04-30", time.Date(2027, 4, 30, 23, 59, 59, 0, time.UTC)},
+		{"2027-04-30T09:00:00Z", time.Date(2027, 4, 30, 9, 0, 0, 0, time.UTC)},
+		{"+30d", now.AddDate(0, 0, 30)},
+		{"+2w", now.AddDate(0, 0, 14)},
+		{"+12h", now.Add(12 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := parseCloseDate(tt.in, now)
+		if err != nil || got != tt.want.UnixMilli() {
+			t.Errorf("parseCloseDate(%q) = %v, %v; want %v", tt.in, time.UnixMilli(got).UTC(), err, tt.want)
+		}
+	}
+	for _, bad := range []string{"next week", "+d", "+3y", "30/04/2027"} {
+		if _, err := parseCloseDate(bad, now); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPlanBatchMarkets_Templates(t *testing.T) {
+	now := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaults := batchDefaults{
+		outcomeType:         outcomeTypeBinary,
+		questionTemplate:    "Will inflation exceed 3% in {{month}}?",
+		descriptionTemplate: "Resolves per the {{month}} CPI release. Question: {{question}}",
+		closeDateTemplate:   "{{month}}-28",
+		topics:              []string{"economics"},
+	}
+	specs := []batchMarketSpec{
+		{Vars: map[string]string{"month": "2027-02"}, Topics: []string{"inflation", "economics"}},
+		{
+			Question:    "Which party, if any, will win?",
+			OutcomeType: outcomeTypeMultipleChoice,
+			Answers:     []string{"Labour, with a majority", "Conservative", "Other"},
+			CloseDate:   "+30d",
+			Vars:        map[string]string{"month": "2027-03"},
+		},
+	}
+
+	plans, errs := planBatchMarkets(specs, defaults, now)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	first := plans[0].req
+	if first.Question != "Will inflation exceed 3% in 2027-02?" {
+		t.Errorf("unexpected question %q", first.Question)
+	}
+	if first.Description != "Resolves per the 2027-02 CPI release. Question: "+first.Question {
+		t.Errorf("unexpected description %q", first.Description)
+	}
+	if *first.CloseTime != time.Date(2027, 2, 28, 23, 59, 59, 0, time.UTC).UnixMilli() {
+		t.Errorf("unexpected close time %v", time.UnixMilli(*first.CloseTime).UTC())
+	}
+	if len(plans[0].topics) != 2 || plans[0].topics[0] != "economics" || plans[0].topics[1] != "inflation" {
+		t.Errorf("unexpected topics %v", plans[0].topics)
+	}
+
+	second := plans[1].req
+	if second.OutcomeType != outcomeTypeMultipleChoice || len(second.Answers) != 3 ||
+		second.Answers[0] != "Labour, with a majority" {
+		t.Errorf("expected answers with commas to be kept intact, got %+v", second)
+	}
+	if *second.CloseTime != now.AddDate(0, 0, 30).UnixMilli() {
+		t.Errorf("expected spec close date to override the template")
+	}
+}
+
+func TestPlanBatchMarkets_ReportsAllErrors(t *testing.T) {
+	now := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	specs := []batchMarketSpec{
+		{Question: "Fine?", OutcomeType: outcomeTypeBinary},
+		{Question: "Fine?", OutcomeType: outcomeTypeBinary, InitialProb: floatPtr(0.5)},
+		{Question: "Which?", OutcomeType: outcomeTypeMultipleChoice, Answers: []string{"A", "A"}},
+		{Question: "How many?", OutcomeType: outcomeTypePseudoNumeric, Min: floatPtr(10), Max: floatPtr(1)},
+		{Question: "Late?", OutcomeType: outcomeTypeBinary, CloseDate: "2026-01-01"},
+		{OutcomeType: outcomeTypeBinary, Answers: []string{"x", "y"}},
+		{Question: "How high?", OutcomeType: outcomeTypeNumber},
+		{Question: "How low?", OutcomeType: outcomeTypeNumber, Min: floatPtr(5), Max: floatPtr(5)},
+	}
+
+	_, errs := planBatchMarkets(specs, batchDefaults{}, now)
+	wants := []string{
+		"market 2: duplicate question",
+		"market 2: initialProb",
+		"market 3: duplicate answer",
+		"market 4: min must be less than max",
+		"market 5: close time is in the past",
+		"market 6: question is required",
+		"market 6: answers only apply",
+		"market 7: NUMBER markets need min and max",
+		"market 8: min must be less than max",
+	}
+	if len(errs) != len(wants) {
+		t.Fatalf("expected %d errors, got %d: %v", len(wants), len(errs), errs)
+	}
+	for i, want := range wants {
+		if !strings.HasPrefix(errs[i], want) {
+			t.Errorf("error %d: expected prefix %q, got %q", i, want, errs[i])
+		}
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	balance := 1000.0
+	var created []client.CreateMarketRequest
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/me":
+			_ = json.NewEncoder(w).Encode(client.User{ID: "me", Balance: balance})
+		case "/v0/group/economics":
+			_ = json.NewEncoder(w).Encode(client.Group{ID: "g1", Slug: "economics"})
+		case "/v0/market":
+			var req client.CreateMarketRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Question == "Rejected?" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			created = append(created, req)
+			balance -= 100
+			_ = json.NewEncoder(w).Encode(client.LiteMarket{ID: "m" + req.Question, URL: "u"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	s := &Server{client: client.NewClient(api.URL, "key")}
+	plans := []batchPlan{
+		{req: client.CreateMarketRequest{OutcomeType: outcomeTypeBinary, Question: "A?"}, topics: []string{"economics"}},
+		{req: client.CreateMarketRequest{OutcomeType: outcomeTypeBinary, Question: "Rejected?"}},
+		{req: client.CreateMarketRequest{OutcomeType: outcomeTypeBinary, Question: "B?"}},
+	}
+	if errs := s.resolveTopicIDs(context.Background(), plans); len(errs) != 0 {
+		t.Fatalf("unexpected topic errors: %v", errs)
+	}
+
+	results := make([]batchMarketResult, len(plans))
+	total := s.createBatch(context.Background(), plans, results)
+	if len(created) != 2 || len(created[0].GroupIDs) != 1 || created[0].GroupIDs[0] != "g1" {
+		t.Fatalf("unexpected created markets %+v", created)
+	}
+	if results[0].ID != "mA?" || results[1].Error == "" || results[2].ID != "mB?" {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if results[0].Cost == nil || *results[0].Cost != 100 || total == nil || *total != 200 {
+		t.Errorf("expected 100 per market and 200 total, got %v / %v", results[0].Cost, total)
+	}
+
+	missing := []batchPlan{{topics: []string{"nope"}}}
+	if errs := s.resolveTopicIDs(context.Background(), missing); len(errs) != 1 {
+		t.Errorf("expected an error for an unknown topic, got %v", errs)
+	}
+}
+
+func TestEstimateCreationCost(t *testing.T) {
+	tests := []struct {
+		req  client.CreateMarketRequest
+		want float64
+	}{
+		{client.CreateMarketRequest{OutcomeType: outcomeTypeBinary}, marketAnte},
+		{client.CreateMarketRequest{OutcomeType: outcomeTypePseudoNumeric}, marketAnte},
+		{client.CreateMarketRequest{OutcomeType: outcomeTypeMultipleChoice, Answers: []string{"a", "b"}}, marketAnte},
+		{client.CreateMarketRequest{OutcomeType: outcomeTypeMultipleChoice, Answers: make([]string, 8)}, 8 * answerAnte},
+		{client.CreateMarketRequest{OutcomeType: outcomeTypePoll, Answers: []string{"a", "b"}}, pollAnte},
+		{client.CreateMarketRequest{OutcomeType: outcomeTypeBounty}, minimumBounty},
+	}
+	for _, tt := range tests {
+		if got := estimateCreationCost(tt.req); got != tt.want {
+			t.Errorf("%s with %d answers: expected %.0f, got %.0f", tt.req.OutcomeType, len(tt.req.Answers), tt.want, got)
+		}
+	}
+}
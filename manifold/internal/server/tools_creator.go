@@ -0,0 +1,387 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultClosingWithinHours = 48
+	creatorCommentLimit       = 200
+	searchPageLimit           = 1000
+	maxSearchResults          = 5000
+
+	actionExtendClose = "extend_close"
+	actionResolveNA   = "resolve_na"
+
+	resolutionCancel = "CANCEL"
+)
+
+// creatorMarket is a market in the creator dashboard.
+type creatorMarket struct {
+	ID                 string  `json:"id"`
+	Question           string  `json:"question"`
+	URL                string  `json:"url"`
+	OutcomeType        string  `json:"outcomeType"`
+	CloseTime          *int64  `json:"closeTime,omitempty"`
+	IsResolved         bool    `json:"isResolved"`
+	Resolution         *string `json:"resolution,omitempty"`
+	Volume             float64 `json:"volume"`
+	Volume24Hours      float64 `json:"volume24Hours"`
+	BettorCount        int     `json:"bettorCount"`
+	Overdue            bool    `json:"overdue"`
+	ClosingSoon        bool    `json:"closingSoon"`
+	UnansweredComments int     `json:"unansweredComments"`
+}
+
+// creatorSummary totals the dashboard.
+type creatorSummary struct {
+	Total              int `json:"total"`
+	Open               int `json:"open"`
+	Overdue            int `json:"overdue"`
+	ClosingSoon        int `json:"closingSoon"`
+	Resolved           int `json:"resolved"`
+	UnansweredComments int `json:"unansweredComments"`
+}
+
+// creatorAction is the outcome (or preview) of a bulk action on one market.
+type creatorAction struct {
+	MarketID string `json:"marketId"`
+	Question string `json:"question,omitempty"`
+	Action   string `json:"action"`
+	Detail   string `json:"detail"`
+	DryRun   bool   `json:"dryRun"`
+	Error    string `json:"error,omitempty"`
+}
+
+// creatorDashboardResponse is the JSON output for get_creator_dashboard.
+type creatorDashboardResponse struct {
+	Summary creatorSummary  `json:"summary"`
+	Markets []creatorMarket `json:"markets"`
+	Actions []creatorAction `json:"actions,omitempty"`
+	// Truncated is set when only the newest maxSearchResults markets were
+	// fetched, so the summary doesn't cover older ones.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// classifyCreatorMarket builds the dashboard entry for a market, flagging it
+// as overdue (past close and unresolved) or closing soon.
+func classifyCreatorMarket(m *client.LiteMarket, now time.Time, closingWithin time.Duration) creatorMarket {
+	cm := creatorMarket{
+		ID:            m.ID,
+		Question:      m.Question,
+		URL:           m.URL,
+		OutcomeType:   m.OutcomeType,
+		CloseTime:     m.CloseTime,
+		IsResolved:    m.IsResolved,
+		Resolution:    m.Resolution,
+		Volume:        m.Volume,
+		Volume24Hours: m.Volume24Hours,
+	}
+	if m.UniqueSettorCount != nil {
+		cm.BettorCount = *m.UniqueSettorCount
+	}
+	if m.IsResolved || m.CloseTime == nil {
+		return cm
+	}
+	closeAt := time.UnixMilli(*m.CloseTime)
+	cm.Overdue = !closeAt.After(now)
+	cm.ClosingSoon = !cm.Overdue && closeAt.Before(now.Add(closingWithin))
+	return cm
+}
+
+// countUnansweredComments counts comment threads started by other users in
+// which the creator has not replied.
+func countUnansweredComments(comments []client.Comment, creatorID string) int {
+	authors := make(map[string]string, len(comments))
+	for i := range comments {
+		authors[comments[i].ID] = comments[i].UserID
+	}
+
+	count := 0
+	for _, thread := range buildCommentThreads(comments) {
+		if authors[thread.ID] == creatorID {
+			continue
+		}
+		if !threadHasAuthor(thread, authors, creatorID) {
+			count++
+		}
+	}
+	return count
+}
+
+func threadHasAuthor(v *commentView, authors map[string]string, userID string) bool {
+	if authors[v.ID] == userID {
+		return true
+	}
+	for _, r := range v.Replies {
+		if threadHasAuthor(r, authors, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortCreatorMarkets orders markets needing attention first: overdue, then
+// closing soon, then other open markets, then resolved; each by close time.
+func sortCreatorMarkets(markets []creatorMarket) {
+	rank := func(m creatorMarket) int {
+		switch {
+		case m.Overdue:
+			return 0
+		case m.ClosingSoon:
+			return 1
+		case !m.IsResolved:
+			return 2
+		}
+		return 3
+	}
+	closeTime := func(m creatorMarket) int64 {
+		if m.CloseTime == nil {
+			return 1<<63 - 1
+		}
+		return *m.CloseTime
+	}
+	sort.SliceStable(markets, func(i, j int) bool {
+		ri, rj := rank(markets[i]), rank(markets[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return closeTime(markets[i]) < closeTime(markets[j])
+	})
+}
+
+func summarizeCreatorMarkets(markets []creatorMarket) creatorSummary {
+	summary := creatorSummary{Total: len(markets)}
+	for _, m := range markets {
+		switch {
+		case m.IsResolved:
+			summary.Resolved++
+		case m.Overdue:
+			summary.Overdue++
+		default:
+			summary.Open++
+		}
+		if m.ClosingSoon {
+			summary.ClosingSoon++
+		}
+		summary.UnansweredComments += m.UnansweredComments
+	}
+	return summary
+}
+
+// fetchCreatorMarkets pages through the markets created by the user, newest
+// first, reporting whether they were cut off at maxSearchResults.
+func (s *Server) fetchCreatorMarkets(ctx context.Context, creatorID string) ([]client.LiteMarket, bool, error) {
+	params := url.Values{}
+	params.Set("creatorId", creatorID)
+	params.Set("sort", "newest")
+	return s.searchAllMarkets(ctx, params)
+}
+
+// searchAllMarkets pages through the search results for the given params, up
+// to maxSearchResults. It reports whether the results were cut off there.
+func (s *Server) searchAllMarkets(ctx context.Context, params url.Values) ([]client.LiteMarket, bool, error) {
+	var all []client.LiteMarket
+	for offset := 0; offset < maxSearchResults; offset += searchPageLimit {
+		params.Set("limit", fmt.Sprintf("%d", searchPageLimit))
+		params.Set("offset", fmt.Sprintf("%d", offset))
+		page, err := s.client.SearchMarkets(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+		all = append(all, page...)
+		if len(page) < searchPageLimit {
+			return all, false, nil
+		}
+	}
+	return all, true, nil
+}
+
+// fillUnansweredComments counts unanswered comments on each unresolved market
+// concurrently. Markets whose comments cannot be fetched are left at zero.
+func (s *Server) fillUnansweredComments(ctx context.Context, markets []creatorMarket, creatorID string) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrency)
+	for i := range markets {
+		if markets[i].IsResolved {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			params := url.Values{}
+			params.Set("contractId", markets[i].ID)
+			params.Set("limit", fmt.Sprintf("%d", creatorCommentLimit))
+			comments, err := s.client.GetComments(ctx, params)
+			if err != nil {
+				return
+			}
+			markets[i].UnansweredComments = countUnansweredComments(comments, creatorID)
+		}()
+	}
+	wg.Wait()
+}
+
+// creatorActionRequest is a parsed bulk action.
+type creatorActionRequest struct {
+	action    string
+	closeTime int64
+	targets   []string
+	dryRun    bool
+}
+
+// parseCreatorAction reads the bulk action arguments, if any. Without explicit
+// targets the action applies to every overdue market.
+func parseCreatorAction(args map[string]any, markets []creatorMarket, now time.Time) (*creatorActionRequest, error) {
+	action, _ := args["action"].(string)
+	if action == "" {
+		return nil, nil
+	}
+
+	req := &creatorActionRequest{action: action, dryRun: true}
+	if v, ok := args["dryRun"].(bool); ok {
+		req.dryRun = v
+	}
+
+	switch action {
+	case actionExtendClose:
+		closeTime, hasTime := args["closeTime"].(float64)
+		days, hasDays := args["extendDays"].(float64)
+		switch {
+		case hasTime && closeTime > 0:
+			req.closeTime = int64(closeTime)
+		case hasDays && days > 0:
+			req.closeTime = now.Add(time.Duration(days * float64(24*time.Hour))).UnixMilli()
+		default:
+			return nil, fmt.Errorf("extend_close requires closeTime or extendDays")
+		}
+	case actionResolveNA:
+	default:
+		return nil, fmt.Errorf("unknown action %q (expected %s or %s)", action, actionExtendClose, actionResolveNA)
+	}
+
+	if v, ok := args["marketIds"].(string); ok && v != "" {
+		req.targets = splitIDs(v)
+		return req, nil
+	}
+	for _, m := range markets {
+		if m.Overdue {
+			req.targets = append(req.targets, m.ID)
+		}
+	}
+	return req, nil
+}
+
+// runCreatorAction applies (or previews) a bulk action. Targets that are not
+// unresolved markets owned by the user are rejected rather than attempted.
+func (s *Server) runCreatorAction(
+	ctx context.Context, req *creatorActionRequest, markets []creatorMarket,
+) []creatorAction {
+	byID := make(map[string]creatorMarket, len(markets))
+	for _, m := range markets {
+		byID[m.ID] = m
+	}
+
+	results := make([]creatorAction, 0, len(req.targets))
+	for _, id := range req.targets {
+		res := creatorAction{MarketID: id, Action: req.action, DryRun: req.dryRun}
+		m, ok := byID[id]
+		switch {
+		case !ok:
+			res.Error = "not one of your markets"
+		case m.IsResolved:
+			res.Question = m.Question
+			res.Error = "already resolved"
+		default:
+			res.Question = m.Question
+			res.Detail, res.Error = s.applyCreatorAction(ctx, req, id)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func (s *Server) applyCreatorAction(ctx context.Context, req *creatorActionRequest, marketID string) (string, string) {
+	var detail string
+	var err error
+	switch req.action {
+	case actionExtendClose:
+		detail = "close time set to " + time.UnixMilli(req.closeTime).UTC().Format(time.RFC3339)
+		if !req.dryRun {
+			err = s.client.CloseMarket(ctx, marketID, client.CloseMarketRequest{CloseTime: &req.closeTime})
+		}
+	case actionResolveNA:
+		detail = "resolved N/A (CANCEL)"
+		if !req.dryRun {
+			err = s.client.ResolveMarket(ctx, marketID, client.ResolveMarketRequest{Outcome: resolutionCancel})
+		}
+	}
+	if err != nil {
+		return detail, err.Error()
+	}
+	return detail, ""
+}
+
+func (s *Server) handleGetCreatorDashboard(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	now := time.Now()
+
+	closingWithin := defaultClosingWithinHours * time.Hour
+	if v, ok := args["closingWithinHours"].(float64); ok && v > 0 {
+		closingWithin = time.Duration(v * float64(time.Hour))
+	}
+	includeResolved, _ := args["includeResolved"].(bool)
+
+	me, err := s.client.GetMe(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get authenticated user: %v", err)), nil
+	}
+	lite, truncated, err := s.fetchCreatorMarkets(ctx, me.ID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search markets: %v", err)), nil
+	}
+
+	markets := make([]creatorMarket, len(lite))
+	for i := range lite {
+		markets[i] = classifyCreatorMarket(&lite[i], now, closingWithin)
+	}
+
+	actionReq, err := parseCreatorAction(args, markets, now)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	s.fillUnansweredComments(ctx, markets, me.ID)
+	sortCreatorMarkets(markets)
+
+	resp := creatorDashboardResponse{Summary: summarizeCreatorMarkets(markets), Truncated: truncated}
+	for _, m := range markets {
+		if includeResolved || !m.IsResolved {
+			resp.Markets = append(resp.Markets, m)
+		}
+	}
+	if actionReq != nil {
+		resp.Actions = s.runCreatorAction(ctx, actionReq, markets)
+	}
+
+	text := fmt.Sprintf("%d market(s): %d open, %d overdue, %d closing soon, %d resolved.",
+		resp.Summary.Total, resp.Summary.Open, resp.Summary.Overdue, resp.Summary.ClosingSoon, resp.Summary.Resolved)
+	if truncated {
+		text += fmt.Sprintf(" Only the newest %d markets were checked.", maxSearchResults)
+	}
+	return newStructuredResult(text, resp)
+}
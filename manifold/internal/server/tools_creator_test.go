@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func TestClassifyCreatorMarket(t *testing.T) {
+	now := time.UnixMilli(1_000_000_000)
+	past := now.Add(-time.Hour).UnixMilli()
+	soon := now.Add(12 * time.Hour).UnixMilli()
+	later := now.Add(72 * time.Hour).UnixMilli()
+	bettors := 7
+
+	overdue := classifyCreatorMarket(&client.LiteMarket{ID: "a", CloseTime: &past, UniqueSettorCount: &bettors}, now, 48*time.Hour)
+	if !overdue.Overdue || overdue.ClosingSoon || overdue.BettorCount != 7 {
+		t.Errorf("expected overdue market with 7 bettors, got %+v", overdue)
+	}
+
+	closing := classifyCreatorMarket(&client.LiteMarket{ID: "b", CloseTime: &soon}, now, 48*time.Hour)
+	if closing.Overdue || !closing.ClosingSoon {
+		t.Errorf("expected closing soon market, got %+v", closing)
+	}
+
+	open := classifyCreatorMarket(&client.LiteMarket{ID: "c", CloseTime: &later}, now, 48*time.Hour)
+	if open.Overdue || open.ClosingSoon {
+		t.Errorf("expected unflagged open market, got %+v", open)
+	}
+
+	resolved := classifyCreatorMarket(&client.LiteMarket{ID: "d", CloseTime: &past, IsResolved: true}, now, 48*time.Hour)
+	if resolved.Overdue {
+		t.Errorf("resolved market should not be overdue, got %+v", resolved)
+	}
+}
+
+func TestCountUnansweredComments(t *testing.T) {
+	q1, q2 := "q1", "q2"
+	comments := []client.Comment{
+		{ID: "q1", UserID: "bettor"},
+		{ID: "r1", UserID: "creator", ReplyToCommentID: &q1},
+		{ID: "q2", UserID: "bettor"},
+		{ID: "r2", UserID: "other", ReplyToCommentID: &q2},
+		{ID: "q3", UserID: "bettor"},
+		{ID: "own", UserID: "creator"},
+	}
+	if got := countUnansweredComments(comments, "creator"); got != 2 {
+		t.Errorf("expected 2 unanswered threads (q2, q3), got %d", got)
+	}
+}
+
+func TestSortCreatorMarkets(t *testing.T) {
+	t1, t2 := int64(100), int64(200)
+	markets := []creatorMarket{
+		{ID: "resolved", IsResolved: true},
+		{ID: "open-late", CloseTime: &t2},
+		{ID: "soon", ClosingSoon: true, CloseTime: &t2},
+		{ID: "open-early", CloseTime: &t1},
+		{ID: "overdue", Overdue: true, CloseTime: &t1},
+	}
+	sortCreatorMarkets(markets)
+	want := []string{"overdue", "soon", "open-early", "open-late", "resolved"}
+	for i, id := range want {
+		if markets[i].ID != id {
+			t.Fatalf("position %d: expected %s, got %s", i, id, markets[i].ID)
+		}
+	}
+}
+
+func TestParseCreatorAction(t *testing.T) {
+	now := time.UnixMilli(0)
+	markets := []creatorMarket{{ID: "a", Overdue: true}, {ID: "b"}, {ID: "c", Overdue: true}}
+
+	req, err := parseCreatorAction(map[string]any{"action": "extend_close", "extendDays": 2.0}, markets, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !req.dryRun {
+		t.Error("expected dry run by default")
+	}
+	if req.closeTime != (48 * time.Hour).Milliseconds() {
+		t.Errorf("expected close time 48h from now, got %d", req.closeTime)
+	}
+	if len(req.targets) != 2 || req.targets[0] != "a" || req.targets[1] != "c" {
+		t.Errorf("expected overdue targets a, c; got %v", req.targets)
+	}
+
+	req, err = parseCreatorAction(
+		map[string]any{"action": "resolve_na", "marketIds": "b", "dryRun": false}, markets, now,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.dryRun || len(req.targets) != 1 || req.targets[0] != "b" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	if _, err := parseCreatorAction(map[string]any{"action": "extend_close"}, markets, now); err == nil {
+		t.Error("expected error without close time")
+	}
+	if _, err := parseCreatorAction(map[string]any{"action": "delete"}, markets, now); err == nil {
+		t.Error("expected error for unknown action")
+	}
+	if req, err := parseCreatorAction(map[string]any{}, markets, now); req != nil || err != nil {
+		t.Errorf("expected no action, got %+v, %v", req, err)
+	}
+}
+
+func TestRunCreatorAction(t *testing.T) {
+	var resolved []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body client.ResolveMarketRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Outcome != resolutionCancel {
+			t.Errorf("expected CANCEL resolution, got %q", body.Outcome)
+		}
+		resolved = append(resolved, r.URL.Path)
+	}))
+	defer api.Close()
+
+	s := &Server{client: client.NewClient(api.URL, "key")}
+	markets := []creatorMarket{{ID: "a", Question: "A?", Overdue: true}, {ID: "done", IsResolved: true}}
+	req := &creatorActionRequest{action: actionResolveNA, targets: []string{"a", "done", "someone-elses"}}
+
+	results := s.runCreatorAction(context.Background(), req, markets)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Error != "" || results[1].Error == "" || results[2].Error == "" {
+		t.Errorf("expected only the owned unresolved market to succeed, got %+v", results)
+	}
+	if len(resolved) != 1 || resolved[0] != "/v0/market/a/resolve" {
+		t.Errorf("expected one resolve call for market a, got %v", resolved)
+	}
+
+	// Dry runs make no API calls.
+	req.dryRun = true
+	s.runCreatorAction(context.Background(), req, markets)
+	if len(resolved) != 1 {
+		t.Errorf("expected dry run not to call the API, got %v", resolved)
+	}
+}
+
+func TestSearchAllMarkets(t *testing.T) {
+	var calls int
+	total := searchPageLimit + 10
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		n := max(min(searchPageLimit, total-offset), 0)
+		_ = json.NewEncoder(w).Encode(make([]client.LiteMarket, n))
+	}))
+	defer api.Close()
+
+	s := &Server{client: client.NewClient(api.URL, "key")}
+	markets, truncated, err := s.searchAllMarkets(context.Background(), url.Values{})
+	if err != nil || truncated || len(markets) != total || calls != 2 {
+		t.Errorf("expected all %d markets in 2 pages, got %d in %d pages, truncated %v, err %v",
+			total, len(markets), calls, truncated, err)
+	}
+
+	calls, total = 0, 2*maxSearchResults
+	markets, truncated, _ = s.searchAllMarkets(context.Background(), url.Values{})
+	if !truncated || len(markets) != maxSearchResults || calls != maxSearchResults/searchPageLimit {
+		t.Errorf("expected truncation at %d markets, got %d in %d pages, truncated %v",
+			maxSearchResults, len(markets), calls, truncated)
+	}
+}
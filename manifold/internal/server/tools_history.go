@@ -0,0 +1,443 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultHistoryDays  = 30
+	maxHistoryDays      = 365
+	defaultContributors = 5
+	betPageLimit        = 1000
+	// maxHistoryPages bounds how far back a single market's bet history is
+	// fetched; prices before the oldest fetched bet are approximated.
+	maxHistoryPages = 10
+	// maxUserBetPages bounds how many of a user's bets are replayed; older
+	// bets are left out, so positions opened before them are misstated.
+	maxUserBetPages = 50
+)
+
+// positionKey identifies a priced position: a market, or one answer of a
+// multiple choice market.
+type positionKey struct {
+	contractID string
+	answerID   string
+}
+
+func betKey(b *client.Bet) positionKey {
+	k := positionKey{contractID: b.ContractID}
+	if b.AnswerID != nil {
+		k.answerID = *b.AnswerID
+	}
+	return k
+}
+
+// priceAt returns the YES probability at time t from a bet history sorted
+// oldest first: the probability after the latest bet at or before t, or
+// before the earliest bet if t precedes them all. With no bets the current
+// probability is used.
+func priceAt(history []client.Bet, t int64, current float64) float64 {
+	i := sort.Search(len(history), func(i int) bool { return history[i].CreatedTime > t })
+	if i > 0 {
+		return history[i-1].ProbAfter
+	}
+	if len(history) > 0 {
+		return history[0].ProbBefore
+	}
+	return current
+}
+
+// historyMarket is the data needed to value one market's positions over time.
+type historyMarket struct {
+	market *client.FullMarket
+	// bets are the user's bets on the market, oldest first.
+	bets []client.Bet
+	// prices are all bets on each position, oldest first.
+	prices map[positionKey][]client.Bet
+}
+
+// currentProb returns the current YES probability of a position.
+func (h *historyMarket) currentProb(answerID string) float64 {
+	if answerID == "" {
+		if h.market.Probability != nil {
+			return *h.market.Probability
+		}
+		return 0
+	}
+	for i := range h.market.Answers {
+		if h.market.Answers[i].ID == answerID {
+			if p := h.market.Answers[i].CurrentProb(); p != nil {
+				return *p
+			}
+		}
+	}
+	return 0
+}
+
+// resolutionAt returns a position's resolved YES value if it had resolved by
+// time t, and whether it was cancelled.
+func (h *historyMarket) resolutionAt(answerID string, t int64) (value float64, resolved, cancelled bool) {
+	m := h.market
+	if answerID != "" {
+		for i := range m.Answers {
+			a := &m.Answers[i]
+			if a.ID == answerID && a.Resolution != nil && a.ResolutionTime != nil && *a.ResolutionTime <= t {
+				v, c := resolvedYesValue(*a.Resolution, answerID, nil, h.currentProb(answerID))
+				return v, true, c
+			}
+		}
+	}
+	if !m.IsResolved || m.Resolution == nil || m.ResolutionTime == nil || *m.ResolutionTime > t {
+		return 0, false, false
+	}
+	var resProb *float64
+	if answerID == "" {
+		resProb = m.ResolutionProb
+	}
+	v, c := resolvedYesValue(*m.Resolution, answerID, resProb, h.currentProb(answerID))
+	return v, true, c
+}
+
+// marketPnlAt values the user's positions in a market at time t. P&L is the
+// value of shares held less net mana invested; positions count as closed once
+// resolved or fully sold.
+func (h *historyMarket) marketPnlAt(t int64) (value, pnl float64, closed bool) {
+	type holding struct {
+		shares   map[string]float64
+		invested float64
+	}
+	holdings := map[positionKey]*holding{}
+	for i := range h.bets {
+		b := &h.bets[i]
+		if b.CreatedTime > t {
+			break
+		}
+		k := betKey(b)
+		hd, ok := holdings[k]
+		if !ok {
+			hd = &holding{shares: map[string]float64{}}
+			holdings[k] = hd
+		}
+		hd.shares[b.Outcome] += b.Shares
+		hd.invested += b.Amount
+	}
+
+	closed = true
+	for k, hd := range holdings {
+		yes, resolved, cancelled := h.resolutionAt(k.answerID, t)
+		if cancelled {
+			continue
+		}
+		if !resolved {
+			yes = priceAt(h.prices[k], t, h.currentProb(k.answerID))
+		}
+		v := hd.shares[outcomeYes]*yes + hd.shares[outcomeNo]*(1-yes)
+		pnl += v - hd.invested
+		held := math.Abs(hd.shares[outcomeYes]) >= minSellShares || math.Abs(hd.shares[outcomeNo]) >= minSellShares
+		if !resolved && held {
+			closed = false
+			value += v
+		}
+	}
+	return value, pnl, closed
+}
+
+// needsPriceHistory reports whether a market's positions could change value
+// after t, so its price history is needed.
+func (h *historyMarket) needsPriceHistory(t int64) bool {
+	if h.market.IsResolved && h.market.ResolutionTime != nil && *h.market.ResolutionTime <= t {
+		return false
+	}
+	if len(h.bets) > 0 && h.bets[len(h.bets)-1].CreatedTime > t {
+		return true
+	}
+	_, _, closed := h.marketPnlAt(t)
+	return !closed
+}
+
+// historyPoint is the portfolio at the end of one day.
+type historyPoint struct {
+	Date          string  `json:"date"`
+	Time          int64   `json:"time"`
+	PositionValue float64 `json:"positionValue"`
+	RealizedPnl   float64 `json:"realizedPnl"`
+	UnrealizedPnl float64 `json:"unrealizedPnl"`
+	TotalPnl      float64 `json:"totalPnl"`
+	DailyPnl      float64 `json:"dailyPnl"`
+}
+
+// historyContributor is a market's contribution to P&L over the window.
+type historyContributor struct {
+	ContractID string  `json:"contractId"`
+	Question   string  `json:"question"`
+	URL        string  `json:"url"`
+	StartPnl   float64 `json:"startPnl"`
+	EndPnl     float64 `json:"endPnl"`
+	PnlChange  float64 `json:"pnlChange"`
+}
+
+// historySummary totals the window.
+type historySummary struct {
+	StartTotalPnl float64 `json:"startTotalPnl"`
+	EndTotalPnl   float64 `json:"endTotalPnl"`
+	WindowPnl     float64 `json:"windowPnl"`
+	MarketCount   int     `json:"marketCount"`
+	Excluded      int     `json:"excludedUnsupported"`
+	// Truncated is set when the user had more than maxUserBetPages pages of
+	// bets and only the newest were replayed.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// historyResponse is the JSON output for get_portfolio_history.
+type historyResponse struct {
+	Summary   historySummary       `json:"summary"`
+	Series    []historyPoint       `json:"series"`
+	TopGains  []historyContributor `json:"topGains"`
+	TopLosses []historyContributor `json:"topLosses"`
+	Warnings  []string             `json:"warnings,omitempty"`
+}
+
+// historyTimes returns the window start followed by the end of each of the
+// last days UTC days, the last being now.
+func historyTimes(now time.Time, days int) []time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	times := make([]time.Time, 0, days+1)
+	for i := days; i >= 0; i-- {
+		t := today.AddDate(0, 0, -i).Add(24*time.Hour - time.Millisecond)
+		if t.After(now) {
+			t = now
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// replayPortfolio values every market at each time, returning the daily
+// series (excluding the window start) and each market's change in P&L.
+func replayPortfolio(markets []*historyMarket, times []time.Time, topN int) historyResponse {
+	resp := historyResponse{
+		Series:    make([]historyPoint, 0, len(times)-1),
+		TopGains:  []historyContributor{},
+		TopLosses: []historyContributor{},
+	}
+	points := make([]historyPoint, len(times))
+	for i, t := range times {
+		points[i] = historyPoint{Date: t.Format(time.DateOnly), Time: t.UnixMilli()}
+	}
+
+	var contributors []historyContributor
+	for _, h := range markets {
+		var first, last float64
+		for i := range times {
+			value, pnl, closed := h.marketPnlAt(points[i].Time)
+			points[i].PositionValue += value
+			points[i].TotalPnl += pnl
+			if closed {
+				points[i].RealizedPnl += pnl
+			} else {
+				points[i].UnrealizedPnl += pnl
+			}
+			if i == 0 {
+				first = pnl
+			}
+			last = pnl
+		}
+		if change := last - first; math.Abs(change) >= 0.01 {
+			contributors = append(contributors, historyContributor{
+				ContractID: h.market.ID,
+				Question:   h.market.Question,
+				URL:        h.market.URL,
+				StartPnl:   first,
+				EndPnl:     last,
+				PnlChange:  change,
+			})
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		points[i].DailyPnl = points[i].TotalPnl - points[i-1].TotalPnl
+		resp.Series = append(resp.Series, points[i])
+	}
+	resp.Summary = historySummary{
+		StartTotalPnl: points[0].TotalPnl,
+		EndTotalPnl:   points[len(points)-1].TotalPnl,
+		WindowPnl:     points[len(points)-1].TotalPnl - points[0].TotalPnl,
+		MarketCount:   len(markets),
+	}
+
+	sort.Slice(contributors, func(i, j int) bool {
+		return contributors[i].PnlChange > contributors[j].PnlChange
+	})
+	for _, c := range contributors {
+		if c.PnlChange > 0 && len(resp.TopGains) < topN {
+			resp.TopGains = append(resp.TopGains, c)
+		}
+	}
+	for i := len(contributors) - 1; i >= 0; i-- {
+		if c := contributors[i]; c.PnlChange < 0 && len(resp.TopLosses) < topN {
+			resp.TopLosses = append(resp.TopLosses, c)
+		}
+	}
+	return resp
+}
+
+// fetchBetPages pages backwards through bets matching params, newest first,
+// until a bet at or before since is reached, the bets run out, or maxPages
+// pages have been fetched. It reports whether it stopped at the page limit.
+func (s *Server) fetchBetPages(
+	ctx context.Context, params url.Values, since int64, maxPages int,
+) ([]client.Bet, bool, error) {
+	var all []client.Bet
+	params.Set("limit", strconv.Itoa(betPageLimit))
+	for page := 0; page < maxPages; page++ {
+		bets, err := s.client.ListBets(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+		all = append(all, bets...)
+		if len(bets) < betPageLimit || bets[len(bets)-1].CreatedTime <= since {
+			return all, false, nil
+		}
+		params.Set("before", bets[len(bets)-1].ID)
+	}
+	return all, true, nil
+}
+
+// fetchPriceHistories fetches the bet history of each market back to since,
+// storing it per position, oldest first.
+func (s *Server) fetchPriceHistories(ctx context.Context, markets []*historyMarket, since int64) []string {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, maxConcurrency)
+		warnings []string
+	)
+	for _, h := range markets {
+		if !h.needsPriceHistory(since) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			params := url.Values{}
+			params.Set("contractId", h.market.ID)
+			bets, truncated, err := s.fetchBetPages(ctx, params, since, maxHistoryPages)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				warnings = append(warnings, fmt.Sprintf("%s: price history unavailable, using current price: %v", h.market.ID, err))
+			case truncated:
+				warnings = append(warnings, fmt.Sprintf("%s: price history truncated; early prices are approximate", h.market.ID))
+			}
+			for i := len(bets) - 1; i >= 0; i-- {
+				k := betKey(&bets[i])
+				h.prices[k] = append(h.prices[k], bets[i])
+			}
+		}()
+	}
+	wg.Wait()
+	sort.Strings(warnings)
+	return warnings
+}
+
+// groupHistoryMarkets groups the user's bets by market, oldest first, keeping
+// markets of the types get_portfolio_pnl can value.
+func groupHistoryMarkets(bets []client.Bet, markets map[string]*client.FullMarket) ([]*historyMarket, int) {
+	byID := map[string]*historyMarket{}
+	var ordered []*historyMarket
+	excluded := map[string]bool{}
+	for i := len(bets) - 1; i >= 0; i-- {
+		b := bets[i]
+		m, ok := markets[b.ContractID]
+		if !ok {
+			continue
+		}
+		switch m.OutcomeType {
+		case outcomeTypeBinary, outcomeTypePseudoNumeric, outcomeTypeMultipleChoice:
+		default:
+			excluded[m.ID] = true
+			continue
+		}
+		h, ok := byID[b.ContractID]
+		if !ok {
+			h = &historyMarket{market: m, prices: map[positionKey][]client.Bet{}}
+			byID[b.ContractID] = h
+			ordered = append(ordered, h)
+		}
+		h.bets = append(h.bets, b)
+	}
+	for _, h := range ordered {
+		sort.SliceStable(h.bets, func(i, j int) bool { return h.bets[i].CreatedTime < h.bets[j].CreatedTime })
+	}
+	return ordered, len(excluded)
+}
+
+func (s *Server) handleGetPortfolioHistory(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	userID, ok := args["userId"].(string)
+	if !ok || userID == "" {
+		return mcp.NewToolResultError("userId is required"), nil
+	}
+	days := defaultHistoryDays
+	if v, ok := args["days"].(float64); ok && v > 0 {
+		days = min(int(v), maxHistoryDays)
+	}
+	topN := defaultContributors
+	if v, ok := args["top"].(float64); ok && v > 0 {
+		topN = int(v)
+	}
+
+	times := historyTimes(time.Now(), days)
+	since := times[0].UnixMilli()
+
+	params := url.Values{}
+	params.Set("userId", userID)
+	bets, truncated, err := s.fetchBetPages(ctx, params, 0, maxUserBetPages)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list bets: %v", err)), nil
+	}
+
+	seen := map[string]bool{}
+	var ids []string
+	for _, b := range bets {
+		if !seen[b.ContractID] {
+			seen[b.ContractID] = true
+			ids = append(ids, b.ContractID)
+		}
+	}
+	fullMarkets, errs := s.fetchFullMarkets(ctx, ids)
+
+	markets, excluded := groupHistoryMarkets(bets, fullMarkets)
+	warnings := append(errs, s.fetchPriceHistories(ctx, markets, since)...)
+
+	resp := replayPortfolio(markets, times, topN)
+	resp.Summary.Excluded = excluded
+	resp.Summary.Truncated = truncated
+	if truncated {
+		warnings = append(warnings, fmt.Sprintf(
+			"only the newest %d bets were replayed; positions opened before them are misstated", len(bets)))
+	}
+	resp.Warnings = warnings
+
+	return newStructuredResult(fmt.Sprintf("P&L over %d day(s): %+.0f mana across %d market(s).",
+		len(resp.Series), resp.Summary.WindowPnl, resp.Summary.MarketCount), resp)
+}
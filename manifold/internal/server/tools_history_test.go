@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func TestPriceAt(t *testing.T) {
+	history := []client.Bet{
+		{CreatedTime: 100, ProbBefore: 0.5, ProbAfter: 0.6},
+		{CreatedTime: 200, ProbBefore: 0.6, ProbAfter: 0.7},
+	}
+	tests := []struct {
+		t    int64
+		want float64
+	}{
+		{50, 0.5},
+		{100, 0.6},
+		{150, 0.6},
+		{250, 0.7},
+	}
+	for _, tt := range tests {
+		if got := priceAt(history, tt.t, 0.9); got != tt.want {
+			t.Errorf("priceAt(%d) = %f, want %f", tt.t, got, tt.want)
+		}
+	}
+	if got := priceAt(nil, 100, 0.9); got != 0.9 {
+		t.Errorf("expected current price without history, got %f", got)
+	}
+}
+
+func TestHistoryTimes(t *testing.T) {
+	now := time.Date(2027, 5, 10, 15, 0, 0, 0, time.UTC)
+	times := historyTimes(now, 3)
+	if len(times) != 4 {
+		t.Fatalf("expected 4 times, got %d", len(times))
+	}
+	if want := time.Date(2027, 5, 7, 23, 59, 59, 999e6, time.UTC); !times[0].Equal(want) {
+		t.Errorf("expected window start %v, got %v", want, times[0])
+	}
+	if !times[3].Equal(now) {
+		t.Errorf("expected the last point to be now, got %v", times[3])
+	}
+}
+
+func TestReplayPortfolio(t *testing.T) {
+	day := func(d int) int64 { return time.Date(2027, 5, d, 12, 0, 0, 0, time.UTC).UnixMilli() }
+	times := []time.Time{
+		time.Date(2027, 5, 1, 23, 59, 59, 0, time.UTC),
+		time.Date(2027, 5, 2, 23, 59, 59, 0, time.UTC),
+		time.Date(2027, 5, 3, 23, 59, 59, 0, time.UTC),
+	}
+
+	// Bought 100 YES at 0.5 before the window; the price rises to 0.6 on day 2.
+	winner := &historyMarket{
+		market: &client.FullMarket{LiteMarket: client.LiteMarket{ID: "w", Probability: floatPtr(0.6)}},
+		bets:   []client.Bet{{ContractID: "w", CreatedTime: day(1) - 1, Outcome: outcomeYes, Amount: 50, Shares: 100}},
+		prices: map[positionKey][]client.Bet{
+			{contractID: "w"}: {
+				{CreatedTime: day(1) - 1, ProbBefore: 0.4, ProbAfter: 0.5},
+				{CreatedTime: day(2), ProbBefore: 0.5, ProbAfter: 0.6},
+			},
+		},
+	}
+	// Bought 40 NO for 20, resolved YES on day 3.
+	resolvedAt := day(3)
+	loser := &historyMarket{
+		market: &client.FullMarket{LiteMarket: client.LiteMarket{
+			ID: "l", Probability: floatPtr(1), IsResolved: true,
+			Resolution: strPtr(outcomeYes), ResolutionTime: &resolvedAt,
+		}},
+		bets: []client.Bet{{ContractID: "l", CreatedTime: day(1), Outcome: outcomeNo, Amount: 20, Shares: 40}},
+		prices: map[positionKey][]client.Bet{
+			{contractID: "l"}: {{CreatedTime: day(1), ProbBefore: 0.55, ProbAfter: 0.5}},
+		},
+	}
+
+	resp := replayPortfolio([]*historyMarket{winner, loser}, times, 5)
+	if len(resp.Series) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(resp.Series))
+	}
+
+	// Start: winner 50-50=0, loser 40*0.5-20=0.
+	if resp.Summary.StartTotalPnl != 0 {
+		t.Errorf("expected zero starting P&L, got %f", resp.Summary.StartTotalPnl)
+	}
+	day2 := resp.Series[0]
+	if math.Abs(day2.UnrealizedPnl-10) > 1e-9 || day2.RealizedPnl != 0 || math.Abs(day2.PositionValue-80) > 1e-9 {
+		t.Errorf("unexpected day 2: %+v", day2)
+	}
+	day3 := resp.Series[1]
+	if math.Abs(day3.RealizedPnl-(-20)) > 1e-9 || math.Abs(day3.UnrealizedPnl-10) > 1e-9 {
+		t.Errorf("unexpected day 3: %+v", day3)
+	}
+	if math.Abs(day3.DailyPnl-(-20)) > 1e-9 || math.Abs(resp.Summary.WindowPnl-(-10)) > 1e-9 {
+		t.Errorf("unexpected daily/window P&L: %f / %f", day3.DailyPnl, resp.Summary.WindowPnl)
+	}
+	if len(resp.TopGains) != 1 || resp.TopGains[0].ContractID != "w" ||
+		len(resp.TopLosses) != 1 || resp.TopLosses[0].ContractID != "l" {
+		t.Errorf("unexpected contributors: %+v / %+v", resp.TopGains, resp.TopLosses)
+	}
+}
+
+func TestMarketPnlAt_AnswersAndSales(t *testing.T) {
+	m := &historyMarket{
+		market: &client.FullMarket{
+			LiteMarket: client.LiteMarket{ID: "mc", OutcomeType: outcomeTypeMultipleChoice},
+			Answers:    []client.Answer{{ID: "a", Probability: floatPtr(0.3)}},
+		},
+		bets: []client.Bet{
+			{ContractID: "mc", AnswerID: strPtr("a"), CreatedTime: 10, Outcome: outcomeYes, Amount: 10, Shares: 50},
+			{ContractID: "mc", AnswerID: strPtr("a"), CreatedTime: 20, Outcome: outcomeYes, Amount: -15, Shares: -50},
+		},
+		prices: map[positionKey][]client.Bet{},
+	}
+
+	value, pnl, closed := m.marketPnlAt(15)
+	if closed || math.Abs(value-15) > 1e-9 || math.Abs(pnl-5) > 1e-9 {
+		t.Errorf("expected open position worth 15, got value %f pnl %f closed %v", value, pnl, closed)
+	}
+	value, pnl, closed = m.marketPnlAt(25)
+	if !closed || value != 0 || math.Abs(pnl-5) > 1e-9 {
+		t.Errorf("expected sold position to realize 5, got value %f pnl %f closed %v", value, pnl, closed)
+	}
+}
+
+func TestFetchBetPages(t *testing.T) {
+	var calls int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Full pages of bets counting down in time from 10000 per page.
+		start := 10000 * (4 - calls)
+		bets := make([]client.Bet, betPageLimit)
+		for i := range bets {
+			bets[i] = client.Bet{ID: strconv.Itoa(calls) + "-" + strconv.Itoa(i), CreatedTime: int64(start + betPageLimit - i)}
+		}
+		if r.URL.Query().Get("before") == "" && calls > 1 {
+			t.Error("expected later pages to set before")
+		}
+		_ = json.NewEncoder(w).Encode(bets)
+	}))
+	defer api.Close()
+
+	s := &Server{client: client.NewClient(api.URL, "key")}
+	bets, truncated, err := s.fetchBetPages(context.Background(), url.Values{}, 20500, 10)
+	if err != nil || truncated || calls != 2 || len(bets) != 2*betPageLimit {
+		t.Errorf("expected to stop after reaching since: %d calls, %d bets, truncated %v, err %v",
+			calls, len(bets), truncated, err)
+	}
+
+	calls = 0
+	_, truncated, _ = s.fetchBetPages(context.Background(), url.Values{}, 0, 2)
+	if !truncated || calls != 2 {
+		t.Errorf("expected truncation at the page limit, got %v after %d calls", truncated, calls)
+	}
+}
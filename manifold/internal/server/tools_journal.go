@@ -0,0 +1,461 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/jbeshir/mcp-servers/manifold/internal/journal"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultJournalEntryLimit = 50
+	untaggedGroup            = "untagged"
+
+	tradeStatusOpen      = "open"
+	tradeStatusWon       = "won"
+	tradeStatusLost      = "lost"
+	tradeStatusCancelled = "cancelled"
+	tradeStatusUnfilled  = "unfilled"
+	tradeStatusUnknown   = "unknown"
+
+	// fillLookupLimit is how many of a user's bets on a market are fetched
+	// when reconciling limit order fills.
+	fillLookupLimit = 1000
+)
+
+// parseJournalMetadata reads the optional journal arguments shared by the
+// trading tools.
+func parseJournalMetadata(args map[string]any) (rationale string, confidence *float64, tags []string, err error) {
+	rationale, _ = args["rationale"].(string)
+	if v, ok := args["confidence"].(float64); ok {
+		if v < 0 || v > 1 {
+			return "", nil, nil, fmt.Errorf("confidence must be between 0 and 1")
+		}
+		confidence = &v
+	}
+	if v, ok := args["tags"].(string); ok && v != "" {
+		tags = splitIDs(v)
+	}
+	return rationale, confidence, tags, nil
+}
+
+// recordTrade journals an executed trade. Failures are returned as a warning
+// rather than an error, since the trade itself has already happened.
+func (s *Server) recordTrade(action string, bet *client.Bet, args map[string]any) string {
+	rationale, confidence, tags, _ := parseJournalMetadata(args)
+	err := s.journal.Append(journal.Entry{
+		Action:     action,
+		Bet:        *bet,
+		Rationale:  rationale,
+		Confidence: confidence,
+		Tags:       tags,
+	})
+	if err != nil {
+		return fmt.Sprintf("warning: trade executed but not journaled: %v", err)
+	}
+	return ""
+}
+
+// withWarning appends a warning to a tool result if there is one.
+func withWarning(result *mcp.CallToolResult, warning string) *mcp.CallToolResult {
+	if warning != "" && result != nil {
+		result.Content = append(result.Content, mcp.NewTextContent(warning))
+	}
+	return result
+}
+
+// reviewedTrade is a journal entry joined against its market's current state.
+type reviewedTrade struct {
+	BetID       string   `json:"betId"`
+	ContractID  string   `json:"contractId"`
+	AnswerID    string   `json:"answerId,omitempty"`
+	Question    string   `json:"question,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Action      string   `json:"action"`
+	Outcome     string   `json:"outcome"`
+	Amount      float64  `json:"amount"`
+	Shares      float64  `json:"shares"`
+	TradedAt    int64    `json:"tradedAt"`
+	Rationale   string   `json:"rationale,omitempty"`
+	Confidence  *float64 `json:"confidence,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Status      string   `json:"status"`
+	ShareValue  *float64 `json:"shareValue,omitempty"`
+	Pnl         *float64 `json:"pnl,omitempty"`
+	Resolution  string   `json:"resolution,omitempty"`
+	marketValue float64
+}
+
+// journalGroup aggregates reviewed trades sharing a tag.
+type journalGroup struct {
+	Tag           string   `json:"tag"`
+	Trades        int      `json:"trades"`
+	Open          int      `json:"open"`
+	Won           int      `json:"won"`
+	Lost          int      `json:"lost"`
+	Cancelled     int      `json:"cancelled"`
+	Unfilled      int      `json:"unfilled"`
+	RealizedPnl   float64  `json:"realizedPnl"`
+	UnrealizedPnl float64  `json:"unrealizedPnl"`
+	HitRate       *float64 `json:"hitRate,omitempty"`
+	BrierScore    *float64 `json:"brierScore,omitempty"`
+	brierSum      float64
+	brierCount    int
+}
+
+// journalReviewResponse is the JSON output for review_journal.
+type journalReviewResponse struct {
+	Groups []*journalGroup `json:"groups"`
+	Trades []reviewedTrade `json:"trades"`
+	Errors []string        `json:"errors,omitempty"`
+}
+
+// resolvedYesValue returns what a YES share paid out for a resolution, and
+// whether the market (or answer) was cancelled. Multiple choice markets
+// resolve to the winning answer's ID.
+func resolvedYesValue(resolution, answerID string, resolutionProb *float64, prob float64) (float64, bool) {
+	switch resolution {
+	case outcomeYes:
+		return 1, false
+	case outcomeNo:
+		return 0, false
+	case resolutionCancel:
+		return 0, true
+	case "MKT":
+		if resolutionProb != nil {
+			return *resolutionProb, false
+		}
+		return prob, false
+	}
+	if answerID != "" {
+		if resolution == answerID {
+			return 1, false
+		}
+		return 0, false
+	}
+	return prob, false
+}
+
+// tradeYesValue returns the current or resolved value of a YES share for the
+// market or answer a bet was placed on.
+func tradeYesValue(m *client.FullMarket, answerID string) (value float64, resolved, cancelled, ok bool) {
+	if answerID == "" {
+		if m.IsResolved && m.Resolution != nil {
+			prob := 0.0
+			if m.Probability != nil {
+				prob = *m.Probability
+			}
+			v, c := resolvedYesValue(*m.Resolution, "", m.ResolutionProb, prob)
+			return v, true, c, true
+		}
+		if m.Probability == nil {
+			return 0, false, false, false
+		}
+		return *m.Probability, false, false, true
+	}
+
+	for i := range m.Answers {
+		a := &m.Answers[i]
+		if a.ID != answerID {
+			continue
+		}
+		prob := 0.0
+		if p := a.CurrentProb(); p != nil {
+			prob = *p
+		}
+		switch {
+		case a.Resolution != nil:
+			v, c := resolvedYesValue(*a.Resolution, answerID, nil, prob)
+			return v, true, c, true
+		case m.IsResolved && m.Resolution != nil:
+			v, c := resolvedYesValue(*m.Resolution, answerID, nil, prob)
+			return v, true, c, true
+		}
+		return prob, false, false, a.CurrentProb() != nil
+	}
+	return 0, false, false, false
+}
+
+// reconcileFills refreshes the journaled limit orders in entries with their
+// current fills from /v0/bets, since a limit order's amount and shares at
+// placement only cover what filled immediately. Orders that are fully filled
+// or cancelled can't change and aren't looked up. Returns lookup errors;
+// entries that couldn't be reconciled keep their journaled fills.
+func (s *Server) reconcileFills(ctx context.Context, entries []journal.Entry) []string {
+	type orderKey struct{ userID, contractID string }
+	pending := map[orderKey][]int{}
+	var keys []orderKey
+	for i, e := range entries {
+		b := e.Bet
+		if b.LimitProb == nil || (b.IsFilled != nil && *b.IsFilled) || (b.IsCancelled != nil && *b.IsCancelled) {
+			continue
+		}
+		k := orderKey{b.UserID, b.ContractID}
+		if _, ok := pending[k]; !ok {
+			keys = append(keys, k)
+		}
+		pending[k] = append(pending[k], i)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, maxConcurrency)
+		errs []string
+	)
+	for _, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			bets, err := s.client.ListBets(ctx, url.Values{
+				"userId":     {k.userID},
+				"contractId": {k.contractID},
+				"limit":      {fmt.Sprint(fillLookupLimit)},
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("reconciling fills on %s: %v", k.contractID, err))
+				return
+			}
+			byID := make(map[string]client.Bet, len(bets))
+			for _, b := range bets {
+				byID[b.ID] = b
+			}
+			for _, i := range pending[k] {
+				b := &entries[i].Bet
+				current, ok := byID[b.ID]
+				if !ok {
+					errs = append(errs, fmt.Sprintf("limit order %s not found on %s", b.ID, k.contractID))
+					continue
+				}
+				b.Amount, b.Shares, b.Fills = current.Amount, current.Shares, current.Fills
+				b.IsFilled, b.IsCancelled = current.IsFilled, current.IsCancelled
+			}
+		}()
+	}
+	wg.Wait()
+	sort.Strings(errs)
+	return errs
+}
+
+// reviewTrade evaluates a journal entry against its market. P&L is the
+// entry's own mark-to-market or resolved result: shares times share value,
+// less the mana paid (sells have negative shares and amounts).
+func reviewTrade(e journal.Entry, m *client.FullMarket) reviewedTrade {
+	b := e.Bet
+	rt := reviewedTrade{
+		BetID:      b.ID,
+		ContractID: b.ContractID,
+		Action:     e.Action,
+		Outcome:    b.Outcome,
+		Amount:     b.Amount,
+		Shares:     b.Shares,
+		TradedAt:   b.CreatedTime,
+		Rationale:  e.Rationale,
+		Confidence: e.Confidence,
+		Tags:       e.Tags,
+		Status:     tradeStatusUnknown,
+	}
+	if b.AnswerID != nil {
+		rt.AnswerID = *b.AnswerID
+	}
+	if m != nil {
+		rt.Question, rt.URL = m.Question, m.URL
+		if m.Resolution != nil {
+			rt.Resolution = *m.Resolution
+		}
+	}
+	// A limit order that never filled bought nothing, so it isn't scored.
+	if b.LimitProb != nil && b.Shares == 0 {
+		rt.Status = tradeStatusUnfilled
+		return rt
+	}
+	if m == nil {
+		return rt
+	}
+
+	yes, resolved, cancelled, ok := tradeYesValue(m, rt.AnswerID)
+	if !ok {
+		return rt
+	}
+	if cancelled {
+		rt.Status = tradeStatusCancelled
+		return rt
+	}
+
+	value := yes
+	if b.Outcome == outcomeNo {
+		value = 1 - yes
+	}
+	pnl := b.Shares*value - b.Amount
+	rt.ShareValue, rt.Pnl, rt.marketValue = &value, &pnl, value
+
+	switch {
+	case !resolved:
+		rt.Status = tradeStatusOpen
+	case pnl > 0:
+		rt.Status = tradeStatusWon
+	default:
+		rt.Status = tradeStatusLost
+	}
+	return rt
+}
+
+func (g *journalGroup) add(rt reviewedTrade) {
+	g.Trades++
+	switch rt.Status {
+	case tradeStatusOpen:
+		g.Open++
+		g.UnrealizedPnl += *rt.Pnl
+	case tradeStatusWon, tradeStatusLost:
+		if rt.Status == tradeStatusWon {
+			g.Won++
+		} else {
+			g.Lost++
+		}
+		g.RealizedPnl += *rt.Pnl
+		// Calibration only makes sense for the outcome a buy was betting on.
+		if rt.Confidence != nil && rt.Action == journal.ActionBuy {
+			diff := *rt.Confidence - rt.marketValue
+			g.brierSum += diff * diff
+			g.brierCount++
+		}
+	case tradeStatusCancelled:
+		g.Cancelled++
+	case tradeStatusUnfilled:
+		g.Unfilled++
+	}
+}
+
+func (g *journalGroup) finish() {
+	if decided := g.Won + g.Lost; decided > 0 {
+		rate := float64(g.Won) / float64(decided)
+		g.HitRate = &rate
+	}
+	if g.brierCount > 0 {
+		score := g.brierSum / float64(g.brierCount)
+		g.BrierScore = &score
+	}
+}
+
+// groupReviewedTrades aggregates trades by tag; a trade with several tags
+// counts towards each of them.
+func groupReviewedTrades(trades []reviewedTrade) []*journalGroup {
+	groups := map[string]*journalGroup{}
+	for _, rt := range trades {
+		tags := rt.Tags
+		if len(tags) == 0 {
+			tags = []string{untaggedGroup}
+		}
+		for _, tag := range tags {
+			g, ok := groups[tag]
+			if !ok {
+				g = &journalGroup{Tag: tag}
+				groups[tag] = g
+			}
+			g.add(rt)
+		}
+	}
+
+	result := make([]*journalGroup, 0, len(groups))
+	for _, g := range groups {
+		g.finish()
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// journalFilter selects journal entries for review.
+type journalFilter struct {
+	tag        string
+	contractID string
+	since      int64
+}
+
+func (f journalFilter) matches(e journal.Entry) bool {
+	if f.contractID != "" && e.Bet.ContractID != f.contractID {
+		return false
+	}
+	if f.since > 0 && e.Bet.CreatedTime < f.since {
+		return false
+	}
+	if f.tag == "" {
+		return true
+	}
+	for _, t := range e.Tags {
+		if t == f.tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleReviewJournal(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	filter := journalFilter{}
+	filter.tag, _ = args["tag"].(string)
+	filter.contractID, _ = args["contractId"].(string)
+	if days, ok := args["sinceDays"].(float64); ok && days > 0 {
+		filter.since = time.Now().Add(-time.Duration(days * float64(24*time.Hour))).UnixMilli()
+	}
+	limit := defaultJournalEntryLimit
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		limit = int(v)
+	}
+
+	all, err := s.journal.Load()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to load journal: %v", err)), nil
+	}
+	var entries []journal.Entry
+	seen := map[string]bool{}
+	var ids []string
+	for _, e := range all {
+		if !filter.matches(e) {
+			continue
+		}
+		entries = append(entries, e)
+		if !seen[e.Bet.ContractID] {
+			seen[e.Bet.ContractID] = true
+			ids = append(ids, e.Bet.ContractID)
+		}
+	}
+	if len(entries) == 0 {
+		return newStructuredResult("No journal entries found.",
+			journalReviewResponse{Groups: []*journalGroup{}, Trades: []reviewedTrade{}})
+	}
+
+	errs := s.reconcileFills(ctx, entries)
+	markets, marketErrs := s.fetchFullMarkets(ctx, ids)
+	errs = append(errs, marketErrs...)
+	trades := make([]reviewedTrade, len(entries))
+	for i, e := range entries {
+		trades[i] = reviewTrade(e, markets[e.Bet.ContractID])
+	}
+
+	resp := journalReviewResponse{Groups: groupReviewedTrades(trades), Errors: errs}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].TradedAt > trades[j].TradedAt
+	})
+	resp.Trades = trades[:min(limit, len(trades))]
+
+	return newStructuredResult(
+		fmt.Sprintf("Reviewed %d trade(s) in %d group(s).", len(resp.Trades), len(resp.Groups)), resp)
+}
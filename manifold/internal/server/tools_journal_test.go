@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/jbeshir/mcp-servers/manifold/internal/journal"
+)
+
+func TestReviewTrade_OpenBinary(t *testing.T) {
+	m := &client.FullMarket{LiteMarket: client.LiteMarket{ID: "m", Probability: floatPtr(0.6)}}
+	e := journal.Entry{
+		Action: journal.ActionBuy,
+		Bet:    client.Bet{ContractID: "m", Outcome: outcomeNo, Amount: 30, Shares: 60},
+	}
+
+	rt := reviewTrade(e, m)
+	if rt.Status != tradeStatusOpen {
+		t.Fatalf("expected open, got %s", rt.Status)
+	}
+	// 60 NO shares at 0.4 = 24, less 30 paid.
+	if rt.Pnl == nil || math.Abs(*rt.Pnl-(-6)) > 1e-9 {
+		t.Errorf("expected pnl -6, got %v", rt.Pnl)
+	}
+}
+
+func TestReviewTrade_ResolvedBinary(t *testing.T) {
+	m := &client.FullMarket{LiteMarket: client.LiteMarket{
+		ID: "m", Probability: floatPtr(0.99), IsResolved: true, Resolution: strPtr(outcomeYes),
+	}}
+
+	won := reviewTrade(journal.Entry{
+		Action: journal.ActionBuy,
+		Bet:    client.Bet{ContractID: "m", Outcome: outcomeYes, Amount: 50, Shares: 100},
+	}, m)
+	if won.Status != tradeStatusWon || *won.Pnl != 50 {
+		t.Errorf("expected won with pnl 50, got %s %v", won.Status, *won.Pnl)
+	}
+
+	lost := reviewTrade(journal.Entry{
+		Action: journal.ActionBuy,
+		Bet:    client.Bet{ContractID: "m", Outcome: outcomeNo, Amount: 50, Shares: 100},
+	}, m)
+	if lost.Status != tradeStatusLost || *lost.Pnl != -50 {
+		t.Errorf("expected lost with pnl -50, got %s %v", lost.Status, *lost.Pnl)
+	}
+}
+
+func TestReviewTrade_SellAndMKT(t *testing.T) {
+	m := &client.FullMarket{LiteMarket: client.LiteMarket{
+		ID: "m", IsResolved: true, Resolution: strPtr("MKT"), ResolutionProb: floatPtr(0.3),
+	}}
+	// Selling 100 YES shares for 60 mana: the shares would have paid 30.
+	rt := reviewTrade(journal.Entry{
+		Action: journal.ActionSell,
+		Bet:    client.Bet{ContractID: "m", Outcome: outcomeYes, Amount: -60, Shares: -100},
+	}, m)
+	if rt.Status != tradeStatusWon || math.Abs(*rt.Pnl-30) > 1e-9 {
+		t.Errorf("expected won with pnl 30, got %s %v", rt.Status, *rt.Pnl)
+	}
+}
+
+func TestReviewTrade_Answers(t *testing.T) {
+	m := &client.FullMarket{
+		LiteMarket: client.LiteMarket{ID: "m", IsResolved: true, Resolution: strPtr("b")},
+		Answers: []client.Answer{
+			{ID: "a", Probability: floatPtr(0.1)},
+			{ID: "b", Probability: floatPtr(0.9)},
+		},
+	}
+	a := reviewTrade(journal.Entry{Bet: client.Bet{
+		ContractID: "m", AnswerID: strPtr("a"), Outcome: outcomeYes, Amount: 10, Shares: 40,
+	}}, m)
+	if a.Status != tradeStatusLost || *a.Pnl != -10 {
+		t.Errorf("expected losing answer a, got %s %v", a.Status, *a.Pnl)
+	}
+	b := reviewTrade(journal.Entry{Bet: client.Bet{
+		ContractID: "m", AnswerID: strPtr("b"), Outcome: outcomeYes, Amount: 10, Shares: 40,
+	}}, m)
+	if b.Status != tradeStatusWon || *b.Pnl != 30 {
+		t.Errorf("expected winning answer b, got %s %v", b.Status, *b.Pnl)
+	}
+}
+
+func TestReviewTrade_CancelledAndMissing(t *testing.T) {
+	m := &client.FullMarket{LiteMarket: client.LiteMarket{
+		ID: "m", IsResolved: true, Resolution: strPtr(resolutionCancel),
+	}}
+	e := journal.Entry{Bet: client.Bet{ContractID: "m", Outcome: outcomeYes, Amount: 10, Shares: 20}}
+	if rt := reviewTrade(e, m); rt.Status != tradeStatusCancelled || rt.Pnl != nil {
+		t.Errorf("expected cancelled without pnl, got %s %v", rt.Status, rt.Pnl)
+	}
+	if rt := reviewTrade(e, nil); rt.Status != tradeStatusUnknown {
+		t.Errorf("expected unknown for missing market, got %s", rt.Status)
+	}
+}
+
+func TestGroupReviewedTrades(t *testing.T) {
+	pnl := func(v float64) *float64 { return &v }
+	trades := []reviewedTrade{
+		{Action: journal.ActionBuy, Tags: []string{"elections", "longshot"}, Status: tradeStatusWon,
+			Pnl: pnl(40), Confidence: floatPtr(0.8), marketValue: 1},
+		{Action: journal.ActionBuy, Tags: []string{"elections"}, Status: tradeStatusLost,
+			Pnl: pnl(-10), Confidence: floatPtr(0.6), marketValue: 0},
+		{Action: journal.ActionBuy, Tags: []string{"elections"}, Status: tradeStatusOpen, Pnl: pnl(5)},
+		{Action: journal.ActionBuy, Status: tradeStatusCancelled},
+	}
+
+	groups := groupReviewedTrades(trades)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if groups[0].Tag != "elections" || groups[1].Tag != "longshot" || groups[2].Tag != untaggedGroup {
+		t.Fatalf("unexpected group order: %s, %s, %s", groups[0].Tag, groups[1].Tag, groups[2].Tag)
+	}
+
+	g := groups[0]
+	if g.Trades != 3 || g.Won != 1 || g.Lost != 1 || g.Open != 1 {
+		t.Errorf("unexpected counts: %+v", g)
+	}
+	if g.RealizedPnl != 30 || g.UnrealizedPnl != 5 {
+		t.Errorf("expected realized 30, unrealized 5, got %f, %f", g.RealizedPnl, g.UnrealizedPnl)
+	}
+	if g.HitRate == nil || *g.HitRate != 0.5 {
+		t.Errorf("expected hit rate 0.5, got %v", g.HitRate)
+	}
+	// ((0.8-1)^2 + (0.6-0)^2) / 2 = 0.2
+	if g.BrierScore == nil || math.Abs(*g.BrierScore-0.2) > 1e-9 {
+		t.Errorf("expected brier 0.2, got %v", g.BrierScore)
+	}
+
+	if u := groups[2]; u.Cancelled != 1 || u.HitRate != nil || u.BrierScore != nil {
+		t.Errorf("unexpected untagged group: %+v", u)
+	}
+}
+
+func TestParseJournalMetadata(t *testing.T) {
+	rationale, confidence, tags, err := parseJournalMetadata(map[string]any{
+		"rationale":  "polls are stale",
+		"confidence": 0.7,
+		"tags":       "elections, polls",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rationale != "polls are stale" || *confidence != 0.7 || len(tags) != 2 || tags[1] != "polls" {
+		t.Errorf("unexpected metadata: %q %v %v", rationale, *confidence, tags)
+	}
+	if _, _, _, err := parseJournalMetadata(map[string]any{"confidence": 1.5}); err == nil {
+		t.Error("expected error for confidence above 1")
+	}
+}
+
+func TestReviewTrade_UnfilledLimitOrder(t *testing.T) {
+	m := &client.FullMarket{LiteMarket: client.LiteMarket{ID: "m", Probability: floatPtr(0.6)}}
+	e := journal.Entry{
+		Action: journal.ActionBuy,
+		Bet:    client.Bet{ContractID: "m", Outcome: outcomeYes, LimitProb: floatPtr(0.3), OrderAmount: floatPtr(50)},
+	}
+
+	rt := reviewTrade(e, m)
+	if rt.Status != tradeStatusUnfilled || rt.Pnl != nil {
+		t.Fatalf("expected unscored unfilled order, got %+v", rt)
+	}
+	groups := groupReviewedTrades([]reviewedTrade{rt})
+	if g := groups[0]; g.Unfilled != 1 || g.HitRate != nil || g.RealizedPnl != 0 || g.UnrealizedPnl != 0 {
+		t.Errorf("expected unfilled order excluded from scoring, got %+v", g)
+	}
+}
+
+func TestReconcileFills(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []url.Values
+	)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode([]client.Bet{
+			{ID: "limit", Amount: 20, Shares: 40, IsFilled: boolPtr(false)},
+			{ID: "other", Amount: 5, Shares: 5},
+		})
+	}))
+	defer api.Close()
+
+	entries := []journal.Entry{
+		{Bet: client.Bet{ID: "limit", UserID: "u", ContractID: "m", LimitProb: floatPtr(0.5), IsFilled: boolPtr(false)}},
+		{Bet: client.Bet{ID: "market", UserID: "u", ContractID: "m", Amount: 10, Shares: 15}},
+		{Bet: client.Bet{ID: "gone", UserID: "u", ContractID: "n", LimitProb: floatPtr(0.5)}},
+	}
+	s := &Server{client: client.NewClient(api.URL, "key")}
+	errs := s.reconcileFills(context.Background(), entries)
+
+	if len(queries) != 2 {
+		t.Fatalf("expected one lookup per market with open limit orders, got %d", len(queries))
+	}
+	if entries[0].Bet.Amount != 20 || entries[0].Bet.Shares != 40 {
+		t.Errorf("expected limit order fills updated, got %+v", entries[0].Bet)
+	}
+	if entries[1].Bet.Shares != 15 {
+		t.Errorf("expected market order untouched, got %+v", entries[1].Bet)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected an error for the missing order, got %v", errs)
+	}
+}
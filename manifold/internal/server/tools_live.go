@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const liveResourcePrefix = "manifold://live/"
+
+// liveResourceURI returns the resource URI for a watched market's live state.
+func liveResourceURI(contractID string) string {
+	return liveResourcePrefix + contractID
+}
+
+// liveUpdatesResponse is the JSON output for get_live_updates.
+type liveUpdatesResponse struct {
+	Cursor  int64                    `json:"cursor"`
+	Watched []client.LiveMarketState `json:"watched"`
+	Events  []client.LiveEvent       `json:"events"`
+}
+
+// liveResourceContents is the JSON body of a live market resource.
+type liveResourceContents struct {
+	State  client.LiveMarketState `json:"state"`
+	Events []client.LiveEvent     `json:"events"`
+}
+
+// splitIDs splits a comma-separated list of IDs, dropping empty entries.
+func splitIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// liveSubscriptions tracks which sessions subscribed to each live resource.
+type liveSubscriptions struct {
+	mu    sync.Mutex
+	byURI map[string]map[string]bool
+}
+
+func newLiveSubscriptions() *liveSubscriptions {
+	return &liveSubscriptions{byURI: make(map[string]map[string]bool)}
+}
+
+// subscribe records that sessionID wants updates for uri.
+func (l *liveSubscriptions) subscribe(sessionID, uri string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.byURI[uri] == nil {
+		l.byURI[uri] = make(map[string]bool)
+	}
+	l.byURI[uri][sessionID] = true
+}
+
+// unsubscribe removes sessionID's subscription to uri.
+func (l *liveSubscriptions) unsubscribe(sessionID, uri string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.byURI[uri], sessionID)
+	if len(l.byURI[uri]) == 0 {
+		delete(l.byURI, uri)
+	}
+}
+
+// removeSession drops every subscription held by sessionID.
+func (l *liveSubscriptions) removeSession(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for uri, sessions := range l.byURI {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(l.byURI, uri)
+		}
+	}
+}
+
+// sessions returns the IDs of sessions subscribed to uri, sorted.
+func (l *liveSubscriptions) sessions(uri string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make([]string, 0, len(l.byURI[uri]))
+	for id := range l.byURI[uri] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// addHooks keeps the subscriptions in step with resources/subscribe and
+// resources/unsubscribe requests and with sessions closing.
+func (l *liveSubscriptions) addHooks(hooks *server.Hooks) {
+	hooks.AddAfterSubscribe(func(ctx context.Context, _ any, req *mcp.SubscribeRequest, _ *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			l.subscribe(session.SessionID(), req.Params.URI)
+		}
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, _ any, req *mcp.UnsubscribeRequest, _ *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			l.unsubscribe(session.SessionID(), req.Params.URI)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		l.removeSession(session.SessionID())
+	})
+}
+
+// notifyLiveUpdate tells the sessions subscribed to a watched market's
+// resource that it changed.
+func (s *Server) notifyLiveUpdate(contractID string) {
+	uri := liveResourceURI(contractID)
+	for _, sessionID := range s.subs.sessions(uri) {
+		// A session that closed between lookup and send just misses the update.
+		_ = s.mcpServer.SendNotificationToSpecificClient(
+			sessionID,
+			"notifications/resources/updated",
+			map[string]any{"uri": uri},
+		)
+	}
+}
+
+func (s *Server) handleGetLiveUpdates(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	if v, ok := args["unwatch"].(string); ok && v != "" {
+		if err := s.live.Unwatch(splitIDs(v)...); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to unwatch markets: %v", err)), nil
+		}
+	}
+	if v, ok := args["watch"].(string); ok && v != "" {
+		if err := s.live.Watch(ctx, splitIDs(v)...); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to watch markets: %v", err)), nil
+		}
+	}
+
+	var cursor int64
+	if v, ok := args["cursor"].(float64); ok && v > 0 {
+		cursor = int64(v)
+	}
+	contractID, _ := args["contractId"].(string)
+
+	events, next := s.live.EventsSince(cursor, contractID)
+	watched := s.live.Watched()
+	sort.Slice(watched, func(i, j int) bool {
+		return watched[i].ContractID < watched[j].ContractID
+	})
+	resp := liveUpdatesResponse{Cursor: next, Watched: watched, Events: events}
+	if len(watched) == 0 {
+		resp.Watched = []client.LiveMarketState{}
+		return newStructuredResult("No markets are being watched. Pass watch with market IDs to start.", resp)
+	}
+	return newStructuredResult(fmt.Sprintf("%d event(s) across %d watched market(s).", len(events), len(watched)), resp)
+}
+
+func (s *Server) handleReadLiveResource(
+	_ context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	contractID := strings.TrimPrefix(uri, liveResourcePrefix)
+
+	state, ok := s.live.State(contractID)
+	if !ok {
+		return nil, fmt.Errorf("market %s is not being watched; watch it with get_live_updates", contractID)
+	}
+	events, _ := s.live.EventsSince(0, contractID)
+
+	data, err := json.MarshalIndent(liveResourceContents{State: state, Events: events}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("formatting live state: %w", err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}, nil
+}
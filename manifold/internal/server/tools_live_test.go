@@ -0,0 +1,34 @@
+package server
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLiveSubscriptions(t *testing.T) {
+	subs := newLiveSubscriptions()
+	a, b := liveResourceURI("a"), liveResourceURI("b")
+	subs.subscribe("s1", a)
+	subs.subscribe("s2", a)
+	subs.subscribe("s2", b)
+
+	if got := subs.sessions(a); !slices.Equal(got, []string{"s1", "s2"}) {
+		t.Errorf("expected both sessions subscribed to a, got %v", got)
+	}
+	if got := subs.sessions(b); !slices.Equal(got, []string{"s2"}) {
+		t.Errorf("expected only s2 subscribed to b, got %v", got)
+	}
+
+	subs.unsubscribe("s1", a)
+	if got := subs.sessions(a); !slices.Equal(got, []string{"s2"}) {
+		t.Errorf("expected s2 subscribed to a after s1 unsubscribed, got %v", got)
+	}
+
+	subs.removeSession("s2")
+	if got := subs.sessions(a); len(got) != 0 {
+		t.Errorf("expected no sessions subscribed to a after s2 closed, got %v", got)
+	}
+	if got := subs.sessions(b); len(got) != 0 {
+		t.Errorf("expected no sessions subscribed to b after s2 closed, got %v", got)
+	}
+}
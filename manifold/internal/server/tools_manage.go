@@ -71,6 +71,22 @@ func (s *Server) handleCreateMarket(
 	return formatLiteMarket(market)
 }
 
+// marketActionResult is the structured output for resolve_market,
+// close_market, and add_liquidity.
+type marketActionResult struct {
+	MarketID  string  `json:"marketId"`
+	Outcome   string  `json:"outcome,omitempty"`
+	AnswerID  string  `json:"answerId,omitempty"`
+	CloseTime *int64  `json:"closeTime,omitempty"`
+	Amount    float64 `json:"amount,omitempty"`
+}
+
+// sendManaResult is the structured output for send_mana.
+type sendManaResult struct {
+	ToIDs  []string `json:"toIds"`
+	Amount float64  `json:"amount"`
+}
+
 func (s *Server) handleResolveMarket(
 	ctx context.Context,
 	request mcp.CallToolRequest,
@@ -105,7 +121,8 @@ func (s *Server) handleResolveMarket(
 		return mcp.NewToolResultError(fmt.Sprintf("failed to resolve market: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Resolved market %s to %s", marketID, outcome)), nil
+	return newStructuredResult(fmt.Sprintf("Resolved market %s to %s", marketID, outcome),
+		marketActionResult{MarketID: marketID, Outcome: outcome, AnswerID: req.AnswerID})
 }
 
 func (s *Server) handleCloseMarket(
@@ -129,7 +146,8 @@ func (s *Server) handleCloseMarket(
 		return mcp.NewToolResultError(fmt.Sprintf("failed to close market: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Closed market %s", marketID)), nil
+	return newStructuredResult(fmt.Sprintf("Closed market %s", marketID),
+		marketActionResult{MarketID: marketID, CloseTime: req.CloseTime})
 }
 
 func (s *Server) handleAddComment(
@@ -185,7 +203,8 @@ func (s *Server) handleAddLiquidity(
 		return mcp.NewToolResultError(fmt.Sprintf("failed to add liquidity: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Added %.0f mana liquidity to market %s", amount, marketID)), nil
+	return newStructuredResult(fmt.Sprintf("Added %.0f mana liquidity to market %s", amount, marketID),
+		marketActionResult{MarketID: marketID, Amount: amount})
 }
 
 func (s *Server) handleSendMana(
@@ -221,5 +240,6 @@ func (s *Server) handleSendMana(
 		return mcp.NewToolResultError(fmt.Sprintf("failed to send mana: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Sent %.0f mana to %d user(s)", amount, len(toIDs))), nil
+	return newStructuredResult(fmt.Sprintf("Sent %.0f mana to %d user(s)", amount, len(toIDs)),
+		sendManaResult{ToIDs: toIDs, Amount: amount})
 }
@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math"
 	"net/url"
@@ -20,6 +19,10 @@ const (
 	recentResolvedDays = 7
 	outcomeYes         = "YES"
 	outcomeNo          = "NO"
+
+	outcomeTypeBinary         = "BINARY"
+	outcomeTypeMultipleChoice = "MULTIPLE_CHOICE"
+	outcomeTypePseudoNumeric  = "PSEUDO_NUMERIC"
 )
 
 // BaselineResult holds a computed baseline probability and an optional warning.
@@ -140,46 +143,51 @@ func (s *Server) handleGetBaseline(
 		Warning:      result.Warning,
 	}
 
-	data, err := json.MarshalIndent(resp, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-	}
-	return mcp.NewToolResultText(string(data)), nil
+	return newStructuredResult(fmt.Sprintf("%.1f%% now vs %.1f%% baseline (%+.1fpp).",
+		resp.CurrentProb*100, resp.BaselineProb*100, resp.ChangePp), resp)
 }
 
 // portfolioSummary is the top-level summary in the portfolio response.
 type portfolioSummary struct {
-	OpenPnl           float64 `json:"openPnl"`
-	RecentResolvedPnl float64 `json:"recentResolvedPnl"`
-	CombinedPnl       float64 `json:"combinedPnl"`
-	PositionCount     int     `json:"positionCount"`
-	ExcludedNonBinary int     `json:"excludedNonBinary"`
+	OpenPnl             float64 `json:"openPnl"`
+	RecentResolvedPnl   float64 `json:"recentResolvedPnl"`
+	CombinedPnl         float64 `json:"combinedPnl"`
+	PositionCount       int     `json:"positionCount"`
+	ExcludedUnsupported int     `json:"excludedUnsupported"`
 }
 
 // portfolioMover is a market with significant movement in the lookback window.
 type portfolioMover struct {
-	Question    string  `json:"question"`
-	URL         string  `json:"url"`
-	Outcome     string  `json:"outcome"`
-	ChangePp    float64 `json:"changePp"`
-	BaselinePnl float64 `json:"baselinePnl"`
-	CurrentProb float64 `json:"currentProb"`
+	Question      string   `json:"question"`
+	URL           string   `json:"url"`
+	Answer        string   `json:"answer,omitempty"`
+	Outcome       string   `json:"outcome"`
+	ChangePp      float64  `json:"changePp"`
+	BaselinePnl   float64  `json:"baselinePnl"`
+	CurrentProb   float64  `json:"currentProb"`
+	ExpectedValue *float64 `json:"expectedValue,omitempty"`
 }
 
-// portfolioPosition is a single open position.
+// portfolioPosition is a single open position. For multiple choice markets
+// each answer held is a separate position; for PSEUDO_NUMERIC markets the
+// probabilities are also mapped onto the market's numeric range.
 type portfolioPosition struct {
-	ContractID   string  `json:"contractId"`
-	Question     string  `json:"question"`
-	URL          string  `json:"url"`
-	Outcome      string  `json:"outcome"`
-	Shares       float64 `json:"shares"`
-	CostBasis    float64 `json:"costBasis"`
-	CurrentProb  float64 `json:"currentProb"`
-	CurrentValue float64 `json:"currentValue"`
-	Pnl          float64 `json:"pnl"`
-	BaselineProb float64 `json:"baselineProb"`
-	ChangePp     float64 `json:"changePp"`
-	BaselinePnl  float64 `json:"baselinePnl"`
+	ContractID            string   `json:"contractId"`
+	Question              string   `json:"question"`
+	URL                   string   `json:"url"`
+	AnswerID              string   `json:"answerId,omitempty"`
+	Answer                string   `json:"answer,omitempty"`
+	Outcome               string   `json:"outcome"`
+	Shares                float64  `json:"shares"`
+	CostBasis             float64  `json:"costBasis"`
+	CurrentProb           float64  `json:"currentProb"`
+	CurrentValue          float64  `json:"currentValue"`
+	Pnl                   float64  `json:"pnl"`
+	BaselineProb          float64  `json:"baselineProb"`
+	ChangePp              float64  `json:"changePp"`
+	BaselinePnl           float64  `json:"baselinePnl"`
+	ExpectedValue         *float64 `json:"expectedValue,omitempty"`
+	BaselineExpectedValue *float64 `json:"baselineExpectedValue,omitempty"`
 }
 
 // portfolioResolved is a recently resolved position.
@@ -187,6 +195,8 @@ type portfolioResolved struct {
 	ContractID string  `json:"contractId"`
 	Question   string  `json:"question"`
 	URL        string  `json:"url"`
+	AnswerID   string  `json:"answerId,omitempty"`
+	Answer     string  `json:"answer,omitempty"`
 	Outcome    string  `json:"outcome"`
 	Resolution string  `json:"resolution"`
 	Pnl        float64 `json:"pnl"`
@@ -226,11 +236,8 @@ func (s *Server) handleGetPortfolioPnl(
 
 	resp := buildPortfolioResponse(enriched, resolvedLookback)
 
-	data, err := json.MarshalIndent(resp, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-	}
-	return mcp.NewToolResultText(string(data)), nil
+	return newStructuredResult(fmt.Sprintf("%d position(s), %d mover(s), %d recently resolved.",
+		len(resp.Positions), len(resp.Movers), len(resp.RecentResolved)), resp)
 }
 
 const bulkFetchLimit = 1000
@@ -294,31 +301,111 @@ func positionBaselinePnl(shares, currentProb, baseline float64, outcome string)
 	return shares * (baseline - currentProb), -(currentProb - baseline) * 100
 }
 
+// numericValue maps a PSEUDO_NUMERIC market probability onto the market's
+// value range, mirroring Manifold's linear and log-scale mappings.
+func numericValue(prob, minVal, maxVal float64, isLogScale bool) float64 {
+	if isLogScale {
+		return math.Pow(10, prob*math.Log10(maxVal-minVal+1)) + minVal - 1
+	}
+	return minVal + prob*(maxVal-minVal)
+}
+
+// pricedOutcome is the probability-bearing unit a position is valued against:
+// the whole market for binary and numeric markets, or a single answer for
+// multiple choice markets.
+type pricedOutcome struct {
+	prob           float64
+	baseline       float64
+	answerID       string
+	answerText     string
+	isResolved     bool
+	resolution     *string
+	resolutionTime *int64
+}
+
+// marketOutcome builds the pricedOutcome for a single-probability market.
+func marketOutcome(m *client.PortfolioMarket) pricedOutcome {
+	return pricedOutcome{
+		prob:           *m.Prob,
+		baseline:       baselineProbFromMarket(m),
+		isResolved:     m.IsResolved,
+		resolution:     m.Resolution,
+		resolutionTime: m.ResolutionTime,
+	}
+}
+
+// answerOutcome builds the pricedOutcome for one answer of a multiple choice
+// market. Answers can resolve individually before the market as a whole, so
+// the answer's own resolution takes precedence over the market's.
+func answerOutcome(m *client.PortfolioMarket, a *client.Answer) (pricedOutcome, bool) {
+	prob := a.CurrentProb()
+	if prob == nil {
+		return pricedOutcome{}, false
+	}
+	baseline := *prob
+	if a.ProbChanges != nil {
+		baseline -= a.ProbChanges.Day
+	}
+	out := pricedOutcome{
+		prob:           *prob,
+		baseline:       baseline,
+		answerID:       a.ID,
+		answerText:     a.Text,
+		isResolved:     m.IsResolved,
+		resolution:     m.Resolution,
+		resolutionTime: m.ResolutionTime,
+	}
+	if a.Resolution != nil {
+		out.isResolved = true
+		out.resolution = a.Resolution
+		if a.ResolutionTime != nil {
+			out.resolutionTime = a.ResolutionTime
+		}
+	}
+	return out, true
+}
+
+// numericExpectedValues returns the current and baseline expected values of a
+// PSEUDO_NUMERIC market, or nils for any other market type.
+func numericExpectedValues(m *client.PortfolioMarket, po pricedOutcome) (current, baseline *float64) {
+	if m.OutcomeType != outcomeTypePseudoNumeric || m.Min == nil || m.Max == nil {
+		return nil, nil
+	}
+	isLog := m.IsLogScale != nil && *m.IsLogScale
+	cur := numericValue(po.prob, *m.Min, *m.Max, isLog)
+	base := numericValue(po.baseline, *m.Min, *m.Max, isLog)
+	return &cur, &base
+}
+
 // processOpenPosition builds a portfolioPosition for an open market position.
 func processOpenPosition(
 	m *client.PortfolioMarket,
+	po pricedOutcome,
 	outcome string,
 	shares float64,
 	profit float64,
-	baselineProb float64,
 ) portfolioPosition {
-	currentProb := *m.Prob
-	currentValue := positionValue(shares, currentProb, outcome)
-	baselinePnl, changePp := positionBaselinePnl(shares, currentProb, baselineProb, outcome)
+	currentValue := positionValue(shares, po.prob, outcome)
+	baselinePnl, changePp := positionBaselinePnl(shares, po.prob, po.baseline, outcome)
+	expected, baselineExpected := numericExpectedValues(m, po)
 
 	return portfolioPosition{
-		ContractID:   m.ID,
-		Question:     m.Question,
-		URL:          m.URL(),
-		Outcome:      outcome,
-		Shares:       shares,
-		CostBasis:    currentValue - profit,
-		CurrentProb:  currentProb,
-		CurrentValue: currentValue,
-		Pnl:          profit,
-		BaselineProb: baselineProb,
-		ChangePp:     changePp,
-		BaselinePnl:  baselinePnl,
+		ContractID:            m.ID,
+		Question:              m.Question,
+		URL:                   m.URL(),
+		AnswerID:              po.answerID,
+		Answer:                po.answerText,
+		Outcome:               outcome,
+		Shares:                shares,
+		CostBasis:             currentValue - profit,
+		CurrentProb:           po.prob,
+		CurrentValue:          currentValue,
+		Pnl:                   profit,
+		BaselineProb:          po.baseline,
+		ChangePp:              changePp,
+		BaselinePnl:           baselinePnl,
+		ExpectedValue:         expected,
+		BaselineExpectedValue: baselineExpected,
 	}
 }
 
@@ -337,103 +424,149 @@ type portfolioAccumulator struct {
 	movers         []portfolioMover
 	openPnl        float64
 	resolvedPnl    float64
-	nonBinary      int
+	unsupported    int
 }
 
 func (a *portfolioAccumulator) processMarket(em *enrichedMarket, resolvedLookback time.Duration) {
 	m := &em.market
 
-	if m.OutcomeType != "BINARY" || m.Prob == nil {
-		a.nonBinary++
-		return
+	switch m.OutcomeType {
+	case outcomeTypeBinary, outcomeTypePseudoNumeric:
+		if m.Prob == nil {
+			a.unsupported++
+			return
+		}
+		po := marketOutcome(m)
+		for _, pos := range em.positions {
+			a.processMetric(m, po, pos, resolvedLookback)
+		}
+	case outcomeTypeMultipleChoice:
+		a.processMultipleChoice(em, resolvedLookback)
+	default:
+		a.unsupported++
 	}
+}
 
-	baseline := baselineProbFromMarket(m)
+// processMultipleChoice values each answer-level position against its own
+// answer's probability. Metrics without an answer ID are Manifold's
+// contract-wide summary rows and would double count the answer positions.
+func (a *portfolioAccumulator) processMultipleChoice(em *enrichedMarket, resolvedLookback time.Duration) {
+	m := &em.market
+
+	answers := make(map[string]*client.Answer, len(m.Answers))
+	for i := range m.Answers {
+		answers[m.Answers[i].ID] = &m.Answers[i]
+	}
 
 	for _, pos := range em.positions {
-		if !pos.HasYesShares && !pos.HasNoShares {
+		if pos.AnswerID == nil {
 			continue
 		}
-
-		outcome := outcomeYes
-		if pos.HasNoShares && !pos.HasYesShares {
-			outcome = outcomeNo
-		}
-
-		shares := pos.TotalShares[outcome]
-		if shares < 0.5 {
+		answer, ok := answers[*pos.AnswerID]
+		if !ok {
 			continue
 		}
-
-		if m.IsResolved {
-			a.processResolved(m, outcome, pos.Profit, resolvedLookback)
+		po, ok := answerOutcome(m, answer)
+		if !ok {
 			continue
 		}
+		a.processMetric(m, po, pos, resolvedLookback)
+	}
+}
 
-		a.processOpen(m, pos, outcome, shares, baseline)
+func (a *portfolioAccumulator) processMetric(
+	m *client.PortfolioMarket,
+	po pricedOutcome,
+	pos client.ContractMetric,
+	resolvedLookback time.Duration,
+) {
+	if !pos.HasYesShares && !pos.HasNoShares {
+		return
+	}
+
+	outcome := outcomeYes
+	if pos.HasNoShares && !pos.HasYesShares {
+		outcome = outcomeNo
+	}
+
+	shares := pos.TotalShares[outcome]
+	if shares < 0.5 {
+		return
+	}
+
+	if po.isResolved {
+		a.processResolved(m, po, outcome, pos.Profit, resolvedLookback)
+		return
 	}
+
+	a.processOpen(m, po, pos, outcome, shares)
 }
 
 func (a *portfolioAccumulator) processResolved(
 	m *client.PortfolioMarket,
+	po pricedOutcome,
 	outcome string,
 	profit float64,
 	resolvedLookback time.Duration,
 ) {
 	resolvedCutoffMs := time.Now().UnixMilli() - resolvedLookback.Milliseconds()
-	if m.ResolutionTime == nil || *m.ResolutionTime <= resolvedCutoffMs {
+	if po.resolutionTime == nil || *po.resolutionTime <= resolvedCutoffMs {
 		return
 	}
 	resolution := ""
-	if m.Resolution != nil {
-		resolution = *m.Resolution
+	if po.resolution != nil {
+		resolution = *po.resolution
 	}
 	a.recentResolved = append(a.recentResolved, portfolioResolved{
 		ContractID: m.ID,
 		Question:   m.Question,
 		URL:        m.URL(),
+		AnswerID:   po.answerID,
+		Answer:     po.answerText,
 		Outcome:    outcome,
 		Resolution: resolution,
 		Pnl:        profit,
-		ResolvedAt: *m.ResolutionTime,
+		ResolvedAt: *po.resolutionTime,
 	})
 	a.resolvedPnl += profit
 }
 
 func (a *portfolioAccumulator) processOpen(
 	m *client.PortfolioMarket,
+	po pricedOutcome,
 	pos client.ContractMetric,
 	outcome string,
 	shares float64,
-	baselineProb float64,
 ) {
-	pp := processOpenPosition(m, outcome, shares, pos.Profit, baselineProb)
+	pp := processOpenPosition(m, po, outcome, shares, pos.Profit)
 	a.positions = append(a.positions, pp)
 	a.openPnl += pos.Profit
 
 	if math.Abs(pp.ChangePp) >= moverThresholdPp {
 		a.movers = append(a.movers, portfolioMover{
-			Question:    m.Question,
-			URL:         m.URL(),
-			Outcome:     outcome,
-			ChangePp:    pp.ChangePp,
-			BaselinePnl: pp.BaselinePnl,
-			CurrentProb: *m.Prob,
+			Question:      m.Question,
+			URL:           m.URL(),
+			Answer:        po.answerText,
+			Outcome:       outcome,
+			ChangePp:      pp.ChangePp,
+			BaselinePnl:   pp.BaselinePnl,
+			CurrentProb:   po.prob,
+			ExpectedValue: pp.ExpectedValue,
 		})
 	}
 
 	// Handle dual positions (user holds both YES and NO).
 	if pos.HasYesShares && pos.HasNoShares {
-		a.processDualPosition(m, pos, outcome, pp.CurrentValue, baselineProb)
+		a.processDualPosition(m, po, pos, outcome, pp.CurrentValue)
 	}
 }
 
 func (a *portfolioAccumulator) processDualPosition(
 	m *client.PortfolioMarket,
+	po pricedOutcome,
 	pos client.ContractMetric,
 	primaryOutcome string,
 	primaryValue float64,
-	baselineProb float64,
 ) {
 	otherOutcome := outcomeNo
 	if primaryOutcome == outcomeNo {
@@ -444,7 +577,7 @@ func (a *portfolioAccumulator) processDualPosition(
 		return
 	}
 
-	otherValue := positionValue(otherShares, *m.Prob, otherOutcome)
+	otherValue := positionValue(otherShares, po.prob, otherOutcome)
 
 	// Split profit proportionally by value.
 	totalValue := primaryValue + otherValue
@@ -453,7 +586,7 @@ func (a *portfolioAccumulator) processDualPosition(
 		otherPnl = pos.Profit * (otherValue / totalValue)
 	}
 
-	otherPos := processOpenPosition(m, otherOutcome, otherShares, otherPnl, baselineProb)
+	otherPos := processOpenPosition(m, po, otherOutcome, otherShares, otherPnl)
 	a.positions = append(a.positions, otherPos)
 }
 
@@ -479,11 +612,11 @@ func buildPortfolioResponse(
 
 	return portfolioResponse{
 		Summary: portfolioSummary{
-			OpenPnl:           acc.openPnl,
-			RecentResolvedPnl: acc.resolvedPnl,
-			CombinedPnl:       acc.openPnl + acc.resolvedPnl,
-			PositionCount:     len(acc.positions),
-			ExcludedNonBinary: acc.nonBinary,
+			OpenPnl:             acc.openPnl,
+			RecentResolvedPnl:   acc.resolvedPnl,
+			CombinedPnl:         acc.openPnl + acc.resolvedPnl,
+			PositionCount:       len(acc.positions),
+			ExcludedUnsupported: acc.unsupported,
 		},
 		Movers:         acc.movers,
 		Positions:      acc.positions,
@@ -158,28 +158,193 @@ func TestBuildPortfolioResponse_BasicOpenPosition(t *testing.T) {
 	}
 }
 
-func TestBuildPortfolioResponse_NonBinaryExcluded(t *testing.T) {
+func TestBuildPortfolioResponse_UnsupportedExcluded(t *testing.T) {
 	enriched := map[string]*enrichedMarket{
-		"multi": {
+		"poll": {
 			market: client.PortfolioMarket{
-				ID:          "multi",
-				OutcomeType: "MULTIPLE_CHOICE",
+				ID:          "poll",
+				OutcomeType: "POLL",
 			},
 			positions: []client.ContractMetric{
-				{ContractID: "multi", HasYesShares: true, TotalShares: map[string]float64{outcomeYes: 50}},
+				{ContractID: "poll", HasYesShares: true, TotalShares: map[string]float64{outcomeYes: 50}},
 			},
 		},
 	}
 
 	resp := buildPortfolioResponse(enriched, 3*24*time.Hour)
-	if resp.Summary.ExcludedNonBinary != 1 {
-		t.Errorf("expected 1 excluded non-binary, got %d", resp.Summary.ExcludedNonBinary)
+	if resp.Summary.ExcludedUnsupported != 1 {
+		t.Errorf("expected 1 excluded unsupported, got %d", resp.Summary.ExcludedUnsupported)
 	}
 	if resp.Summary.PositionCount != 0 {
 		t.Errorf("expected 0 positions, got %d", resp.Summary.PositionCount)
 	}
 }
 
+func TestBuildPortfolioResponse_MultipleChoiceAnswers(t *testing.T) {
+	probA, probB := 0.30, 0.70
+	answerA, answerB := "ans-a", "ans-b"
+	enriched := map[string]*enrichedMarket{
+		"multi": {
+			market: client.PortfolioMarket{
+				ID:          "multi",
+				Question:    "Who wins?",
+				Slug:        "who-wins",
+				OutcomeType: "MULTIPLE_CHOICE",
+				Answers: []client.Answer{
+					{
+						ID:   answerA,
+						Text: "Alice",
+						AnswerExtraFields: client.AnswerExtraFields{
+							Prob:        &probA,
+							ProbChanges: &client.ProbChanges{Day: -0.10},
+						},
+					},
+					{ID: answerB, Text: "Bob", Probability: &probB},
+				},
+			},
+			positions: []client.ContractMetric{
+				// Contract-wide summary row; must not be counted.
+				{ContractID: "multi", HasYesShares: true, TotalShares: map[string]float64{outcomeYes: 500}, Profit: 99},
+				{
+					ContractID:   "multi",
+					AnswerID:     &answerA,
+					HasYesShares: true,
+					TotalShares:  map[string]float64{outcomeYes: 100},
+					Profit:       -5,
+				},
+				{
+					ContractID:  "multi",
+					AnswerID:    &answerB,
+					HasNoShares: true,
+					TotalShares: map[string]float64{outcomeNo: 50},
+					Profit:      3,
+				},
+			},
+		},
+	}
+
+	resp := buildPortfolioResponse(enriched, 7*24*time.Hour)
+	if resp.Summary.ExcludedUnsupported != 0 {
+		t.Errorf("expected no excluded markets, got %d", resp.Summary.ExcludedUnsupported)
+	}
+	if len(resp.Positions) != 2 {
+		t.Fatalf("expected 2 answer positions, got %d", len(resp.Positions))
+	}
+	if math.Abs(resp.Summary.OpenPnl-(-2)) > 0.0001 {
+		t.Errorf("expected open P&L -2, got %f", resp.Summary.OpenPnl)
+	}
+
+	byAnswer := map[string]portfolioPosition{}
+	for _, p := range resp.Positions {
+		byAnswer[p.AnswerID] = p
+	}
+	// Alice YES: 100 * 0.30 = 30, baseline 0.40 so -10pp.
+	alice := byAnswer[answerA]
+	if alice.Answer != "Alice" || math.Abs(alice.CurrentValue-30) > 0.0001 {
+		t.Errorf("unexpected Alice position: %+v", alice)
+	}
+	if math.Abs(alice.ChangePp-(-10)) > 0.01 {
+		t.Errorf("expected Alice changePp ~-10, got %f", alice.ChangePp)
+	}
+	// Bob NO: 50 * (1 - 0.70) = 15, no probChanges so no movement.
+	bob := byAnswer[answerB]
+	if math.Abs(bob.CurrentValue-15) > 0.0001 || bob.ChangePp != 0 {
+		t.Errorf("unexpected Bob position: %+v", bob)
+	}
+
+	if len(resp.Movers) != 1 || resp.Movers[0].Answer != "Alice" {
+		t.Errorf("expected Alice as the only mover, got %+v", resp.Movers)
+	}
+}
+
+func TestBuildPortfolioResponse_MultipleChoiceAnswerResolved(t *testing.T) {
+	prob := 1.0
+	answerID := "ans-a"
+	resolution := outcomeYes
+	resolvedAt := time.Now().UnixMilli() - 1000
+	enriched := map[string]*enrichedMarket{
+		"multi": {
+			market: client.PortfolioMarket{
+				ID:          "multi",
+				OutcomeType: "MULTIPLE_CHOICE",
+				Answers: []client.Answer{{
+					ID:             answerID,
+					Text:           "Alice",
+					Probability:    &prob,
+					Resolution:     &resolution,
+					ResolutionTime: &resolvedAt,
+				}},
+			},
+			positions: []client.ContractMetric{{
+				ContractID:   "multi",
+				AnswerID:     &answerID,
+				HasYesShares: true,
+				TotalShares:  map[string]float64{outcomeYes: 10},
+				Profit:       4,
+			}},
+		},
+	}
+
+	resp := buildPortfolioResponse(enriched, 7*24*time.Hour)
+	if len(resp.Positions) != 0 {
+		t.Errorf("expected resolved answer not to be an open position, got %d", len(resp.Positions))
+	}
+	if len(resp.RecentResolved) != 1 || resp.RecentResolved[0].AnswerID != answerID {
+		t.Fatalf("expected resolved answer position, got %+v", resp.RecentResolved)
+	}
+	if resp.Summary.RecentResolvedPnl != 4 {
+		t.Errorf("expected resolved total 4, got %f", resp.Summary.RecentResolvedPnl)
+	}
+}
+
+func TestBuildPortfolioResponse_PseudoNumeric(t *testing.T) {
+	prob := 0.25
+	minVal, maxVal := 0.0, 200.0
+	enriched := map[string]*enrichedMarket{
+		"num": {
+			market: client.PortfolioMarket{
+				ID:          "num",
+				OutcomeType: "PSEUDO_NUMERIC",
+				Prob:        &prob,
+				ProbChanges: client.ProbChanges{Day: -0.05},
+				Min:         &minVal,
+				Max:         &maxVal,
+			},
+			positions: []client.ContractMetric{{
+				ContractID:   "num",
+				HasYesShares: true,
+				TotalShares:  map[string]float64{outcomeYes: 40},
+				Profit:       1,
+			}},
+		},
+	}
+
+	resp := buildPortfolioResponse(enriched, 7*24*time.Hour)
+	if len(resp.Positions) != 1 {
+		t.Fatalf("expected 1 position, got %d", len(resp.Positions))
+	}
+	pos := resp.Positions[0]
+	if math.Abs(pos.CurrentValue-10) > 0.0001 {
+		t.Errorf("expected current value 10, got %f", pos.CurrentValue)
+	}
+	if pos.ExpectedValue == nil || math.Abs(*pos.ExpectedValue-50) > 0.0001 {
+		t.Errorf("expected expected value 50, got %v", pos.ExpectedValue)
+	}
+	if pos.BaselineExpectedValue == nil || math.Abs(*pos.BaselineExpectedValue-60) > 0.0001 {
+		t.Errorf("expected baseline expected value 60, got %v", pos.BaselineExpectedValue)
+	}
+}
+
+func TestNumericValue_LogScale(t *testing.T) {
+	// Manifold's log mapping: 10^(p*log10(max-min+1)) + min - 1.
+	if got := numericValue(0.5, 0, 99, true); math.Abs(got-9) > 0.0001 {
+		t.Errorf("expected 9, got %f", got)
+	}
+	if got := numericValue(1, 0, 99, true); math.Abs(got-99) > 0.0001 {
+		t.Errorf("expected 99, got %f", got)
+	}
+}
+
 func TestBuildPortfolioResponse_RecentResolved(t *testing.T) {
 	prob := 1.0
 	resolution := outcomeYes
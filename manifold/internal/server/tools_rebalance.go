@@ -0,0 +1,509 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/jbeshir/mcp-servers/manifold/internal/journal"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// sellSearchIterations bounds the simulated sells used to find a sale
+	// size, locating it to within 1/4096 of the position.
+	sellSearchIterations = 12
+	// saleValueIterations bounds the bisection for a sale's proceeds.
+	saleValueIterations = 60
+	minSellShares       = 0.5
+
+	sellTargetValue    = "value"
+	sellTargetExposure = "exposure"
+	sellTargetProb     = "probability"
+)
+
+// sellTarget is the point at which a position has been reduced enough.
+type sellTarget struct {
+	kind   string
+	amount float64
+}
+
+// reached reports whether holding held-sold shares, with the market at
+// probAfter, satisfies the target. Value is the remaining shares at the
+// post-sale price; exposure is the remaining shares, i.e. the payout if the
+// outcome wins. Selling YES pushes the probability down and selling NO pushes
+// it up, so a probability target is reached once it has been crossed.
+func (t sellTarget) reached(held, sold float64, outcome string, probAfter float64) bool {
+	switch t.kind {
+	case sellTargetValue:
+		return positionValue(held-sold, probAfter, outcome) <= t.amount
+	case sellTargetExposure:
+		return held-sold <= t.amount
+	case sellTargetProb:
+		if outcome == outcomeYes {
+			return probAfter <= t.amount
+		}
+		return probAfter >= t.amount
+	}
+	return true
+}
+
+// parseSellTarget reads exactly one of targetValue, targetExposure, or targetProb.
+func parseSellTarget(args map[string]any) (sellTarget, error) {
+	var targets []sellTarget
+	if v, ok := args["targetValue"].(float64); ok {
+		targets = append(targets, sellTarget{kind: sellTargetValue, amount: v})
+	}
+	if v, ok := args["targetExposure"].(float64); ok {
+		targets = append(targets, sellTarget{kind: sellTargetExposure, amount: v})
+	}
+	if v, ok := args["targetProb"].(float64); ok {
+		if v <= 0 || v >= 1 {
+			return sellTarget{}, fmt.Errorf("targetProb must be between 0 and 1")
+		}
+		targets = append(targets, sellTarget{kind: sellTargetProb, amount: v})
+	}
+	if len(targets) != 1 {
+		return sellTarget{}, fmt.Errorf("exactly one of targetValue, targetExposure, or targetProb is required")
+	}
+	if targets[0].amount < 0 {
+		return sellTarget{}, fmt.Errorf("target must not be negative")
+	}
+	return targets[0], nil
+}
+
+// cpmmPool is the liquidity pool of a binary market or of one answer of a
+// multiple choice market. Answer pools are weighted evenly.
+type cpmmPool struct {
+	yes, no, p float64
+}
+
+// poolFromJSON reads a pool's YES and NO share counts from an API response.
+func poolFromJSON(raw any, p float64) (cpmmPool, bool) {
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return cpmmPool{}, false
+	}
+	yes, yesOK := m[outcomeYes].(float64)
+	no, noOK := m[outcomeNo].(float64)
+	if !yesOK || !noOK || yes <= 0 || no <= 0 || p <= 0 || p >= 1 {
+		return cpmmPool{}, false
+	}
+	return cpmmPool{yes: yes, no: no, p: p}, true
+}
+
+// marketPool returns the pool a sale of answerID's shares (or the market's,
+// if answerID is empty) trades against.
+func marketPool(m *client.FullMarket, answerID string) (cpmmPool, error) {
+	if answerID == "" {
+		if m.P == nil {
+			return cpmmPool{}, fmt.Errorf("market %s has no CPMM pool", m.ID)
+		}
+		if pool, ok := poolFromJSON(m.Pool, *m.P); ok {
+			return pool, nil
+		}
+		return cpmmPool{}, fmt.Errorf("market %s has no CPMM pool", m.ID)
+	}
+	for i := range m.Answers {
+		if m.Answers[i].ID != answerID {
+			continue
+		}
+		if pool, ok := poolFromJSON(m.Answers[i].Pool, 0.5); ok {
+			return pool, nil
+		}
+		return cpmmPool{}, fmt.Errorf("answer %s has no CPMM pool", answerID)
+	}
+	return cpmmPool{}, fmt.Errorf("answer %s not found in market %s", answerID, m.ID)
+}
+
+func (c cpmmPool) prob() float64 {
+	return c.p * c.no / (c.p*c.no + (1-c.p)*c.yes)
+}
+
+func (c cpmmPool) k() float64 {
+	return math.Pow(c.yes, c.p) * math.Pow(c.no, 1-c.p)
+}
+
+// cpmmSale is a simulated sale of shares back to a pool.
+type cpmmSale struct {
+	proceeds  float64
+	probAfter float64
+}
+
+// sell simulates selling shares of outcome back to the pool, as Manifold
+// does: the shares are added to the pool and mana is withdrawn from both
+// sides until the pool's invariant is restored. Proceeds are after an
+// estimated taker fee at the average of the prices before and after.
+func (c cpmmPool) sell(shares float64, outcome string) cpmmSale {
+	k := c.k()
+	yes, no := c.yes, c.no
+	if outcome == outcomeYes {
+		yes += shares
+	} else {
+		no += shares
+	}
+	lo, hi := 0.0, min(yes, no)
+	for range saleValueIterations {
+		mid := (lo + hi) / 2
+		after := cpmmPool{yes: yes - mid, no: no - mid, p: c.p}
+		if after.k() >= k {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	after := cpmmPool{yes: yes - lo, no: no - lo, p: c.p}
+
+	probBefore, probAfter := c.prob(), after.prob()
+	avg := (probBefore + probAfter) / 2
+	fee := takerFeeConstant * avg * (1 - avg) * shares
+	return cpmmSale{proceeds: max(lo-fee, 0), probAfter: probAfter}
+}
+
+// solveSellShares finds the fewest shares to sell that reach the target,
+// bisecting over simulated sells since each sale moves the price. It
+// returns the share count, the simulated sale, and whether the target is
+// reachable; if it is not, the whole position is sold.
+func solveSellShares(held float64, outcome string, target sellTarget, pool cpmmPool) (float64, cpmmSale, bool) {
+	if target.kind == sellTargetExposure {
+		shares := held - target.amount
+		return shares, pool.sell(shares, outcome), true
+	}
+
+	all := pool.sell(held, outcome)
+	if !target.reached(held, held, outcome, all.probAfter) {
+		return held, all, false
+	}
+
+	lo, hi, best := 0.0, held, all
+	for range sellSearchIterations {
+		mid := (lo + hi) / 2
+		sale := pool.sell(mid, outcome)
+		if target.reached(held, mid, outcome, sale.probAfter) {
+			hi, best = mid, sale
+		} else {
+			lo = mid
+		}
+	}
+	return hi, best, true
+}
+
+// sellPosition is a single held outcome to reduce.
+type sellPosition struct {
+	contractID  string
+	question    string
+	answerID    string
+	outcome     string
+	shares      float64
+	prob        float64
+	pool        cpmmPool
+	approximate bool
+}
+
+// reduceResult is the outcome (or preview) of reducing one position.
+type reduceResult struct {
+	ContractID    string      `json:"contractId"`
+	Question      string      `json:"question,omitempty"`
+	AnswerID      string      `json:"answerId,omitempty"`
+	Outcome       string      `json:"outcome"`
+	SharesHeld    float64     `json:"sharesHeld"`
+	SharesToSell  float64     `json:"sharesToSell"`
+	ProbBefore    float64     `json:"probBefore"`
+	ProbAfter     float64     `json:"probAfter"`
+	ValueBefore   float64     `json:"valueBefore"`
+	ValueAfter    float64     `json:"valueAfter"`
+	Proceeds      float64     `json:"proceeds"`
+	TargetReached bool        `json:"targetReached"`
+	DryRun        bool        `json:"dryRun"`
+	Bet           *client.Bet `json:"bet,omitempty"`
+	Warning       string      `json:"warning,omitempty"`
+	Error         string      `json:"error,omitempty"`
+}
+
+// reducePosition sells down a position until the target is reached, or only
+// previews the sale if dryRun is set. Executed sales are journaled with any
+// journal metadata in args.
+func (s *Server) reducePosition(
+	ctx context.Context, pos sellPosition, target sellTarget, dryRun bool, args map[string]any,
+) reduceResult {
+	res := reduceResult{
+		ContractID:    pos.contractID,
+		Question:      pos.question,
+		AnswerID:      pos.answerID,
+		Outcome:       pos.outcome,
+		SharesHeld:    pos.shares,
+		ProbBefore:    pos.prob,
+		ProbAfter:     pos.prob,
+		ValueBefore:   positionValue(pos.shares, pos.prob, pos.outcome),
+		TargetReached: true,
+		DryRun:        dryRun,
+	}
+	res.ValueAfter = res.ValueBefore
+	if target.reached(pos.shares, 0, pos.outcome, pos.prob) {
+		return res
+	}
+
+	shares, preview, reached := solveSellShares(pos.shares, pos.outcome, target, pos.pool)
+	res.SharesToSell = shares
+	res.ProbAfter = preview.probAfter
+	res.ValueAfter = positionValue(pos.shares-shares, preview.probAfter, pos.outcome)
+	res.Proceeds = preview.proceeds
+	res.TargetReached = reached
+	if pos.approximate {
+		res.Warning = "answers sum to one, so the simulated sale ignores the rebalancing of other answers"
+	}
+	if dryRun || shares < minSellShares {
+		return res
+	}
+
+	bet, err := s.client.SellShares(ctx, pos.contractID, client.SellSharesRequest{
+		Outcome:  pos.outcome,
+		Shares:   &shares,
+		AnswerID: pos.answerID,
+	})
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Bet = bet
+	res.ProbAfter = bet.ProbAfter
+	res.ValueAfter = positionValue(pos.shares-shares, bet.ProbAfter, pos.outcome)
+	res.Proceeds = -bet.Amount
+	res.Warning = s.recordTrade(journal.ActionSell, bet, args)
+	return res
+}
+
+// trimPosition fetches a position's market just before reducing it, so the
+// simulated sale runs against a pool that reflects any earlier trims.
+func (s *Server) trimPosition(
+	ctx context.Context, pos sellPosition, target sellTarget, dryRun bool, args map[string]any,
+) reduceResult {
+	m, err := s.client.GetMarket(ctx, pos.contractID)
+	if err == nil {
+		pos.pool, pos.approximate, err = positionPool(m, pos.answerID)
+	}
+	if err != nil {
+		return reduceResult{
+			ContractID: pos.contractID,
+			Question:   pos.question,
+			AnswerID:   pos.answerID,
+			Outcome:    pos.outcome,
+			SharesHeld: pos.shares,
+			ProbBefore: pos.prob,
+			DryRun:     dryRun,
+			Error:      err.Error(),
+		}
+	}
+	return s.reducePosition(ctx, pos, target, dryRun, args)
+}
+
+// positionPool returns the pool a sale in the market or answer trades
+// against, and whether simulating against it alone is only approximate:
+// answers that sum to one are rebalanced against each other on every sale.
+func positionPool(m *client.FullMarket, answerID string) (cpmmPool, bool, error) {
+	pool, err := marketPool(m, answerID)
+	approximate := answerID != "" && m.ShouldAnswersSumToOne != nil && *m.ShouldAnswersSumToOne
+	return pool, approximate, err
+}
+
+// findSellPosition locates the user's holding in a market (or one of its
+// answers). Without an explicit outcome, the side with more shares is used.
+func findSellPosition(
+	m *client.FullMarket, metrics []client.ContractMetric, answerID, outcome string,
+) (sellPosition, error) {
+	pos := sellPosition{contractID: m.ID, question: m.Question, answerID: answerID}
+
+	if answerID == "" {
+		if m.Probability == nil {
+			return pos, fmt.Errorf("market %s has no probability; pass answerId for multiple choice markets", m.ID)
+		}
+		pos.prob = *m.Probability
+	} else {
+		found := false
+		for i := range m.Answers {
+			if m.Answers[i].ID == answerID && m.Answers[i].CurrentProb() != nil {
+				pos.prob = *m.Answers[i].CurrentProb()
+				found = true
+			}
+		}
+		if !found {
+			return pos, fmt.Errorf("answer %s not found in market %s", answerID, m.ID)
+		}
+	}
+	var err error
+	if pos.pool, pos.approximate, err = positionPool(m, answerID); err != nil {
+		return pos, err
+	}
+
+	var metric *client.ContractMetric
+	for i := range metrics {
+		id := ""
+		if metrics[i].AnswerID != nil {
+			id = *metrics[i].AnswerID
+		}
+		if id == answerID {
+			metric = &metrics[i]
+		}
+	}
+	if metric == nil {
+		return pos, fmt.Errorf("no position found")
+	}
+
+	pos.outcome = outcome
+	if pos.outcome == "" {
+		pos.outcome = outcomeYes
+		if metric.TotalShares[outcomeNo] > metric.TotalShares[outcomeYes] {
+			pos.outcome = outcomeNo
+		}
+	}
+	pos.shares = metric.TotalShares[pos.outcome]
+	if pos.shares < minSellShares {
+		return pos, fmt.Errorf("no %s shares held", pos.outcome)
+	}
+	return pos, nil
+}
+
+func (s *Server) handleReducePosition(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	marketID, ok := args["marketId"].(string)
+	if !ok || marketID == "" {
+		return mcp.NewToolResultError("marketId is required"), nil
+	}
+	answerID, _ := args["answerId"].(string)
+	outcome, _ := args["outcome"].(string)
+	if outcome != "" && outcome != outcomeYes && outcome != outcomeNo {
+		return mcp.NewToolResultError("outcome must be YES or NO"), nil
+	}
+	dryRun := true
+	if v, ok := args["dryRun"].(bool); ok {
+		dryRun = v
+	}
+
+	target, err := parseSellTarget(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if _, _, _, err := parseJournalMetadata(args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	me, err := s.client.GetMe(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get authenticated user: %v", err)), nil
+	}
+	market, err := s.client.GetMarket(ctx, marketID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get market: %v", err)), nil
+	}
+	params := url.Values{}
+	params.Set("userId", me.ID)
+	metrics, err := s.client.GetPositions(ctx, market.ID, params)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get positions: %v", err)), nil
+	}
+
+	pos, err := findSellPosition(market, metrics, answerID, outcome)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	res := s.reducePosition(ctx, pos, target, dryRun, args)
+	if res.Error != "" {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to reduce position: %s", res.Error)), nil
+	}
+
+	return newStructuredResult(fmt.Sprintf("Sell %.2f of %.2f %s shares for %.2f mana, probability %.1f%% -> %.1f%%.",
+		res.SharesToSell, res.SharesHeld, res.Outcome, res.Proceeds, res.ProbBefore*100, res.ProbAfter*100), res)
+}
+
+// rebalanceResponse is the JSON output for rebalance_portfolio.
+type rebalanceResponse struct {
+	Balance          float64        `json:"balance"`
+	PositionValue    float64        `json:"positionValue"`
+	NetWorth         float64        `json:"netWorth"`
+	MaxPositionValue float64        `json:"maxPositionValue"`
+	DryRun           bool           `json:"dryRun"`
+	Trims            []reduceResult `json:"trims"`
+}
+
+// overweightPositions returns the open positions worth more than maxFraction
+// of net worth (cash balance plus open position value), largest first.
+func overweightPositions(
+	positions []portfolioPosition, balance, maxFraction float64,
+) (rebalanceResponse, []portfolioPosition) {
+	resp := rebalanceResponse{Balance: balance}
+	for _, p := range positions {
+		resp.PositionValue += p.CurrentValue
+	}
+	resp.NetWorth = balance + resp.PositionValue
+	resp.MaxPositionValue = resp.NetWorth * maxFraction
+
+	var over []portfolioPosition
+	for _, p := range positions {
+		if p.CurrentValue > resp.MaxPositionValue {
+			over = append(over, p)
+		}
+	}
+	sort.Slice(over, func(i, j int) bool {
+		return over[i].CurrentValue > over[j].CurrentValue
+	})
+	return resp, over
+}
+
+func (s *Server) handleRebalancePortfolio(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	maxFraction, ok := args["maxFraction"].(float64)
+	if !ok || maxFraction <= 0 || maxFraction > 1 {
+		return mcp.NewToolResultError("maxFraction is required and must be between 0 and 1"), nil
+	}
+	dryRun := true
+	if v, ok := args["dryRun"].(bool); ok {
+		dryRun = v
+	}
+	if _, _, _, err := parseJournalMetadata(args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	me, err := s.client.GetMe(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get authenticated user: %v", err)), nil
+	}
+	enriched, err := s.fetchAllUserPositions(ctx, me.ID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch portfolio: %v", err)), nil
+	}
+	portfolio := buildPortfolioResponse(enriched, 0)
+
+	resp, over := overweightPositions(portfolio.Positions, me.Balance, maxFraction)
+	resp.DryRun = dryRun
+	resp.Trims = []reduceResult{}
+	target := sellTarget{kind: sellTargetValue, amount: resp.MaxPositionValue}
+
+	// Trims run one at a time: positions can share a market, and each sale
+	// moves the price the next one is simulated against.
+	for _, p := range over {
+		pos := sellPosition{
+			contractID: p.ContractID,
+			question:   p.Question,
+			answerID:   p.AnswerID,
+			outcome:    p.Outcome,
+			shares:     p.Shares,
+			prob:       p.CurrentProb,
+		}
+		resp.Trims = append(resp.Trims, s.trimPosition(ctx, pos, target, dryRun, args))
+	}
+
+	return newStructuredResult(
+		fmt.Sprintf("%d position(s) above %.0f mana.", len(resp.Trims), resp.MaxPositionValue), resp)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func TestCPMMPoolSell(t *testing.T) {
+	pool := cpmmPool{yes: 100, no: 100, p: 0.5}
+	sale := pool.sell(50, outcomeYes)
+	// (150-m)(100-m) = 100*100 gives m = 125 - sqrt(10625).
+	raw := 125 - math.Sqrt(10625)
+	wantProb := (100 - raw) / (250 - 2*raw)
+	if math.Abs(sale.probAfter-wantProb) > 1e-6 {
+		t.Errorf("expected probAfter %f, got %f", wantProb, sale.probAfter)
+	}
+	avg := (0.5 + wantProb) / 2
+	wantProceeds := raw - takerFeeConstant*avg*(1-avg)*50
+	if math.Abs(sale.proceeds-wantProceeds) > 1e-6 {
+		t.Errorf("expected proceeds %f, got %f", wantProceeds, sale.proceeds)
+	}
+
+	no := pool.sell(50, outcomeNo)
+	if math.Abs(no.probAfter-(1-wantProb)) > 1e-6 {
+		t.Errorf("expected selling NO to mirror selling YES, got %f", no.probAfter)
+	}
+}
+
+func TestSolveSellShares_Probability(t *testing.T) {
+	pool := cpmmPool{yes: 100, no: 100, p: 0.5}
+	target := sellTarget{kind: sellTargetProb, amount: 0.4}
+	shares, sale, reached := solveSellShares(100, outcomeYes, target, pool)
+	if !reached || sale.probAfter > 0.4 {
+		t.Fatalf("expected to reach 0.4, got %f (reached=%v)", sale.probAfter, reached)
+	}
+	if fewer := pool.sell(shares-100.0/4096, outcomeYes); fewer.probAfter <= 0.4 {
+		t.Errorf("expected %f shares to be about the fewest that reach 0.4", shares)
+	}
+}
+
+func TestSolveSellShares_Value(t *testing.T) {
+	pool := cpmmPool{yes: 200, no: 300, p: 0.4}
+	target := sellTarget{kind: sellTargetValue, amount: 20}
+	shares, sale, reached := solveSellShares(100, outcomeYes, target, pool)
+	if !reached {
+		t.Fatal("expected target to be reachable")
+	}
+	remaining := positionValue(100-shares, sale.probAfter, outcomeYes)
+	if remaining > 20 || remaining < 19.8 {
+		t.Errorf("expected remaining value just under 20, got %f", remaining)
+	}
+}
+
+func TestSolveSellShares_Unreachable(t *testing.T) {
+	pool := cpmmPool{yes: 1000, no: 1000, p: 0.5}
+	target := sellTarget{kind: sellTargetProb, amount: 0.2}
+	shares, _, reached := solveSellShares(10, outcomeYes, target, pool)
+	if reached || shares != 10 {
+		t.Errorf("expected to sell everything, got %f shares, reached=%v", shares, reached)
+	}
+}
+
+func TestSolveSellShares_Exposure(t *testing.T) {
+	pool := cpmmPool{yes: 100, no: 100, p: 0.5}
+	target := sellTarget{kind: sellTargetExposure, amount: 30}
+	shares, _, reached := solveSellShares(100, outcomeYes, target, pool)
+	if !reached || shares != 70 {
+		t.Errorf("expected 70 shares, got %f", shares)
+	}
+}
+
+func TestMarketPool(t *testing.T) {
+	m := &client.FullMarket{
+		LiteMarket: client.LiteMarket{ID: "m", P: floatPtr(0.3), Pool: map[string]any{"YES": 10.0, "NO": 20.0}},
+		Answers:    []client.Answer{{ID: "a", Pool: map[string]any{"YES": 5.0, "NO": 15.0}}, {ID: "b"}},
+	}
+	if pool, err := marketPool(m, ""); err != nil || pool != (cpmmPool{yes: 10, no: 20, p: 0.3}) {
+		t.Errorf("unexpected market pool %+v, err %v", pool, err)
+	}
+	if pool, err := marketPool(m, "a"); err != nil || pool != (cpmmPool{yes: 5, no: 15, p: 0.5}) {
+		t.Errorf("unexpected answer pool %+v, err %v", pool, err)
+	}
+	if _, err := marketPool(m, "b"); err == nil {
+		t.Error("expected error for an answer without a pool")
+	}
+}
+
+func TestSellTarget_ReachedNo(t *testing.T) {
+	target := sellTarget{kind: sellTargetProb, amount: 0.4}
+	if target.reached(10, 0, outcomeNo, 0.3) {
+		t.Error("selling NO raises the probability; 0.3 has not reached 0.4")
+	}
+	if !target.reached(10, 5, outcomeNo, 0.45) {
+		t.Error("expected 0.45 to have reached 0.4 when selling NO")
+	}
+}
+
+func TestParseSellTarget(t *testing.T) {
+	target, err := parseSellTarget(map[string]any{"targetValue": 50.0})
+	if err != nil || target.kind != sellTargetValue || target.amount != 50 {
+		t.Errorf("unexpected target %+v, err %v", target, err)
+	}
+	if _, err := parseSellTarget(map[string]any{}); err == nil {
+		t.Error("expected error without a target")
+	}
+	if _, err := parseSellTarget(map[string]any{"targetValue": 5.0, "targetProb": 0.5}); err == nil {
+		t.Error("expected error with two targets")
+	}
+	if _, err := parseSellTarget(map[string]any{"targetProb": 1.5}); err == nil {
+		t.Error("expected error for probability above 1")
+	}
+}
+
+func TestFindSellPosition(t *testing.T) {
+	m := &client.FullMarket{
+		LiteMarket: client.LiteMarket{ID: "m", Question: "Q?"},
+		Answers: []client.Answer{
+			{ID: "a", Probability: floatPtr(0.25), Pool: map[string]any{"YES": 30.0, "NO": 10.0}},
+			{ID: "b", Probability: floatPtr(0.75), Pool: map[string]any{"YES": 10.0, "NO": 30.0}},
+		},
+	}
+	metrics := []client.ContractMetric{
+		{TotalShares: map[string]float64{outcomeYes: 500}},
+		{AnswerID: strPtr("a"), TotalShares: map[string]float64{outcomeYes: 10, outcomeNo: 40}},
+		{AnswerID: strPtr("b"), TotalShares: map[string]float64{outcomeYes: 30}},
+	}
+
+	pos, err := findSellPosition(m, metrics, "a", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pos.outcome != outcomeNo || pos.shares != 40 || pos.prob != 0.25 {
+		t.Errorf("unexpected position %+v", pos)
+	}
+
+	if _, err := findSellPosition(m, metrics, "b", outcomeNo); err == nil {
+		t.Error("expected error with no NO shares held")
+	}
+	if _, err := findSellPosition(m, metrics, "", ""); err == nil {
+		t.Error("expected error without answerId on a multiple choice market")
+	}
+}
+
+func TestOverweightPositions(t *testing.T) {
+	positions := []portfolioPosition{
+		{ContractID: "a", CurrentValue: 100},
+		{ContractID: "b", CurrentValue: 300},
+		{ContractID: "c", CurrentValue: 50},
+		{ContractID: "d", CurrentValue: 250},
+	}
+	resp, over := overweightPositions(positions, 300, 0.2)
+	if resp.NetWorth != 1000 || resp.PositionValue != 700 || resp.MaxPositionValue != 200 {
+		t.Errorf("unexpected totals: %+v", resp)
+	}
+	if len(over) != 2 || over[0].ContractID != "b" || over[1].ContractID != "d" {
+		t.Errorf("expected b then d to be trimmed, got %+v", over)
+	}
+}
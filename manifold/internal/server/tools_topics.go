@@ -0,0 +1,355 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	topicURLPrefix          = "https://manifold.markets/topic/"
+	defaultTopicMarketLimit = 100
+	defaultNewWithinDays    = 7
+	defaultTopicMovers      = 10
+	topicTopVolume          = 5
+	// topicMoverCandidates bounds how many markets are checked for 24h
+	// movement; markets are checked in order of 24h volume, since a market
+	// without trading cannot have moved.
+	topicMoverCandidates = 30
+)
+
+// topicView is a topic with its rich-text description rendered to markdown.
+type topicView struct {
+	ID            string `json:"id"`
+	Slug          string `json:"slug"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	TotalMembers  int    `json:"totalMembers"`
+	PrivacyStatus string `json:"privacyStatus,omitempty"`
+	About         string `json:"about,omitempty"`
+}
+
+func newTopicView(g *client.Group) topicView {
+	return topicView{
+		ID:            g.ID,
+		Slug:          g.Slug,
+		Name:          g.Name,
+		URL:           topicURLPrefix + g.Slug,
+		TotalMembers:  g.TotalMembers,
+		PrivacyStatus: g.PrivacyStatus,
+		About:         tiptapToMarkdown(g.About),
+	}
+}
+
+// topicList is the structured output for list_topics.
+type topicList struct {
+	Topics []topicView `json:"topics"`
+}
+
+func formatTopics(groups []client.Group) (*mcp.CallToolResult, error) {
+	views := make([]topicView, len(groups))
+	for i := range groups {
+		views[i] = newTopicView(&groups[i])
+	}
+	return newStructuredResult(fmt.Sprintf("Found %d topic(s).", len(groups)), topicList{Topics: views})
+}
+
+func (s *Server) handleListTopics(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	params := url.Values{}
+	setOptionalLimit(params, args)
+
+	var (
+		groups []client.Group
+		err    error
+	)
+	if term, ok := args["term"].(string); ok && term != "" {
+		params.Set("term", term)
+		groups, err = s.client.SearchGroups(ctx, params)
+	} else {
+		setOptionalNumber(params, args, "beforeTime")
+		groups, err = s.client.ListGroups(ctx, params)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list topics: %v", err)), nil
+	}
+
+	return formatTopics(groups)
+}
+
+// topicMarketsResponse is the JSON output for get_topic_markets.
+type topicMarketsResponse struct {
+	TopicSlug  string              `json:"topicSlug"`
+	Offset     int                 `json:"offset"`
+	Count      int                 `json:"count"`
+	NextOffset *int                `json:"nextOffset,omitempty"`
+	Markets    []client.LiteMarket `json:"markets"`
+}
+
+func (s *Server) handleGetTopicMarkets(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	slug, ok := args["topicSlug"].(string)
+	if !ok || slug == "" {
+		return mcp.NewToolResultError("topicSlug is required"), nil
+	}
+	limit := defaultTopicMarketLimit
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		limit = min(int(v), searchPageLimit)
+	}
+	offset := 0
+	if v, ok := args["offset"].(float64); ok && v > 0 {
+		offset = int(v)
+	}
+
+	params := url.Values{}
+	params.Set("topicSlug", slug)
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("offset", strconv.Itoa(offset))
+	setOptionalString(params, args, "sort")
+	setOptionalString(params, args, "filter")
+	setOptionalString(params, args, "contractType")
+
+	markets, err := s.client.SearchMarkets(ctx, params)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search markets: %v", err)), nil
+	}
+
+	resp := topicMarketsResponse{
+		TopicSlug: slug,
+		Offset:    offset,
+		Count:     len(markets),
+		Markets:   markets,
+	}
+	if len(markets) == limit {
+		next := offset + limit
+		resp.NextOffset = &next
+	}
+
+	return newStructuredResult(
+		fmt.Sprintf("%d market(s) in topic %s from offset %d.", resp.Count, resp.TopicSlug, resp.Offset), resp)
+}
+
+// topicMarket is a market listed in a topic summary.
+type topicMarket struct {
+	ID            string   `json:"id"`
+	Question      string   `json:"question"`
+	URL           string   `json:"url"`
+	OutcomeType   string   `json:"outcomeType"`
+	CreatedTime   int64    `json:"createdTime"`
+	Probability   *float64 `json:"probability,omitempty"`
+	Volume        float64  `json:"volume"`
+	Volume24Hours float64  `json:"volume24Hours"`
+}
+
+func newTopicMarket(m *client.LiteMarket) topicMarket {
+	return topicMarket{
+		ID:            m.ID,
+		Question:      m.Question,
+		URL:           m.URL,
+		OutcomeType:   m.OutcomeType,
+		CreatedTime:   m.CreatedTime,
+		Probability:   m.Probability,
+		Volume:        m.Volume,
+		Volume24Hours: m.Volume24Hours,
+	}
+}
+
+// topicMover is a market whose probability moved in the last 24 hours.
+type topicMover struct {
+	ID           string  `json:"id"`
+	Question     string  `json:"question"`
+	URL          string  `json:"url"`
+	CurrentProb  float64 `json:"currentProb"`
+	BaselineProb float64 `json:"baselineProb"`
+	ChangePp     float64 `json:"changePp"`
+	Warning      string  `json:"warning,omitempty"`
+}
+
+// topicSummaryResponse is the JSON output for topic_summary.
+type topicSummaryResponse struct {
+	Topic         topicView     `json:"topic"`
+	MarketCount   int           `json:"marketCount"`
+	OpenCount     int           `json:"openCount"`
+	ResolvedCount int           `json:"resolvedCount"`
+	TotalVolume   float64       `json:"totalVolume"`
+	Volume24Hours float64       `json:"volume24Hours"`
+	TopByVolume   []topicMarket `json:"topBy24hVolume"`
+	Movers        []topicMover  `json:"movers"`
+	NewMarkets    []topicMarket `json:"newMarkets"`
+	// Truncated is set when only the newest maxSearchResults markets were
+	// fetched, so the totals don't cover older ones.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// summarizeTopicMarkets computes the volume totals, busiest markets, and
+// markets created since newSince. Movers are filled in separately.
+func summarizeTopicMarkets(markets []client.LiteMarket, newSince time.Time) topicSummaryResponse {
+	resp := topicSummaryResponse{
+		MarketCount: len(markets),
+		TopByVolume: []topicMarket{},
+		Movers:      []topicMover{},
+		NewMarkets:  []topicMarket{},
+	}
+	for i := range markets {
+		m := &markets[i]
+		if m.IsResolved {
+			resp.ResolvedCount++
+		} else {
+			resp.OpenCount++
+		}
+		resp.TotalVolume += m.Volume
+		resp.Volume24Hours += m.Volume24Hours
+		if m.CreatedTime >= newSince.UnixMilli() {
+			resp.NewMarkets = append(resp.NewMarkets, newTopicMarket(m))
+		}
+	}
+	sort.Slice(resp.NewMarkets, func(i, j int) bool {
+		return resp.NewMarkets[i].CreatedTime > resp.NewMarkets[j].CreatedTime
+	})
+
+	for _, m := range byVolume24h(markets) {
+		if len(resp.TopByVolume) == topicTopVolume || m.Volume24Hours == 0 {
+			break
+		}
+		resp.TopByVolume = append(resp.TopByVolume, newTopicMarket(m))
+	}
+	return resp
+}
+
+// byVolume24h returns pointers to the markets sorted by 24h volume, descending.
+func byVolume24h(markets []client.LiteMarket) []*client.LiteMarket {
+	sorted := make([]*client.LiteMarket, len(markets))
+	for i := range markets {
+		sorted[i] = &markets[i]
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Volume24Hours > sorted[j].Volume24Hours
+	})
+	return sorted
+}
+
+// moverCandidates selects the open single-probability markets traded in the
+// last 24 hours, busiest first.
+func moverCandidates(markets []client.LiteMarket) []*client.LiteMarket {
+	var out []*client.LiteMarket
+	for _, m := range byVolume24h(markets) {
+		if len(out) == topicMoverCandidates || m.Volume24Hours == 0 {
+			break
+		}
+		if !m.IsResolved && m.Probability != nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// findTopicMovers computes each candidate's 24h change from its recent bets,
+// as get_baseline does, returning those past the mover threshold sorted by
+// size of move.
+func (s *Server) findTopicMovers(ctx context.Context, candidates []*client.LiteMarket) []topicMover {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, maxConcurrency)
+		movers = []topicMover{}
+	)
+	for _, m := range candidates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			params := url.Values{}
+			params.Set("contractId", m.ID)
+			params.Set("limit", strconv.Itoa(baselineBetLimit))
+			bets, err := s.client.ListBets(ctx, params)
+			if err != nil {
+				return
+			}
+			result := computeBaseline(bets, *m.Probability, 24*time.Hour)
+			changePp := (*m.Probability - result.Baseline) * 100
+			if math.Abs(changePp) < moverThresholdPp {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			movers = append(movers, topicMover{
+				ID:           m.ID,
+				Question:     m.Question,
+				URL:          m.URL,
+				CurrentProb:  *m.Probability,
+				BaselineProb: result.Baseline,
+				ChangePp:     changePp,
+				Warning:      result.Warning,
+			})
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(movers, func(i, j int) bool {
+		return math.Abs(movers[i].ChangePp) > math.Abs(movers[j].ChangePp)
+	})
+	return movers
+}
+
+func (s *Server) handleTopicSummary(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	slug, ok := args["topicSlug"].(string)
+	if !ok || slug == "" {
+		return mcp.NewToolResultError("topicSlug is required"), nil
+	}
+	newWithin := time.Duration(defaultNewWithinDays) * 24 * time.Hour
+	if v, ok := args["newWithinDays"].(float64); ok && v > 0 {
+		newWithin = time.Duration(v * float64(24*time.Hour))
+	}
+	moverLimit := defaultTopicMovers
+	if v, ok := args["moverLimit"].(float64); ok && v > 0 {
+		moverLimit = int(v)
+	}
+
+	group, err := s.client.GetGroup(ctx, slug)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get topic: %v", err)), nil
+	}
+	params := url.Values{}
+	params.Set("topicSlug", slug)
+	params.Set("sort", "newest")
+	markets, truncated, err := s.searchAllMarkets(ctx, params)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search markets: %v", err)), nil
+	}
+
+	resp := summarizeTopicMarkets(markets, time.Now().Add(-newWithin))
+	resp.Topic = newTopicView(group)
+	resp.Truncated = truncated
+	movers := s.findTopicMovers(ctx, moverCandidates(markets))
+	resp.Movers = movers[:min(moverLimit, len(movers))]
+
+	text := fmt.Sprintf("Topic %s: %d market(s) (%d open), %.0f mana 24h volume.",
+		resp.Topic.Name, resp.MarketCount, resp.OpenCount, resp.Volume24Hours)
+	if truncated {
+		text += fmt.Sprintf(" Only the newest %d markets were counted.", maxSearchResults)
+	}
+	return newStructuredResult(text, resp)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+)
+
+func TestSummarizeTopicMarkets(t *testing.T) {
+	now := time.UnixMilli(10_000_000_000)
+	markets := []client.LiteMarket{
+		{ID: "old", CreatedTime: now.Add(-30 * 24 * time.Hour).UnixMilli(), Volume: 500, Volume24Hours: 40},
+		{ID: "new", CreatedTime: now.Add(-time.Hour).UnixMilli(), Volume: 20, Volume24Hours: 20},
+		{ID: "newer", CreatedTime: now.Add(-time.Minute).UnixMilli(), Volume: 5},
+		{ID: "done", IsResolved: true, Volume: 1000, Volume24Hours: 0},
+	}
+
+	resp := summarizeTopicMarkets(markets, now.Add(-7*24*time.Hour))
+	if resp.MarketCount != 4 || resp.OpenCount != 3 || resp.ResolvedCount != 1 {
+		t.Errorf("unexpected counts: %+v", resp)
+	}
+	if resp.TotalVolume != 1525 || resp.Volume24Hours != 60 {
+		t.Errorf("unexpected volume %f / %f", resp.TotalVolume, resp.Volume24Hours)
+	}
+	if len(resp.NewMarkets) != 2 || resp.NewMarkets[0].ID != "newer" || resp.NewMarkets[1].ID != "new" {
+		t.Errorf("expected new markets newest first, got %+v", resp.NewMarkets)
+	}
+	if len(resp.TopByVolume) != 2 || resp.TopByVolume[0].ID != "old" {
+		t.Errorf("expected only traded markets by 24h volume, got %+v", resp.TopByVolume)
+	}
+}
+
+func TestMoverCandidates(t *testing.T) {
+	markets := []client.LiteMarket{
+		{ID: "quiet", Probability: floatPtr(0.5)},
+		{ID: "busy", Probability: floatPtr(0.5), Volume24Hours: 100},
+		{ID: "mc", Volume24Hours: 200},
+		{ID: "resolved", IsResolved: true, Probability: floatPtr(1), Volume24Hours: 300},
+		{ID: "some", Probability: floatPtr(0.2), Volume24Hours: 10},
+	}
+	got := moverCandidates(markets)
+	if len(got) != 2 || got[0].ID != "busy" || got[1].ID != "some" {
+		t.Errorf("unexpected candidates %v", got)
+	}
+}
+
+func TestFindTopicMovers(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour).UnixMilli()
+	dayAgo := time.Now().Add(-48 * time.Hour).UnixMilli()
+	bets := map[string][]client.Bet{
+		"up": {
+			{CreatedTime: hourAgo, ProbBefore: 0.4, ProbAfter: 0.6},
+			{CreatedTime: dayAgo, ProbBefore: 0.3, ProbAfter: 0.4},
+		},
+		"flat": {
+			{CreatedTime: hourAgo, ProbBefore: 0.5, ProbAfter: 0.51},
+			{CreatedTime: dayAgo, ProbBefore: 0.4, ProbAfter: 0.5},
+		},
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(bets[r.URL.Query().Get("contractId")])
+	}))
+	defer api.Close()
+
+	s := &Server{client: client.NewClient(api.URL, "key")}
+	candidates := []*client.LiteMarket{
+		{ID: "up", Probability: floatPtr(0.6)},
+		{ID: "flat", Probability: floatPtr(0.51)},
+	}
+	movers := s.findTopicMovers(context.Background(), candidates)
+	if len(movers) != 1 || movers[0].ID != "up" {
+		t.Fatalf("expected only the market that moved, got %+v", movers)
+	}
+	if movers[0].BaselineProb != 0.4 || movers[0].ChangePp < 19.99 || movers[0].ChangePp > 20.01 {
+		t.Errorf("unexpected move %+v", movers[0])
+	}
+}
+
+func TestNewTopicView(t *testing.T) {
+	g := &client.Group{
+		ID:   "g",
+		Slug: "ai",
+		Name: "AI",
+		About: map[string]any{"type": "doc", "content": []any{
+			map[string]any{"type": "paragraph", "content": []any{
+				map[string]any{"type": "text", "text": "Artificial intelligence"},
+			}},
+		}},
+	}
+	v := newTopicView(g)
+	if v.URL != "https://manifold.markets/topic/ai" || v.About != "Artificial intelligence" {
+		t.Errorf("unexpected view %+v", v)
+	}
+}
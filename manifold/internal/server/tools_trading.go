@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/jbeshir/mcp-servers/manifold/internal/client"
+	"github.com/jbeshir/mcp-servers/manifold/internal/journal"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -41,13 +42,21 @@ func (s *Server) handlePlaceBet(
 	if dryRun, ok := args["dryRun"].(bool); ok {
 		req.DryRun = &dryRun
 	}
+	if _, _, _, err := parseJournalMetadata(args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	bet, err := s.client.PlaceBet(ctx, req)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to place bet: %v", err)), nil
 	}
 
-	return formatBet(bet)
+	var warning string
+	if req.DryRun == nil || !*req.DryRun {
+		warning = s.recordTrade(journal.ActionBuy, bet, args)
+	}
+	result, err := formatBet(bet)
+	return withWarning(result, warning), err
 }
 
 func (s *Server) handleSellShares(
@@ -71,13 +80,24 @@ func (s *Server) handleSellShares(
 	if answerID, ok := args["answerId"].(string); ok && answerID != "" {
 		req.AnswerID = answerID
 	}
+	if _, _, _, err := parseJournalMetadata(args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	bet, err := s.client.SellShares(ctx, marketID, req)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to sell shares: %v", err)), nil
 	}
 
-	return formatBet(bet)
+	warning := s.recordTrade(journal.ActionSell, bet, args)
+	result, err := formatBet(bet)
+	return withWarning(result, warning), err
+}
+
+// cancelBetResult is the structured output for cancel_bet.
+type cancelBetResult struct {
+	BetID     string `json:"betId"`
+	Cancelled bool   `json:"cancelled"`
 }
 
 func (s *Server) handleCancelBet(
@@ -93,5 +113,5 @@ func (s *Server) handleCancelBet(
 		return mcp.NewToolResultError(fmt.Sprintf("failed to cancel bet: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Cancelled bet %s", betID)), nil
+	return newStructuredResult(fmt.Sprintf("Cancelled bet %s", betID), cancelBetResult{BetID: betID, Cancelled: true})
 }
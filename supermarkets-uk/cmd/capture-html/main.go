@@ -1,15 +1,28 @@
-// Command capture-html fetches rendered HTML from supermarket pages using a
-// headless browser and saves it to disk for selector discovery.
+// Command capture-html fetches supermarket search and category pages and
+// saves them to disk for selector discovery. Pages are fetched the way the
+// store's datasource fetches them: rendered in a headless browser, or with
+// plain HTTP requests for stores served as static HTML or JSON APIs.
+//
+// With -refresh, it replaces the store's search and category fixtures in
+// testdata, parsing the old and new pages with the store's parser and
+// printing a diff, so selector drift shows up as missing products or
+// fields. It exits non-zero if the new pages parse noticeably worse.
 //
 // Usage:
 //
 //	go run ./cmd/capture-html -store asda -query "milk"
 //	go run ./cmd/capture-html -store waitrose -query "bread"
+//	go run ./cmd/capture-html -store iceland -query "milk"
+//	go run ./cmd/capture-html -store lidl -query "milk"
 //	go run ./cmd/capture-html -store asda -url "https://www.asda.com/groceries/product/some-id" -wait "h1"
+//	go run ./cmd/capture-html -store tesco -refresh
+//	go run ./cmd/capture-html -store all -refresh
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -18,11 +31,21 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/auth"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/aldi"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/asda"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/coop"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/iceland"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/lidl"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/tesco"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/waitrose"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/healthcheck"
 )
 
 type storeConfig struct {
@@ -30,16 +53,31 @@ type storeConfig struct {
 	categoryURL     string
 	searchWaitSel   string // CSS selector to wait for on search pages
 	categoryWaitSel string // CSS selector to wait for on category pages
+	// viaHTTP fetches pages with plain GET requests, adding header, rather
+	// than rendering them in the browser.
+	viaHTTP bool
+	header  http.Header
+	// searchRequest, if set, builds the search request instead, for stores
+	// that search through an API while rendering other pages.
+	searchRequest func(ctx context.Context, query string) (*http.Request, error)
+	// searchExt and categoryExt are the fixture file extensions, ".html"
+	// unless set. JSON pages are requested with a JSON Accept header.
+	searchExt   string
+	categoryExt string
+	// parseSearch and parseCategories parse the captured pages for -refresh
+	// diffs.
+	parseSearch     func(io.Reader) ([]datasource.Product, error)
+	parseCategories func(io.Reader) ([]datasource.Category, error)
 }
 
 var stores = map[string]storeConfig{
 	"asda": {
-		searchURL: func(query string) string {
-			return "https://www.asda.com/groceries/search/" + url.PathEscape(query)
-		},
+		searchRequest:   asda.NewSearchRequest,
+		searchExt:       ".json",
 		categoryURL:     "https://www.asda.com/groceries",
-		searchWaitSel:   `a[href^="/groceries/product/"]`,
 		categoryWaitSel: `a[href^="/groceries/fruit"]`,
+		parseSearch:     asda.ParseSearchResults,
+		parseCategories: asda.ParseCategories,
 	},
 	"tesco": {
 		searchURL: func(query string) string {
@@ -48,6 +86,8 @@ var stores = map[string]storeConfig{
 		categoryURL:     "https://www.tesco.com/groceries/en-GB/search?query=a",
 		searchWaitSel:   `li[data-testid]`,
 		categoryWaitSel: `li[data-testid]`,
+		parseSearch:     tesco.ParseSearchResults,
+		parseCategories: tesco.ParseCategories,
 	},
 	"waitrose": {
 		searchURL: func(query string) string {
@@ -56,92 +96,359 @@ var stores = map[string]storeConfig{
 		categoryURL:     "https://www.waitrose.com/ecom/shop/browse",
 		searchWaitSel:   `article[data-testid="product-pod"]`,
 		categoryWaitSel: `a[href*="/ecom/shop/browse/groceries/"]`,
+		parseSearch:     waitrose.ParseSearchResults,
+		parseCategories: waitrose.ParseCategories,
 	},
+	"iceland": {
+		searchURL: func(query string) string {
+			return "https://www.iceland.co.uk/search?q=" + url.QueryEscape(query)
+		},
+		categoryURL:     "https://www.iceland.co.uk/",
+		viaHTTP:         true,
+		parseSearch:     iceland.ParseSearchResults,
+		parseCategories: iceland.ParseCategories,
+	},
+	"coop": {
+		searchURL: func(query string) string {
+			return "https://shop.coop.co.uk/search?" + url.Values{"term": {query}}.Encode()
+		},
+		categoryURL:     "https://shop.coop.co.uk/categories",
+		viaHTTP:         true,
+		parseSearch:     coop.ParseSearchResults,
+		parseCategories: coop.ParseCategories,
+	},
+	"lidl": {
+		searchURL: func(query string) string {
+			return "https://www.lidl.co.uk/q/api/search?" + url.Values{
+				"q":          {query},
+				"locale":     {"en_GB"},
+				"assortment": {"GB"},
+				"version":    {"2.1.0"},
+				"fetchsize":  {"30"},
+			}.Encode()
+		},
+		categoryURL:     "https://www.lidl.co.uk/",
+		viaHTTP:         true,
+		searchExt:       ".json",
+		parseSearch:     lidl.ParseSearchResults,
+		parseCategories: lidl.ParseCategories,
+	},
+	"aldi": {
+		searchURL: func(query string) string {
+			return "https://api.aldi.co.uk/v3/product-search?" + url.Values{
+				"currency":    {"GBP"},
+				"serviceType": {"walk-in"},
+				"q":           {query},
+				"limit":       {"30"},
+				"offset":      {"0"},
+				"sort":        {"relevance"},
+			}.Encode()
+		},
+		categoryURL:     "https://api.aldi.co.uk/v2/product-category-tree?serviceType=walk-in",
+		viaHTTP:         true,
+		header:          http.Header{"Origin": {"https://www.aldi.co.uk"}},
+		searchExt:       ".json",
+		categoryExt:     ".json",
+		parseSearch:     aldi.ParseSearchResults,
+		parseCategories: aldi.ParseCategories,
+	},
+}
+
+// fixtureName returns the testdata file name for a store's page, such as
+// "lidl_search.json".
+func fixtureName(storeName, page, ext string) string {
+	if ext == "" {
+		ext = ".html"
+	}
+	return storeName + "_" + page + ext
 }
 
+// httpClient fetches pages for stores captured with plain HTTP requests.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
 func main() {
-	storeName := flag.String("store", "", "supermarket to capture (asda, tesco, waitrose)")
+	storeName := flag.String("store", "", "supermarket to capture ("+storeList()+"), or all with -refresh")
 	query := flag.String("query", "milk", "search query")
 	rawURL := flag.String("url", "", "fetch a specific URL instead of search/category pages")
 	wait := flag.String("wait", "", "CSS selector to wait for before capturing (for -url mode)")
 	outDir := flag.String("out", "", "output directory (default: internal/datasource/<store>/testdata)")
+	refresh := flag.Bool("refresh", false, "replace the testdata fixtures and diff old vs new parse results")
 	flag.Parse()
 
-	if *storeName == "" {
-		log.Fatal("-store is required (asda, tesco, or waitrose)")
+	names := storesToCapture(*storeName, *refresh)
+	if *outDir != "" && len(names) > 1 {
+		log.Fatal("-out cannot be used with -store all")
 	}
-	cfg, ok := stores[*storeName]
-	if !ok {
-		log.Fatalf("unknown store: %s", *storeName)
+	opts := options{query: *query, rawURL: *rawURL, wait: *wait, outDir: *outDir, refresh: *refresh}
+
+	browser := scraper.NewBrowser(scraper.BrowserConfig{})
+	ok := true
+	for _, name := range names {
+		ok = capture(browser, name, opts) && ok
 	}
+	browser.Close()
 
-	if *outDir == "" {
-		*outDir = filepath.Join("internal", "datasource", *storeName, "testdata")
+	if !ok {
+		os.Exit(1)
 	}
-	if err := os.MkdirAll(*outDir, 0o750); err != nil {
-		log.Fatalf("create output dir: %v", err)
+}
+
+// options holds the command-line options shared by every store captured.
+type options struct {
+	query   string
+	rawURL  string
+	wait    string
+	outDir  string
+	refresh bool
+}
+
+// storeList returns the supported store names, sorted and comma-separated.
+func storeList() string {
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
 	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
 
-	// Load cookies from the cookie store for the given store.
-	var cookies []*http.Cookie
-	cookieDir, err := auth.DefaultCookieDir()
-	if err == nil {
-		store, err := auth.NewCookieStore(cookieDir)
-		if err == nil {
-			cookies, _ = store.Load(datasource.SupermarketID(*storeName))
-			if len(cookies) > 0 {
-				log.Printf("Loaded %d cached cookies for %s", len(cookies), *storeName)
-			}
-		}
+// storesToCapture resolves the -store flag to the stores to capture.
+func storesToCapture(storeName string, refresh bool) []string {
+	switch {
+	case storeName == "":
+		log.Fatalf("-store is required (%s)", storeList())
+	case storeName == "all" && !refresh:
+		log.Fatal("-store all requires -refresh")
+	case storeName == "all":
+		return strings.Split(storeList(), ", ")
+	}
+	if _, ok := stores[storeName]; !ok {
+		log.Fatalf("unknown store: %s", storeName)
 	}
+	return []string{storeName}
+}
 
-	browser := scraper.NewBrowser(scraper.BrowserConfig{})
-	defer browser.Close()
+// capture fetches one store's pages. With -refresh, it returns false if
+// a page could not be fetched or parses worse than the fixture it
+// replaced.
+func capture(browser *scraper.Browser, storeName string, opts options) bool {
+	cfg := stores[storeName]
+	outDir := opts.outDir
+	if outDir == "" {
+		outDir = filepath.Join("internal", "datasource", storeName, "testdata")
+	}
+	if err := os.MkdirAll(outDir, 0o750); err != nil {
+		log.Fatalf("create output dir: %v", err)
+	}
+	cookies := loadCookies(storeName)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	if *rawURL != "" {
-		outFile := filepath.Join(*outDir, "page.html")
-		fetchWithCookies(ctx, browser, *rawURL, outFile, *wait, cookies)
-		return
+	pf := pageFetcher{browser: browser, cfg: cfg, cookies: cookies}
+	if opts.rawURL != "" {
+		outFile := filepath.Join(outDir, "page.html")
+		fetchPage(ctx, pf.page(opts.rawURL, opts.wait, ""), outFile)
+		return true
 	}
 
-	// Fetch search results page.
-	searchFile := filepath.Join(*outDir, *storeName+"_search.html")
-	fetchWithCookies(ctx, browser, cfg.searchURL(*query), searchFile, cfg.searchWaitSel, cookies)
+	search := pf.search(opts.query)
+	categories := pf.page(cfg.categoryURL, cfg.categoryWaitSel, cfg.categoryExt)
+	searchFile := filepath.Join(outDir, fixtureName(storeName, "search", cfg.searchExt))
+	catFile := filepath.Join(outDir, fixtureName(storeName, "categories", cfg.categoryExt))
+	if opts.refresh {
+		ok := refreshFixture(ctx, search, searchFile, diffParsed(cfg.parseSearch, diffProducts))
+		return refreshFixture(ctx, categories, catFile, diffParsed(cfg.parseCategories, diffCategories)) && ok
+	}
 
-	// Fetch category page.
-	catFile := filepath.Join(*outDir, *storeName+"_categories.html")
-	fetchWithCookies(ctx, browser, cfg.categoryURL, catFile, cfg.categoryWaitSel, cookies)
+	fetchPage(ctx, search, searchFile)
+	fetchPage(ctx, categories, catFile)
 
 	fmt.Println("\nDone. Inspect the saved HTML to find CSS selectors for product containers, titles, prices, etc.")
+	return true
 }
 
-func fetchWithCookies(
-	ctx context.Context, browser *scraper.Browser,
-	targetURL, outFile, waitSel string, cookies []*http.Cookie,
-) {
-	fmt.Printf("Fetching %s ...\n", targetURL)
-	if waitSel != "" {
-		fmt.Printf("  waiting for: %s\n", waitSel)
+// loadCookies loads cached session cookies for the store, if any.
+func loadCookies(storeName string) []*http.Cookie {
+	cookieDir, err := auth.DefaultCookieDir()
+	if err != nil {
+		return nil
 	}
-	rc, err := browser.Fetch(ctx, targetURL, cookies, waitSel)
+	store, err := auth.NewCookieStore(cookieDir)
 	if err != nil {
-		log.Printf("ERROR fetching %s: %v", targetURL, err)
-		return
+		return nil
+	}
+	cookies, _ := store.Load(datasource.SupermarketID(storeName))
+	if len(cookies) > 0 {
+		log.Printf("Loaded %d cached cookies for %s", len(cookies), storeName)
+	}
+	return cookies
+}
+
+// page fetches one page's body.
+type page func(ctx context.Context) ([]byte, error)
+
+// pageFetcher builds pages for a store, fetched the way its datasource
+// fetches them.
+type pageFetcher struct {
+	browser *scraper.Browser
+	cfg     storeConfig
+	cookies []*http.Cookie
+}
+
+// page returns the page at targetURL. Browser pages wait for waitSel;
+// plain HTTP pages ask for JSON when ext is ".json".
+func (f pageFetcher) page(targetURL, waitSel, ext string) page {
+	if !f.cfg.viaHTTP {
+		return func(ctx context.Context) ([]byte, error) {
+			fmt.Printf("Fetching %s ...\n", targetURL)
+			if waitSel != "" {
+				fmt.Printf("  waiting for: %s\n", waitSel)
+			}
+			rc, err := f.browser.Fetch(ctx, targetURL, f.cookies, waitSel)
+			return readPage(targetURL, rc, err)
+		}
+	}
+	return func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		scraper.SetBrowserHeaders(req)
+		if ext == ".json" {
+			req.Header.Set("Accept", "application/json")
+		}
+		for name, values := range f.cfg.header {
+			req.Header[name] = values
+		}
+		return doRequest(req, f.cookies)
+	}
+}
+
+// search returns the store's search page for query.
+func (f pageFetcher) search(query string) page {
+	if f.cfg.searchRequest == nil {
+		return f.page(f.cfg.searchURL(query), f.cfg.searchWaitSel, f.cfg.searchExt)
+	}
+	return func(ctx context.Context) ([]byte, error) {
+		req, err := f.cfg.searchRequest(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return doRequest(req, nil)
+	}
+}
+
+// doRequest sends a plain HTTP request for a page.
+func doRequest(req *http.Request, cookies []*http.Cookie) ([]byte, error) {
+	fmt.Printf("Fetching %s %s ...\n", req.Method, req.URL)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return readPage(req.URL.String(), nil, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: HTTP %d", req.URL, resp.StatusCode)
+	}
+	return readPage(req.URL.String(), resp.Body, nil)
+}
+
+// readPage reads and closes a fetched page body.
+func readPage(targetURL string, rc io.ReadCloser, err error) ([]byte, error) {
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", targetURL, err)
 	}
 	defer func() { _ = rc.Close() }()
 
 	data, err := io.ReadAll(rc)
 	if err != nil {
-		log.Printf("ERROR reading response for %s: %v", targetURL, err)
+		return nil, fmt.Errorf("reading response for %s: %w", targetURL, err)
+	}
+	return data, nil
+}
+
+// fetchPage fetches a page and saves it to outFile, logging any error.
+func fetchPage(ctx context.Context, p page, outFile string) {
+	data, err := p(ctx)
+	if err != nil {
+		log.Printf("ERROR %v", err)
 		return
 	}
+	save(outFile, data)
+}
 
+func save(outFile string, data []byte) bool {
 	if err := os.WriteFile(outFile, data, 0o600); err != nil {
 		log.Printf("ERROR writing %s: %v", outFile, err)
-		return
+		return false
 	}
 	fmt.Printf("  -> saved %s (%d bytes)\n", outFile, len(data))
+	return true
+}
+
+// differ prints how the parse results of a refreshed page differ from the
+// fixture it replaces, and reports whether the new page parses no worse.
+type differ func(oldPage, newPage []byte) bool
+
+// refreshFixture fetches a page, diffs it against the existing fixture at
+// outFile if diff is non-nil, and replaces the fixture. A regressed page is
+// still saved so the change can be inspected with git diff.
+func refreshFixture(ctx context.Context, p page, outFile string, diff differ) bool {
+	data, err := p(ctx)
+	if err != nil {
+		log.Printf("ERROR %v", err)
+		return false
+	}
+
+	ok := true
+	if diff != nil {
+		old, err := os.ReadFile(outFile) //nolint:gosec // Fixture path built from the store name.
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("ERROR reading %s: %v", outFile, err)
+		}
+		fmt.Printf("  parse diff against %s:\n", outFile)
+		ok = diff(old, data)
+	}
+	return save(outFile, data) && ok
+}
+
+// diffParsed returns a differ that parses both pages with parse and
+// compares the results with compare. A missing or unparseable old page
+// counts as having no results. It returns nil if parse is nil.
+func diffParsed[T any](parse func(io.Reader) ([]T, error), compare func(oldItems, newItems []T) bool) differ {
+	if parse == nil {
+		return nil
+	}
+	return func(oldPage, newPage []byte) bool {
+		var oldItems []T
+		if len(oldPage) > 0 {
+			oldItems, _ = parse(bytes.NewReader(oldPage))
+		}
+		newItems, err := parse(bytes.NewReader(newPage))
+		if err != nil {
+			fmt.Printf("    REGRESSION: new page does not parse: %v\n", err)
+			return false
+		}
+		return compare(oldItems, newItems)
+	}
+}
+
+func diffProducts(oldProducts, newProducts []datasource.Product) bool {
+	d := healthcheck.Compare(oldProducts, newProducts)
+	for _, line := range strings.Split(strings.TrimSuffix(d.String(), "\n"), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	return len(d.Regressions) == 0
+}
+
+func diffCategories(oldCategories, newCategories []datasource.Category) bool {
+	fmt.Printf("    categories: %d -> %d\n", len(oldCategories), len(newCategories))
+	if len(newCategories)*2 < len(oldCategories) {
+		fmt.Printf("    REGRESSION: categories fell from %d to %d\n", len(oldCategories), len(newCategories))
+		return false
+	}
+	return true
 }
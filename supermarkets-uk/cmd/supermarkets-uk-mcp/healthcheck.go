@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/healthcheck"
+)
+
+// runHealthcheck implements the healthcheck subcommand: it runs each
+// store's canned search without login or caching, prints a line per store,
+// and returns a non-zero exit code if any store is unhealthy.
+func runHealthcheck(args []string) int {
+	fs := flag.NewFlagSet("healthcheck", flag.ExitOnError)
+	supermarkets := fs.String("supermarkets", "", "comma-separated supermarket IDs to check (default all)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "how long to wait for every store to answer")
+	_ = fs.Parse(args)
+
+	c := client.NewClient(client.Config{
+		ChromeExecPath: os.Getenv("CHROME_EXEC_PATH"),
+		ShopifyStores:  loadShopifyStores(),
+	})
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	results := healthcheck.Run(ctx, c, client.ParseSupermarketIDs(*supermarkets))
+	for _, r := range results {
+		fmt.Println(healthcheckLine(r))
+	}
+	if !healthcheck.Healthy(results) {
+		return 1
+	}
+	return 0
+}
+
+func healthcheckLine(r healthcheck.Result) string {
+	status := "ok  "
+	if !r.Healthy {
+		status = "FAIL"
+	}
+	line := fmt.Sprintf("%s %s (%q): %d product(s)", status, r.Supermarket, r.Query, r.Coverage.Products)
+	switch {
+	case r.Error != "":
+		line += ": " + r.Error
+	case len(r.Problems) > 0:
+		line += ": " + strings.Join(r.Problems, "; ")
+	}
+	return line
+}
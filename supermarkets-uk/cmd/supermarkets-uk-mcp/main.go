@@ -5,11 +5,19 @@ import (
 	"os"
 
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/auth"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/cache"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/shopify"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/pricehistory"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/server"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/shopping"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(runHealthcheck(os.Args[2:]))
+	}
+
 	logins := auth.LoadLoginFlags()
 	if len(logins) > 0 {
 		for id := range logins {
@@ -29,17 +37,64 @@ func main() {
 
 	cached := auth.LoadCachedCookies(logins, store)
 
+	historyPath, err := pricehistory.DefaultPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("price history database: %s", historyPath)
+	// History is optional: another running instance may hold the database.
+	history, err := pricehistory.Open(historyPath)
+	if err != nil {
+		log.Printf("warning: price history disabled: %v", err)
+	}
+
+	shopifyStores := loadShopifyStores()
+	ids := client.SupermarketIDs(shopifyStores)
+
+	cacheCfg := cache.LoadConfig(ids)
+	if cacheCfg.Dir != "" {
+		log.Printf("cache directory: %s", cacheCfg.Dir)
+	}
+
 	c := client.NewClient(client.Config{
-		Cookies:        cached,
-		LoginFlags:     logins,
-		Store:          store,
-		ChromeExecPath: os.Getenv("CHROME_EXEC_PATH"),
+		Cookies:          cached,
+		LoginFlags:       logins,
+		Store:            store,
+		ChromeExecPath:   os.Getenv("CHROME_EXEC_PATH"),
+		DeliveryMinimums: shopping.LoadDeliveryMinimums(ids),
+		History:          history,
+		Cache:            cache.New(cacheCfg),
+		ShopifyStores:    shopifyStores,
 	})
 	srv := server.NewServer(c)
 
 	err = srv.Run()
 	c.Close()
+	if history != nil {
+		if closeErr := history.Close(); closeErr != nil {
+			log.Printf("warning: failed to close price history: %v", closeErr)
+		}
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// loadShopifyStores reads user-defined Shopify stores. Invalid entries are
+// logged and skipped rather than stopping the server. The client sends each
+// store a test search in the background and leaves out those that fail it.
+func loadShopifyStores() []shopify.Config {
+	path, err := shopify.DefaultStoresPath()
+	if err != nil {
+		log.Printf("warning: shopify stores disabled: %v", err)
+		return nil
+	}
+	configs, err := shopify.LoadStores(path)
+	if err != nil {
+		log.Printf("warning: %s: %v", path, err)
+	}
+	for _, cfg := range configs {
+		log.Printf("shopify store enabled: %s (%s)", cfg.ID, cfg.BaseURL)
+	}
+	return configs
+}
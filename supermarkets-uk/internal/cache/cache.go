@@ -0,0 +1,322 @@
+// Package cache caches supermarket search results and product details for
+// a short time, so repeated lookups don't re-fetch from every store. Stores
+// rendered through a headless browser are slow, and frequent identical
+// requests risk bot detection.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// DefaultTTL is how long responses are cached unless configured otherwise.
+const DefaultTTL = 15 * time.Minute
+
+// maxEntries bounds the in-memory cache. Beyond it, expired entries are
+// dropped, then those closest to expiry.
+const maxEntries = 2000
+
+// Config configures a Cache.
+type Config struct {
+	// TTL is how long responses are cached for stores without their own
+	// TTL. Zero disables caching for them.
+	TTL time.Duration
+	// StoreTTLs overrides TTL per supermarket. A zero TTL disables caching
+	// for that supermarket.
+	StoreTTLs map[datasource.SupermarketID]time.Duration
+	// Dir, if set, also keeps entries on disk so they survive restarts.
+	Dir string
+}
+
+// LoadConfig reads the cache configuration from the environment:
+// CACHE_TTL and <ID>_CACHE_TTL for each of ids as Go durations (e.g. "30m",
+// or "0" to disable), and CACHE_DIR for the optional on-disk cache.
+func LoadConfig(ids []datasource.SupermarketID) Config {
+	cfg := Config{
+		TTL:       DefaultTTL,
+		StoreTTLs: make(map[datasource.SupermarketID]time.Duration),
+		Dir:       os.Getenv("CACHE_DIR"),
+	}
+	if ttl, ok := envTTL("CACHE_TTL"); ok {
+		cfg.TTL = ttl
+	}
+	for _, id := range ids {
+		if ttl, ok := envTTL(strings.ToUpper(string(id)) + "_CACHE_TTL"); ok {
+			cfg.StoreTTLs[id] = ttl
+		}
+	}
+	return cfg
+}
+
+func envTTL(name string) (time.Duration, bool) {
+	val := strings.TrimSpace(os.Getenv(name))
+	if val == "" {
+		return 0, false
+	}
+	ttl, err := time.ParseDuration(val)
+	if err != nil || ttl < 0 {
+		log.Printf("warning: ignoring invalid %s %q: want a duration like 30m", name, val)
+		return 0, false
+	}
+	return ttl, true
+}
+
+type freshKey struct{}
+
+// WithFresh returns a context whose lookups bypass the cache. Fresh
+// responses still replace what is cached.
+func WithFresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshKey{}, true)
+}
+
+func isFresh(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshKey{}).(bool)
+	return fresh
+}
+
+// entry is a cached response, kept JSON-encoded so each hit returns a copy
+// callers are free to modify.
+type entry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// Cache holds cached responses for every wrapped ProductSource.
+type Cache struct {
+	cfg     Config
+	mu      sync.Mutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+// New creates a cache. If cfg.Dir is set it is created if needed, and
+// entries left there by earlier runs that have since expired are removed;
+// if it cannot be created, the cache is kept in memory only.
+func New(cfg Config) *Cache {
+	return newCache(cfg, time.Now)
+}
+
+func newCache(cfg Config, now func() time.Time) *Cache {
+	if cfg.Dir != "" {
+		if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+			log.Printf("warning: on-disk cache disabled: %v", err)
+			cfg.Dir = ""
+		}
+	}
+	c := &Cache{cfg: cfg, entries: make(map[string]entry), now: now}
+	c.sweep()
+	return c
+}
+
+// TTL returns how long responses from a supermarket are cached.
+func (c *Cache) TTL(id datasource.SupermarketID) time.Duration {
+	if ttl, ok := c.cfg.StoreTTLs[id]; ok {
+		return ttl
+	}
+	return c.cfg.TTL
+}
+
+// Wrap returns ds with its searches and product details cached. It returns
+// ds unchanged if c is nil or the supermarket's TTL is zero.
+func (c *Cache) Wrap(ds datasource.ProductSource) datasource.ProductSource {
+	if c == nil {
+		return ds
+	}
+	ttl := c.TTL(ds.ID())
+	if ttl <= 0 {
+		return ds
+	}
+	return &Source{ProductSource: ds, cache: c, ttl: ttl}
+}
+
+func (c *Cache) get(key string, v any) bool {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		e, ok = c.load(key)
+	}
+	if !ok || !c.now().Before(e.Expires) {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+func (c *Cache) set(key string, ttl time.Duration, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	e := entry{Key: key, Expires: c.now().Add(ttl), Value: data}
+
+	c.remember(e)
+	c.save(e)
+}
+
+func (c *Cache) remember(e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[e.Key] = e
+	if len(c.entries) > maxEntries {
+		c.evict()
+	}
+}
+
+// evict drops expired entries, then the entries closest to expiry, until
+// the cache is back under maxEntries. It must be called with c.mu held.
+func (c *Cache) evict() {
+	now := c.now()
+	for key, e := range c.entries {
+		if !now.Before(e.Expires) {
+			delete(c.entries, key)
+		}
+	}
+	for len(c.entries) > maxEntries {
+		var oldest string
+		for key, e := range c.entries {
+			if oldest == "" || e.Expires.Before(c.entries[oldest].Expires) {
+				oldest = key
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// path returns the file an entry is kept in on disk. Keys contain
+// arbitrary search queries, so files are named by their hash.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.cfg.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Cache) load(key string) (entry, bool) {
+	if c.cfg.Dir == "" {
+		return entry{}, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return entry{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return entry{}, false
+	}
+	if !c.now().Before(e.Expires) {
+		_ = os.Remove(c.path(key))
+		return entry{}, false
+	}
+	c.remember(e)
+	return e, true
+}
+
+// sweep removes expired and unreadable entries from disk, along with
+// temporary files left by interrupted writes. Entries are otherwise only
+// removed when read after expiry, so without it the directory would keep
+// every query ever made.
+func (c *Cache) sweep() {
+	if c.cfg.Dir == "" {
+		return
+	}
+	files, err := os.ReadDir(c.cfg.Dir)
+	if err != nil {
+		log.Printf("warning: failed to sweep cache directory: %v", err)
+		return
+	}
+	now := c.now()
+	for _, f := range files {
+		name := filepath.Join(c.cfg.Dir, f.Name())
+		switch {
+		case f.IsDir():
+			continue
+		case strings.HasSuffix(f.Name(), ".tmp"):
+			_ = os.Remove(name)
+		case strings.HasSuffix(f.Name(), ".json"):
+			data, err := os.ReadFile(name) //nolint:gosec // Name is from the cache's own directory.
+			var e entry
+			if err != nil || json.Unmarshal(data, &e) != nil || !now.Before(e.Expires) {
+				_ = os.Remove(name)
+			}
+		}
+	}
+}
+
+// save writes an entry to disk, if configured. Failures only cost a later
+// cache miss, so they are logged.
+func (c *Cache) save(e entry) {
+	if c.cfg.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// Write then rename so a concurrent reader never sees a partial file.
+	tmp, err := os.CreateTemp(c.cfg.Dir, "entry-*.tmp")
+	if err != nil {
+		log.Printf("warning: failed to write cache entry: %v", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(e.Key))
+	}
+	if err != nil {
+		log.Printf("warning: failed to write cache entry: %v", err)
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// Source is a ProductSource decorator that caches searches and product
+// details. Category browsing is passed through uncached.
+type Source struct {
+	datasource.ProductSource
+	cache *Cache
+	ttl   time.Duration
+}
+
+// SearchProducts returns cached results for the query, searching the
+// store if there are none or the context asks for fresh results.
+func (s *Source) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+	key := fmt.Sprintf("search\x00%s\x00%s", s.ID(), strings.Join(strings.Fields(strings.ToLower(query)), " "))
+	var products []datasource.Product
+	if !isFresh(ctx) && s.cache.get(key, &products) {
+		return products, nil
+	}
+	products, err := s.ProductSource.SearchProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(key, s.ttl, products)
+	return products, nil
+}
+
+// GetProductDetails returns cached details for the product, fetching them
+// if there are none or the context asks for fresh results.
+func (s *Source) GetProductDetails(ctx context.Context, productID string) (*datasource.Product, error) {
+	key := fmt.Sprintf("product\x00%s\x00%s", s.ID(), productID)
+	var product datasource.Product
+	if !isFresh(ctx) && s.cache.get(key, &product) {
+		return &product, nil
+	}
+	p, err := s.ProductSource.GetProductDetails(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(key, s.ttl, p)
+	return p, nil
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// countingSource returns a product priced by how many calls it has served.
+type countingSource struct {
+	id    datasource.SupermarketID
+	calls int
+	err   error
+}
+
+func (s *countingSource) ID() datasource.SupermarketID { return s.id }
+func (s *countingSource) Name() string                 { return string(s.id) }
+func (s *countingSource) Description() string          { return "" }
+
+func (s *countingSource) SearchProducts(_ context.Context, query string) ([]datasource.Product, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []datasource.Product{{ID: query, Supermarket: s.id, Price: float64(s.calls)}}, nil
+}
+
+func (s *countingSource) GetProductDetails(_ context.Context, productID string) (*datasource.Product, error) {
+	s.calls++
+	return &datasource.Product{ID: productID, Supermarket: s.id, Price: float64(s.calls)}, nil
+}
+
+func (s *countingSource) BrowseCategories(context.Context) ([]datasource.Category, error) {
+	s.calls++
+	return nil, nil
+}
+
+func newTestCache(cfg Config) (*Cache, *time.Time) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	c := newCache(cfg, func() time.Time { return now })
+	return c, &now
+}
+
+func TestSearchProducts(t *testing.T) {
+	c, now := newTestCache(Config{TTL: 10 * time.Minute})
+	src := &countingSource{id: datasource.Tesco}
+	ds := c.Wrap(src)
+	ctx := t.Context()
+
+	products, err := ds.SearchProducts(ctx, "milk")
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+
+	// Hits return a copy, so changing one doesn't change the cache.
+	products[0].Price = 99
+	products, err = ds.SearchProducts(ctx, " Milk ")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, products[0].Price, 0.001)
+	assert.Equal(t, 1, src.calls)
+
+	products, err = ds.SearchProducts(WithFresh(ctx), "milk")
+	require.NoError(t, err)
+	assert.InDelta(t, 2, products[0].Price, 0.001)
+
+	*now = now.Add(5 * time.Minute)
+	products, _ = ds.SearchProducts(ctx, "milk")
+	assert.InDelta(t, 2, products[0].Price, 0.001, "fresh results replace the cached ones")
+
+	*now = now.Add(10 * time.Minute)
+	products, _ = ds.SearchProducts(ctx, "milk")
+	assert.InDelta(t, 3, products[0].Price, 0.001)
+
+	_, _ = ds.BrowseCategories(ctx)
+	_, _ = ds.BrowseCategories(ctx)
+	assert.Equal(t, 5, src.calls, "categories are not cached")
+}
+
+func TestErrorsNotCached(t *testing.T) {
+	c, _ := newTestCache(Config{TTL: time.Minute})
+	src := &countingSource{id: datasource.Asda, err: errors.New("HTTP 503")}
+	ds := c.Wrap(src)
+
+	_, err := ds.SearchProducts(t.Context(), "bread")
+	require.Error(t, err)
+	src.err = nil
+	products, err := ds.SearchProducts(t.Context(), "bread")
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, 2, src.calls)
+}
+
+func TestStoreTTLs(t *testing.T) {
+	c, now := newTestCache(Config{
+		TTL:       time.Hour,
+		StoreTTLs: map[datasource.SupermarketID]time.Duration{datasource.Tesco: time.Minute, datasource.Asda: 0},
+	})
+	tesco := &countingSource{id: datasource.Tesco}
+	ds := c.Wrap(tesco)
+
+	asda := &countingSource{id: datasource.Asda}
+	assert.Same(t, asda, c.Wrap(asda), "a zero TTL disables caching")
+
+	_, _ = ds.GetProductDetails(t.Context(), "254656543")
+	*now = now.Add(2 * time.Minute)
+	_, _ = ds.GetProductDetails(t.Context(), "254656543")
+	assert.Equal(t, 2, tesco.calls)
+
+	var nilCache *Cache
+	assert.Same(t, tesco, nilCache.Wrap(tesco))
+}
+
+func TestOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	c, _ := newTestCache(Config{TTL: time.Hour, Dir: dir})
+	src := &countingSource{id: datasource.Ocado}
+	_, err := c.Wrap(src).GetProductDetails(t.Context(), "12345")
+	require.NoError(t, err)
+
+	// A new cache, as after a restart, reads the entry from disk.
+	restarted, now := newTestCache(Config{TTL: time.Hour, Dir: dir})
+	product, err := restarted.Wrap(src).GetProductDetails(t.Context(), "12345")
+	require.NoError(t, err)
+	assert.Equal(t, "12345", product.ID)
+	assert.Equal(t, 1, src.calls)
+
+	expired, _ := newTestCache(Config{TTL: time.Hour, Dir: dir})
+	expired.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = expired.Wrap(src).GetProductDetails(t.Context(), "12345")
+	require.NoError(t, err)
+	assert.Equal(t, 2, src.calls)
+}
+
+func TestSweep(t *testing.T) {
+	dir := t.TempDir()
+	c, now := newTestCache(Config{TTL: time.Hour, Dir: dir})
+	src := &countingSource{id: datasource.Ocado}
+	for _, id := range []string{"1", "2"} {
+		_, err := c.Wrap(src).GetProductDetails(t.Context(), id)
+		require.NoError(t, err)
+	}
+	*now = now.Add(30 * time.Minute)
+	_, err := c.Wrap(src).GetProductDetails(WithFresh(t.Context()), "2")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry-1.tmp"), []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0o600))
+
+	// After an hour only the refreshed entry is left.
+	later := newCache(Config{TTL: time.Hour, Dir: dir}, func() time.Time { return now.Add(45 * time.Minute) })
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, filepath.Base(later.path("product\x00ocado\x002")), files[0].Name())
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("CACHE_TTL", "30m")
+	t.Setenv("WAITROSE_CACHE_TTL", "0")
+	t.Setenv("TESCO_CACHE_TTL", "soon")
+	t.Setenv("MYSHOP_CACHE_TTL", "1h")
+	t.Setenv("CACHE_DIR", "")
+
+	ids := append([]datasource.SupermarketID{"myshop"}, datasource.AllSupermarkets...)
+	cfg := LoadConfig(ids)
+	assert.Equal(t, 30*time.Minute, cfg.TTL)
+	assert.Equal(t, map[datasource.SupermarketID]time.Duration{
+		datasource.Waitrose: 0,
+		"myshop":            time.Hour,
+	}, cfg.StoreTTLs)
+	assert.Empty(t, cfg.Dir)
+}
@@ -9,18 +9,25 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/time/rate"
 
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/auth"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/cache"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/aldi"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/asda"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/coop"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/iceland"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/lidl"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/osp"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/sainsburys"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/shopify"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/tesco"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/waitrose"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/pricehistory"
 )
 
 // SupermarketInfo describes a supported supermarket for the list tool.
@@ -96,11 +103,25 @@ func (a *authResolver) tryReauth(ctx context.Context, err error) bool {
 
 // Client orchestrates multiple supermarket product sources.
 type Client struct {
+	// ids lists the registered supermarkets: the built-in ones in
+	// datasource.AllSupermarkets order, then any user-defined stores.
+	ids          []datasource.SupermarketID
 	products     map[datasource.SupermarketID]datasource.ProductSource
 	orderHistory map[datasource.SupermarketID]datasource.OrderHistorySource
 	baskets      map[datasource.SupermarketID]datasource.BasketSource
+	slots        map[datasource.SupermarketID]datasource.SlotSource
+	slotBookers  map[datasource.SupermarketID]datasource.SlotBooker
 	auth         map[datasource.SupermarketID]*authResolver
+	logins       map[datasource.SupermarketID]bool
 	browser      *scraper.Browser
+	minimums     map[datasource.SupermarketID]float64
+	history      *pricehistory.Store
+
+	// failed records user-defined stores that failed their startup test
+	// search. They stay registered but are left out of ListSupermarkets
+	// and searches across all supermarkets.
+	failedMu sync.Mutex
+	failed   map[datasource.SupermarketID]error
 }
 
 // Config holds configuration for creating a Client.
@@ -116,6 +137,18 @@ type Config struct {
 	// ChromeExecPath overrides the Chrome/Chromium binary the shared Browser
 	// launches. See scraper.BrowserConfig.ExecPath for why this may be needed.
 	ChromeExecPath string
+	// DeliveryMinimums holds each supermarket's minimum order value for
+	// delivery, used when planning baskets.
+	DeliveryMinimums map[datasource.SupermarketID]float64
+	// History, if set, records every product price seen by searches and
+	// product lookups.
+	History *pricehistory.Store
+	// Cache, if set, caches searches and product details for each
+	// supermarket. Use cache.WithFresh to bypass it for a lookup.
+	Cache *cache.Cache
+	// ShopifyStores holds user-defined Shopify stores to register alongside
+	// the built-in supermarkets. See shopify.LoadStores.
+	ShopifyStores []shopify.Config
 }
 
 // NewClient creates a new client with all supermarket datasources.
@@ -138,31 +171,109 @@ func NewClient(cfg Config) *Client {
 		osp.NewMorrisons(osp.Config{}, httpClient()),
 	}
 
+	// Plain (non-auth) datasources.
+	plainSources := []datasource.ProductSource{
+		lidl.NewDatasource(lidl.Config{}, httpClient()),
+		aldi.NewDatasource(aldi.Config{}, httpClient()),
+		iceland.NewDatasource(iceland.Config{}, httpClient()),
+		coop.NewDatasource(coop.Config{}, httpClient()),
+		shopify.NewHiyou(httpClient()),
+		shopify.NewTukTukMart(httpClient()),
+		shopify.NewMorueats(httpClient()),
+	}
+	custom := make([]*shopify.Datasource, 0, len(cfg.ShopifyStores))
+	for _, sc := range cfg.ShopifyStores {
+		ds := shopify.NewDatasource(sc, httpClient())
+		custom = append(custom, ds)
+		plainSources = append(plainSources, ds)
+	}
+
 	c := &Client{
-		products:     make(map[datasource.SupermarketID]datasource.ProductSource, len(sources)+3),
+		products:     make(map[datasource.SupermarketID]datasource.ProductSource, len(sources)+len(plainSources)),
 		orderHistory: make(map[datasource.SupermarketID]datasource.OrderHistorySource),
 		baskets:      make(map[datasource.SupermarketID]datasource.BasketSource),
+		slots:        make(map[datasource.SupermarketID]datasource.SlotSource),
+		slotBookers:  make(map[datasource.SupermarketID]datasource.SlotBooker),
 		auth:         make(map[datasource.SupermarketID]*authResolver),
+		logins:       cfg.LoginFlags,
 		browser:      browser,
+		minimums:     cfg.DeliveryMinimums,
+		history:      cfg.History,
+		failed:       make(map[datasource.SupermarketID]error),
 	}
 
 	for _, ds := range sources {
 		c.registerAuthDatasource(ds, cfg)
 	}
 
-	// Add plain (non-auth) datasources.
-	plainSources := []datasource.ProductSource{
-		shopify.NewHiyou(httpClient()),
-		shopify.NewTukTukMart(httpClient()),
-		shopify.NewMorueats(httpClient()),
-	}
 	for _, ds := range plainSources {
-		c.products[ds.ID()] = ds
+		c.products[ds.ID()] = cfg.Cache.Wrap(ds)
+	}
+
+	for _, id := range SupermarketIDs(cfg.ShopifyStores) {
+		if _, ok := c.products[id]; ok {
+			c.ids = append(c.ids, id)
+		}
+	}
+
+	// Validate in the background so a slow store doesn't hold up startup.
+	for _, ds := range custom {
+		go c.validateShopifyStore(ds)
 	}
 
 	return c
 }
 
+// shopifyValidateTimeout bounds how long a user-defined Shopify store has
+// to answer its test search.
+const shopifyValidateTimeout = 15 * time.Second
+
+// validateShopifyStore sends a user-defined store a test search, marking it
+// failed if its search endpoint doesn't respond as Shopify's does.
+func (c *Client) validateShopifyStore(ds *shopify.Datasource) {
+	ctx, cancel := context.WithTimeout(context.Background(), shopifyValidateTimeout)
+	defer cancel()
+	if err := ds.Validate(ctx); err != nil {
+		log.Printf("warning: disabling shopify store %s: %v", ds.ID(), err)
+		c.failedMu.Lock()
+		c.failed[ds.ID()] = err
+		c.failedMu.Unlock()
+	}
+}
+
+// failure returns why a user-defined store failed validation, or nil.
+func (c *Client) failure(id datasource.SupermarketID) error {
+	c.failedMu.Lock()
+	defer c.failedMu.Unlock()
+	return c.failed[id]
+}
+
+// activeIDs returns the registered supermarkets less any user-defined
+// stores that failed validation.
+func (c *Client) activeIDs() []datasource.SupermarketID {
+	ids := make([]datasource.SupermarketID, 0, len(c.ids))
+	for _, id := range c.ids {
+		if c.failure(id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SupermarketIDs returns the supermarkets a client can register with the
+// given user-defined Shopify stores: the built-in ones in
+// datasource.AllSupermarkets order, then the user-defined ones. NewClient
+// lists only those it has a datasource for. Settings read per supermarket at
+// startup should cover all of these.
+func SupermarketIDs(shopifyStores []shopify.Config) []datasource.SupermarketID {
+	ids := make([]datasource.SupermarketID, 0, len(datasource.AllSupermarkets)+len(shopifyStores))
+	ids = append(ids, datasource.AllSupermarkets...)
+	for _, sc := range shopifyStores {
+		ids = append(ids, sc.ID)
+	}
+	return ids
+}
+
 func (c *Client) registerAuthDatasource(ds datasource.AuthProductSource, cfg Config) {
 	id := ds.ID()
 
@@ -218,7 +329,7 @@ func (c *Client) registerAuthDatasource(ds datasource.AuthProductSource, cfg Con
 		}
 	}
 
-	c.products[id] = ds
+	c.products[id] = cfg.Cache.Wrap(ds)
 
 	if oh, ok := ds.(datasource.OrderHistorySource); ok {
 		c.orderHistory[id] = oh
@@ -226,6 +337,12 @@ func (c *Client) registerAuthDatasource(ds datasource.AuthProductSource, cfg Con
 	if b, ok := ds.(datasource.BasketSource); ok {
 		c.baskets[id] = b
 	}
+	if ss, ok := ds.(datasource.SlotSource); ok {
+		c.slots[id] = ss
+	}
+	if sb, ok := ds.(datasource.SlotBooker); ok {
+		c.slotBookers[id] = sb
+	}
 }
 
 // Close releases resources held by the client (e.g. headless browser).
@@ -241,10 +358,26 @@ func (c *Client) ensureAuth(ctx context.Context, id datasource.SupermarketID) {
 	}
 }
 
+type withoutAuthKey struct{}
+
+// WithoutAuth returns a context whose requests never start a login or
+// re-login. Stores use whatever session they already have.
+func WithoutAuth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutAuthKey{}, true)
+}
+
+func authSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(withoutAuthKey{}).(bool)
+	return skip
+}
+
 func withAuth[T any](
 	c *Client, ctx context.Context, id datasource.SupermarketID,
 	fn func() (T, error),
 ) (T, error) {
+	if authSkipped(ctx) {
+		return fn()
+	}
 	c.ensureAuth(ctx, id)
 	result, err := fn()
 	if err != nil {
@@ -265,7 +398,7 @@ func (c *Client) SearchAll(
 ) []datasource.SearchResult {
 	targets := supermarkets
 	if len(targets) == 0 {
-		targets = datasource.AllSupermarkets
+		targets = c.activeIDs()
 	}
 
 	results := make([]datasource.SearchResult, len(targets))
@@ -279,6 +412,13 @@ func (c *Client) SearchAll(
 			}
 			continue
 		}
+		if err := c.failure(id); err != nil {
+			results[i] = datasource.SearchResult{
+				Supermarket: id,
+				Error:       fmt.Sprintf("%s is disabled: %v", id, err),
+			}
+			continue
+		}
 
 		wg.Add(1)
 		go func(idx int, ds datasource.ProductSource, sid datasource.SupermarketID) {
@@ -304,20 +444,43 @@ func (c *Client) SearchAll(
 	}
 
 	wg.Wait()
+
+	var seen []datasource.Product
+	for _, r := range results {
+		seen = append(seen, r.Products...)
+	}
+	c.recordPrices(seen)
 	return results
 }
 
-// ListSupermarkets returns info about all supported supermarkets.
+// recordPrices adds products to the price history, if one is configured.
+// Failures are logged rather than failing the lookup that saw the prices.
+func (c *Client) recordPrices(products []datasource.Product) {
+	if c.history == nil || len(products) == 0 {
+		return
+	}
+	if err := c.history.Record(products); err != nil {
+		log.Printf("warning: failed to record price history: %v", err)
+	}
+}
+
+// History returns the price history store, or nil if none is configured.
+func (c *Client) History() *pricehistory.Store {
+	return c.history
+}
+
+// ListSupermarkets returns info about all supported supermarkets, leaving
+// out user-defined stores that failed validation.
 func (c *Client) ListSupermarkets() []SupermarketInfo {
-	var infos []SupermarketInfo
-	for _, id := range datasource.AllSupermarkets {
-		if ds, ok := c.products[id]; ok {
-			infos = append(infos, SupermarketInfo{
-				ID:          id,
-				Name:        ds.Name(),
-				Description: ds.Description(),
-			})
-		}
+	ids := c.activeIDs()
+	infos := make([]SupermarketInfo, 0, len(ids))
+	for _, id := range ids {
+		ds := c.products[id]
+		infos = append(infos, SupermarketInfo{
+			ID:          id,
+			Name:        ds.Name(),
+			Description: ds.Description(),
+		})
 	}
 	return infos
 }
@@ -346,9 +509,19 @@ func (c *Client) GetProductDetails(
 	if !ok {
 		return nil, fmt.Errorf("unknown supermarket: %s", id)
 	}
-	return withAuth(c, ctx, id, func() (*datasource.Product, error) {
+	product, err := withAuth(c, ctx, id, func() (*datasource.Product, error) {
 		return ds.GetProductDetails(ctx, productID)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Product pages don't always carry the ID or store, so record the
+	// price under the ones it was looked up by.
+	seen := *product
+	seen.ID, seen.Supermarket = productID, id
+	c.recordPrices([]datasource.Product{seen})
+	return product, nil
 }
 
 // GetOrderHistory retrieves order history for a supermarket.
@@ -366,6 +539,43 @@ func (c *Client) GetOrderHistory(
 	})
 }
 
+// GetAllOrderHistory retrieves a page of order history concurrently from
+// every login-enabled supermarket that supports it. A failure at one
+// supermarket is reported in its result's Error rather than failing the
+// whole request.
+func (c *Client) GetAllOrderHistory(
+	ctx context.Context,
+	page int,
+) []datasource.OrderHistoryResult {
+	var targets []datasource.SupermarketID
+	for _, id := range c.ids {
+		if _, ok := c.orderHistory[id]; ok && c.logins[id] {
+			targets = append(targets, id)
+		}
+	}
+
+	results := make([]datasource.OrderHistoryResult, len(targets))
+	var wg sync.WaitGroup
+	for i, id := range targets {
+		wg.Add(1)
+		go func(idx int, sid datasource.SupermarketID) {
+			defer wg.Done()
+			result, err := c.GetOrderHistory(ctx, sid, page)
+			if err != nil {
+				results[idx] = datasource.OrderHistoryResult{
+					Supermarket: sid,
+					Page:        page,
+					Error:       err.Error(),
+				}
+				return
+			}
+			results[idx] = *result
+		}(i, id)
+	}
+	wg.Wait()
+	return results
+}
+
 // GetBasket retrieves the current basket for a supermarket.
 func (c *Client) GetBasket(
 	ctx context.Context,
@@ -397,6 +607,74 @@ func (c *Client) UpdateBasketItem(
 	})
 }
 
+// GetSlots retrieves the delivery and click-and-collect slots starting
+// between from and to for a supermarket.
+func (c *Client) GetSlots(
+	ctx context.Context,
+	id datasource.SupermarketID,
+	from, to time.Time,
+) ([]datasource.Slot, error) {
+	ds, ok := c.slots[id]
+	if !ok {
+		return nil, fmt.Errorf("slot lookup is not supported for %s", id)
+	}
+	return withAuth(c, ctx, id, func() ([]datasource.Slot, error) {
+		return ds.GetSlots(ctx, from, to)
+	})
+}
+
+// GetAllSlots retrieves slots concurrently from every login-enabled
+// supermarket that supports slot lookup. A failure at one supermarket is
+// reported in its result's Error rather than failing the whole request.
+func (c *Client) GetAllSlots(
+	ctx context.Context,
+	from, to time.Time,
+) []datasource.SlotResult {
+	var targets []datasource.SupermarketID
+	for _, id := range c.ids {
+		if _, ok := c.slots[id]; ok && c.logins[id] {
+			targets = append(targets, id)
+		}
+	}
+
+	results := make([]datasource.SlotResult, len(targets))
+	var wg sync.WaitGroup
+	for i, id := range targets {
+		wg.Add(1)
+		go func(idx int, sid datasource.SupermarketID) {
+			defer wg.Done()
+			slots, err := c.GetSlots(ctx, sid, from, to)
+			if err != nil {
+				results[idx] = datasource.SlotResult{Supermarket: sid, Error: err.Error()}
+				return
+			}
+			results[idx] = datasource.SlotResult{Supermarket: sid, Slots: slots}
+		}(i, id)
+	}
+	wg.Wait()
+	return results
+}
+
+// BookSlot books a delivery or click-and-collect slot at a supermarket.
+func (c *Client) BookSlot(
+	ctx context.Context,
+	id datasource.SupermarketID,
+	slotID string,
+) (*datasource.Slot, error) {
+	ds, ok := c.slotBookers[id]
+	if !ok {
+		return nil, fmt.Errorf("slot booking is not supported for %s", id)
+	}
+	return withAuth(c, ctx, id, func() (*datasource.Slot, error) {
+		return ds.BookSlot(ctx, slotID)
+	})
+}
+
+// DeliveryMinimums returns the configured minimum order value per supermarket.
+func (c *Client) DeliveryMinimums() map[datasource.SupermarketID]float64 {
+	return c.minimums
+}
+
 // ParseSupermarketIDs parses a comma-separated list of supermarket IDs.
 func ParseSupermarketIDs(s string) []datasource.SupermarketID {
 	if s == "" {
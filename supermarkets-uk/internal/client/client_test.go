@@ -1,18 +1,23 @@
 package client_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/shopify"
 )
 
 func TestNewClient(t *testing.T) {
 	c := client.NewClient(client.Config{})
 
 	infos := c.ListSupermarkets()
-	if len(infos) != 9 {
-		t.Fatalf("expected 9 supermarkets, got %d", len(infos))
+	if len(infos) != 13 {
+		t.Fatalf("expected 13 supermarkets, got %d", len(infos))
 	}
 
 	type expectedInfo struct {
@@ -28,6 +33,10 @@ func TestNewClient(t *testing.T) {
 		datasource.Waitrose:   {"Waitrose", "Premium UK supermarket chain"},
 		datasource.Hiyou:      {"HiYoU", "Asian supermarket based in Newcastle"},
 		datasource.TukTukMart: {"Tuk Tuk Mart", "Manchester-based Asian supermarket (Hang Won Hong's online store)"},
+		datasource.Lidl:       {"Lidl", "Discount supermarket chain"},
+		datasource.Aldi:       {"Aldi", "Discount supermarket chain"},
+		datasource.Iceland:    {"Iceland", "Frozen food specialist supermarket chain"},
+		datasource.Coop:       {"Co-op", "Convenience and community supermarket chain"},
 		datasource.Morueats:   {"Morueats", "Asian grocery covering Japanese, Chinese, Korean, and Thai products"},
 	}
 
@@ -46,6 +55,111 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientShopifyStores(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"resources":{"results":{"products":[]}}}`))
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.Config{ShopifyStores: []shopify.Config{{
+		ID:          "seoulplaza",
+		Name:        "Seoul Plaza",
+		Description: "Korean grocery",
+		BaseURL:     srv.URL,
+	}}})
+
+	infos := c.ListSupermarkets()
+	if len(infos) != 14 {
+		t.Fatalf("expected 14 supermarkets, got %d", len(infos))
+	}
+	last := infos[len(infos)-1]
+	if last.ID != "seoulplaza" || last.Name != "Seoul Plaza" || last.Description != "Korean grocery" {
+		t.Errorf("user-defined store listed as %+v", last)
+	}
+}
+
+func TestNewClientShopifyStoreFailsValidation(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c := client.NewClient(client.Config{ShopifyStores: []shopify.Config{{
+		ID:      "notshopify",
+		Name:    "Not Shopify",
+		BaseURL: srv.URL,
+	}}})
+
+	// Validation runs in the background, so wait for the store to drop out.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.ListSupermarkets()) != 13 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the store to be left out, got %d supermarkets", len(c.ListSupermarkets()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	results := c.SearchAll(t.Context(), "milk", []datasource.SupermarketID{"notshopify"})
+	if len(results) != 1 || !strings.Contains(results[0].Error, "disabled") {
+		t.Errorf("expected a disabled error searching the store, got %+v", results)
+	}
+}
+
+func TestGetAllOrderHistory(t *testing.T) {
+	c := client.NewClient(client.Config{})
+	if results := c.GetAllOrderHistory(t.Context(), 1); len(results) != 0 {
+		t.Fatalf("expected no results without login, got %d", len(results))
+	}
+
+	// Login-enabled stores without a session report per-store errors.
+	c = client.NewClient(client.Config{LoginFlags: map[datasource.SupermarketID]bool{
+		datasource.Sainsburys: true,
+		datasource.Ocado:      true,
+		datasource.Hiyou:      true,
+	}})
+	results := c.GetAllOrderHistory(t.Context(), 1)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Supermarket != datasource.Sainsburys || results[1].Supermarket != datasource.Ocado {
+		t.Errorf("unexpected order: %s, %s", results[0].Supermarket, results[1].Supermarket)
+	}
+	for _, r := range results {
+		if r.Error == "" {
+			t.Errorf("%s: expected an error without a session", r.Supermarket)
+		}
+	}
+}
+
+func TestGetAllSlots(t *testing.T) {
+	from := time.Now()
+	c := client.NewClient(client.Config{})
+	if results := c.GetAllSlots(t.Context(), from, from.AddDate(0, 0, 7)); len(results) != 0 {
+		t.Fatalf("expected no results without login, got %d", len(results))
+	}
+
+	// Asda is login-enabled but has no slot lookup.
+	c = client.NewClient(client.Config{LoginFlags: map[datasource.SupermarketID]bool{
+		datasource.Morrisons:  true,
+		datasource.Sainsburys: true,
+		datasource.Asda:       true,
+	}})
+	results := c.GetAllSlots(t.Context(), from, from.AddDate(0, 0, 7))
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Supermarket != datasource.Sainsburys || results[1].Supermarket != datasource.Morrisons {
+		t.Errorf("unexpected order: %s, %s", results[0].Supermarket, results[1].Supermarket)
+	}
+	for _, r := range results {
+		if r.Error == "" {
+			t.Errorf("%s: expected an error without a session", r.Supermarket)
+		}
+	}
+
+	if _, err := c.BookSlot(t.Context(), datasource.Sainsburys, "slot"); err == nil {
+		t.Error("expected booking to be unsupported for sainsburys")
+	}
+}
+
 func TestParseSupermarketIDs(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,234 @@
+// Package aldi provides a datasource for Aldi using the JSON API behind
+// its website.
+package aldi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+const (
+	apiBase = "https://api.aldi.co.uk"
+	baseURL = "https://www.aldi.co.uk"
+)
+
+// apiProduct is a product from the Aldi product search and product APIs.
+type apiProduct struct {
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	BrandName   string `json:"brandName"`
+	URLSlugText string `json:"urlSlugText"`
+	SellingSize string `json:"sellingSize"`
+	NotForSale  bool   `json:"notForSale"`
+	Price       struct {
+		// Amount is in pence.
+		Amount            int    `json:"amount"`
+		ComparisonDisplay string `json:"comparisonDisplay"`
+		WasPriceDisplay   string `json:"wasPriceDisplay"`
+	} `json:"price"`
+	Assets []struct {
+		URL string `json:"url"`
+	} `json:"assets"`
+	Description string `json:"description"`
+}
+
+type searchResponse struct {
+	Data []apiProduct `json:"data"`
+}
+
+type productResponse struct {
+	Data apiProduct `json:"data"`
+}
+
+type categoryTreeResponse struct {
+	Data []struct {
+		Key         string `json:"key"`
+		Name        string `json:"name"`
+		URLSlugText string `json:"urlSlugText"`
+	} `json:"data"`
+}
+
+// Config holds optional overrides for an Aldi datasource.
+// Zero values use the built-in defaults.
+type Config struct {
+	BaseURL string
+}
+
+// Datasource uses the Aldi JSON API. Aldi has no login, so it is a plain
+// ProductSource.
+type Datasource struct {
+	httpClient *http.Client
+	apiBase    string
+}
+
+// NewDatasource creates a new Aldi API datasource.
+func NewDatasource(cfg Config, httpClient *http.Client) *Datasource {
+	base := apiBase
+	if cfg.BaseURL != "" {
+		base = cfg.BaseURL
+	}
+	return &Datasource{httpClient: httpClient, apiBase: base}
+}
+
+func (d *Datasource) ID() datasource.SupermarketID { return datasource.Aldi }
+func (d *Datasource) Name() string                 { return "Aldi" }
+func (d *Datasource) Description() string          { return "Discount supermarket chain" }
+
+// SearchProducts searches for products using the Aldi product search API.
+func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+	apiURL := d.apiBase + "/v3/product-search?" + url.Values{
+		"currency":    {"GBP"},
+		"serviceType": {"walk-in"},
+		"q":           {query},
+		"limit":       {"30"},
+		"offset":      {"0"},
+		"sort":        {"relevance"},
+	}.Encode()
+
+	body, err := d.apiRequest(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("aldi search: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return ParseSearchResults(body)
+}
+
+// ParseSearchResults parses an Aldi product search API response.
+func ParseSearchResults(r io.Reader) ([]datasource.Product, error) {
+	var resp searchResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("aldi: decode search response: %w", err)
+	}
+	products := make([]datasource.Product, 0, len(resp.Data))
+	for _, ap := range resp.Data {
+		products = append(products, convertProduct(ap))
+	}
+	return products, nil
+}
+
+// GetProductDetails fetches details for a specific product by SKU.
+func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*datasource.Product, error) {
+	apiURL := d.apiBase + "/v2/products/" + url.PathEscape(productID) + "?" + url.Values{
+		"currency":    {"GBP"},
+		"serviceType": {"walk-in"},
+	}.Encode()
+
+	body, err := d.apiRequest(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("aldi product: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return ParseProduct(body)
+}
+
+// ParseProduct parses an Aldi product API response.
+func ParseProduct(r io.Reader) (*datasource.Product, error) {
+	var resp productResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("aldi: decode product: %w", err)
+	}
+	if resp.Data.SKU == "" {
+		return nil, fmt.Errorf("aldi: product not found")
+	}
+	p := convertProduct(resp.Data)
+	return &p, nil
+}
+
+// BrowseCategories returns the top-level product categories.
+func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Category, error) {
+	apiURL := d.apiBase + "/v2/product-category-tree?" + url.Values{"serviceType": {"walk-in"}}.Encode()
+
+	body, err := d.apiRequest(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("aldi categories: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return ParseCategories(body)
+}
+
+// ParseCategories parses an Aldi category tree API response.
+func ParseCategories(r io.Reader) ([]datasource.Category, error) {
+	var resp categoryTreeResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("aldi: decode categories: %w", err)
+	}
+	categories := make([]datasource.Category, 0, len(resp.Data))
+	for _, c := range resp.Data {
+		categories = append(categories, datasource.Category{
+			ID:          c.Key,
+			Name:        c.Name,
+			URL:         baseURL + "/products/" + c.URLSlugText + "/k/" + c.Key,
+			Supermarket: datasource.Aldi,
+		})
+	}
+	return categories, nil
+}
+
+func (d *Datasource) apiRequest(ctx context.Context, apiURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	scraper.SetBrowserHeaders(req)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Origin", baseURL)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, apiURL)
+	}
+	return resp.Body, nil
+}
+
+func convertProduct(ap apiProduct) datasource.Product {
+	p := datasource.Product{
+		ID:           ap.SKU,
+		Supermarket:  datasource.Aldi,
+		Name:         ap.Name,
+		Price:        float64(ap.Price.Amount) / 100,
+		PricePerUnit: ap.Price.ComparisonDisplay,
+		Currency:     "GBP",
+		Available:    datasource.BoolPtr(!ap.NotForSale),
+		Weight:       ap.SellingSize,
+		Brand:        ap.BrandName,
+		Description:  stripHTML(ap.Description),
+	}
+	if ap.URLSlugText != "" {
+		p.URL = baseURL + "/product/" + ap.URLSlugText + "-" + ap.SKU
+	}
+	if len(ap.Assets) > 0 {
+		// Asset URLs are templates with the image width left open.
+		p.ImageURL = strings.ReplaceAll(ap.Assets[0].URL, "{width}", "500")
+	}
+	if ap.Price.WasPriceDisplay != "" {
+		p.Promotion = fmt.Sprintf("Was %s Now £%.2f", ap.Price.WasPriceDisplay, p.Price)
+	}
+	p.Normalise()
+	return p
+}
+
+// stripHTML parses an HTML fragment and returns its text content.
+func stripHTML(s string) string {
+	if s == "" {
+		return ""
+	}
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	return scraper.TextContent(doc)
+}
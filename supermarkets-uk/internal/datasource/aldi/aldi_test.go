@@ -0,0 +1,64 @@
+package aldi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/aldi"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseSearchResults(t *testing.T) {
+	products := testutil.ParseSearchFile(t, "testdata/aldi_search.json", aldi.ParseSearchResults)
+	require.Len(t, products, 2)
+
+	p := products[0]
+	assert.Equal(t, "Cowbelle British Semi-Skimmed Milk 4 Pints", p.Name)
+	assert.Equal(t, "000000000000590041", p.ID)
+	assert.Equal(t, datasource.Aldi, p.Supermarket)
+	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.Equal(t, "COWBELLE", p.Brand)
+	assert.True(t, *p.Available)
+	assert.Equal(t,
+		"https://www.aldi.co.uk/product/cowbelle-british-semi-skimmed-milk-4-pints-000000000000590041", p.URL)
+	assert.Contains(t, p.ImageURL, "/scaleWidth/500/")
+	require.NotNil(t, p.Quantity)
+	assert.InDelta(t, 2272, p.Quantity.Total(), 0.001)
+	require.NotNil(t, p.UnitPrice)
+	assert.InDelta(t, 0.64, p.UnitPrice.Price, 0.001)
+
+	p2 := products[1]
+	assert.False(t, *p2.Available, "notForSale products should be unavailable")
+	assert.Equal(t, "Was £1.29 Now £0.99", p2.Promotion)
+	require.Len(t, p2.Offers, 1)
+	assert.Equal(t, datasource.SalePrice, p2.Offers[0].Kind)
+	assert.Empty(t, p2.ImageURL)
+}
+
+func TestGetProductDetails(t *testing.T) {
+	srv := testutil.JSONFixtureServer(t, "testdata/aldi_product.json")
+	ds := aldi.NewDatasource(aldi.Config{BaseURL: srv.URL}, srv.Client())
+
+	p, err := ds.GetProductDetails(t.Context(), "000000000000590041")
+	require.NoError(t, err)
+	assert.Equal(t, "Cowbelle British Semi-Skimmed Milk 4 Pints", p.Name)
+	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.Contains(t, p.Description, "100% British milk")
+	assert.NotContains(t, p.Description, "<li>")
+}
+
+func TestBrowseCategories(t *testing.T) {
+	srv := testutil.JSONFixtureServer(t, "testdata/aldi_categories.json")
+	ds := aldi.NewDatasource(aldi.Config{BaseURL: srv.URL}, srv.Client())
+
+	categories, err := ds.BrowseCategories(t.Context())
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "Chilled Food", categories[0].Name)
+	assert.Equal(t, "1588161416978050", categories[0].ID)
+	assert.Equal(t, "https://www.aldi.co.uk/products/chilled-food/k/1588161416978050", categories[0].URL)
+	assert.Equal(t, datasource.Aldi, categories[0].Supermarket)
+}
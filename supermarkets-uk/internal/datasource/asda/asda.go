@@ -65,6 +65,7 @@ type algoliaHit struct {
 	ID              string                  `json:"ID"`
 	ObjectID        string                  `json:"objectID"`
 	Name            string                  `json:"NAME"`
+	Brand           string                  `json:"BRAND"`
 	ImageID         string                  `json:"IMAGE_ID"`
 	PackSize        string                  `json:"PACK_SIZE"`
 	Status          string                  `json:"STATUS"` // "A" = active/available, "I" = inactive/unavailable
@@ -118,8 +119,9 @@ func (d *Datasource) CheckSession(ctx context.Context) bool {
 	return scraper.HTMLHasElement(body, sessionCheckQuery)
 }
 
-// SearchProducts searches for products via the Algolia API.
-func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+// NewSearchRequest builds the Algolia API request for a product search,
+// whose response ParseSearchResults parses.
+func NewSearchRequest(ctx context.Context, query string) (*http.Request, error) {
 	payload, err := json.Marshal(map[string]string{
 		"params": "query=" + url.QueryEscape(query) + "&hitsPerPage=60",
 	})
@@ -134,6 +136,15 @@ func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]dataso
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Algolia-Application-Id", algoliaAppID)
 	req.Header.Set("X-Algolia-API-Key", algoliaAPIKey)
+	return req, nil
+}
+
+// SearchProducts searches for products via the Algolia API.
+func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+	req, err := NewSearchRequest(ctx, query)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := d.httpClient.Do(req)
 	if err != nil {
@@ -235,6 +246,7 @@ func convertHit(hit algoliaHit) datasource.Product {
 		ID:          hit.ObjectID,
 		Supermarket: datasource.Asda,
 		Name:        hit.Name,
+		Brand:       hit.Brand,
 		Currency:    "GBP",
 		Available:   datasource.BoolPtr(hit.Status == "A"),
 		URL:         baseURL + "/groceries/product/" + url.PathEscape(hit.ObjectID),
@@ -265,6 +277,7 @@ func convertHit(hit algoliaHit) datasource.Product {
 			}
 		}
 	}
+	p.Normalise()
 
 	return p
 }
@@ -23,6 +23,7 @@ func TestParseSearchResults(t *testing.T) {
 	assert.Equal(t, datasource.Asda, p.Supermarket)
 	assert.Equal(t, "GBP", p.Currency)
 	assert.NotEmpty(t, p.Name)
+	assert.Equal(t, "ASDA", p.Brand)
 	assert.NotZero(t, p.Price)
 	assert.NotEmpty(t, p.ID)
 	assert.NotEmpty(t, p.URL)
@@ -54,6 +55,31 @@ func TestParseCategories(t *testing.T) {
 	assert.NotEmpty(t, categories[0].URL)
 }
 
+func TestParseOrderHistory(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/asda_orders.html")
+	result, err := asda.ParseOrderHistory(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Asda, result.Supermarket)
+	assert.Nil(t, result.Total)
+	require.Len(t, result.Orders, 2)
+
+	o := result.Orders[0]
+	assert.Equal(t, "31234567890", o.ID)
+	assert.Equal(t, datasource.Asda, o.Supermarket)
+	assert.Equal(t, "2026-03-05", o.Date)
+	assert.Equal(t, "Delivered", o.Status)
+	assert.Equal(t, "Home delivery", o.ShoppingMethod)
+	assert.Equal(t, "Sat 7 Mar, 8:00am–9:00am", o.DeliverySlot)
+	assert.Equal(t, 28, o.TotalItems)
+	assert.InDelta(t, 61.37, o.TotalPrice, 0.001)
+	assert.Equal(t, "GBP", o.Currency)
+
+	assert.Equal(t, "2026-02-20", result.Orders[1].Date)
+	assert.Equal(t, 1, result.Orders[1].TotalItems)
+	assert.Empty(t, result.Orders[1].DeliverySlot)
+}
+
 func TestSearchIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -0,0 +1,67 @@
+package asda
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+const ordersURL = baseURL + "/account/orders"
+
+// ordersWaitSelector waits for the order history section, which renders
+// even when there are no past orders.
+const ordersWaitSelector = `[data-auto-id="order-history"]`
+
+var orderSelectors = scraper.OrderSelectors{
+	Root:      scraper.ElemSel{Tag: "section", Att: "data-auto-id", Val: "order-history"},
+	Container: scraper.ElemSel{Tag: "div", Att: "data-auto-id", Val: "order-card"},
+	ID:        scraper.ElemSel{Tag: "a", Att: "data-auto-id", Val: "order-number"},
+	Date:      scraper.ElemSel{Tag: "p", Att: "data-auto-id", Val: "order-date"},
+	Status:    scraper.ElemSel{Tag: "span", Att: "data-auto-id", Val: "order-status"},
+	Total:     scraper.ElemSel{Tag: "p", Att: "data-auto-id", Val: "order-total"},
+	Items:     scraper.ElemSel{Tag: "p", Att: "data-auto-id", Val: "order-item-count"},
+	Slot:      scraper.ElemSel{Tag: "p", Att: "data-auto-id", Val: "order-slot"},
+	Method:    scraper.ElemSel{Tag: "p", Att: "data-auto-id", Val: "order-fulfilment-type"},
+}
+
+// GetOrderHistory retrieves past orders from the Asda account orders page.
+func (d *Datasource) GetOrderHistory(
+	ctx context.Context, page int,
+) (*datasource.OrderHistoryResult, error) {
+	url := ordersURL
+	if page > 1 {
+		url = ordersURL + "?page=" + strconv.Itoa(page)
+	}
+	body, err := d.browser.Fetch(ctx, url, d.cookies, ordersWaitSelector)
+	if err != nil {
+		return nil, fmt.Errorf("asda orders fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+
+	result, err := ParseOrderHistory(body)
+	if err != nil {
+		return nil, err
+	}
+	result.Page = max(page, 1)
+	return result, nil
+}
+
+// ParseOrderHistory parses an Asda account orders page. The page doesn't
+// report a total, so Total is nil, and its order cards list no item lines,
+// so each order's Items is empty.
+func ParseOrderHistory(r io.Reader) (*datasource.OrderHistoryResult, error) {
+	orders, err := scraper.ParseOrderCards(r, orderSelectors, datasource.Asda)
+	if err != nil {
+		return nil, err
+	}
+	return &datasource.OrderHistoryResult{
+		Supermarket: datasource.Asda,
+		Orders:      orders,
+		Page:        1,
+		PageSize:    len(orders),
+	}, nil
+}
@@ -0,0 +1,132 @@
+// Package coop provides a datasource for Co-op, scraping the server-rendered
+// pages of its online shop.
+package coop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/html"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+const baseURL = "https://shop.coop.co.uk"
+
+var selectors = scraper.Config{
+	ID:          datasource.Coop,
+	BaseURL:     baseURL,
+	Container:   scraper.ElemSel{Tag: "article", Att: "data-testid", Val: "product-card"},
+	CategorySel: scraper.ElemSel{Tag: "a", Att: "data-testid", Val: "category-link"},
+	SearchSel: scraper.ProductSelectors{
+		Title:       scraper.ElemSel{Tag: "a", Att: "data-testid", Val: "product-card-title"},
+		Price:       scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "product-card-price"},
+		Unit:        scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "product-card-unit-price"},
+		Promo:       scraper.ElemSel{Tag: "div", Att: "data-testid", Val: "product-card-offer"},
+		Image:       scraper.ElemSel{Tag: "img", Att: "data-testid", Val: "product-card-image"},
+		Unavailable: scraper.ElemSel{Tag: "p", Att: "data-testid", Val: "product-card-stock"},
+	},
+	ProductSel: scraper.ProductSelectors{
+		Title:       scraper.ElemSel{Tag: "h1", Att: "data-testid", Val: "product-title"},
+		Price:       scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "product-price"},
+		Unit:        scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "product-unit-price"},
+		Promo:       scraper.ElemSel{Tag: "div", Att: "data-testid", Val: "product-offer"},
+		Image:       scraper.ElemSel{Tag: "img", Att: "data-testid", Val: "product-image"},
+		Description: scraper.ElemSel{Tag: "div", Att: "data-testid", Val: "product-description"},
+		Ingredients: scraper.ElemSel{Tag: "div", Att: "data-testid", Val: "product-ingredients"},
+	},
+}
+
+var nutritionTableSel = scraper.ElemSel{Tag: "table", Att: "data-testid", Val: "nutrition-table"}
+
+// Config holds optional overrides for a Co-op datasource.
+// Zero values use the built-in defaults.
+type Config struct {
+	BaseURL string
+}
+
+// Datasource scrapes the Co-op online shop. Browsing and search do not
+// need a login, so it is a plain ProductSource.
+type Datasource struct {
+	httpClient *http.Client
+	cfg        scraper.Config
+}
+
+// NewDatasource creates a new Co-op datasource.
+func NewDatasource(cfg Config, httpClient *http.Client) *Datasource {
+	resolved := selectors
+	if cfg.BaseURL != "" {
+		resolved.BaseURL = cfg.BaseURL
+	}
+	return &Datasource{httpClient: httpClient, cfg: resolved}
+}
+
+func (d *Datasource) ID() datasource.SupermarketID { return datasource.Coop }
+func (d *Datasource) Name() string                 { return "Co-op" }
+func (d *Datasource) Description() string          { return "Convenience and community supermarket chain" }
+
+func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+	searchURL := d.cfg.BaseURL + "/search?" + url.Values{"term": {query}}.Encode()
+	body, err := scraper.FetchHTML(ctx, searchURL, nil, d.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("coop search fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return scraper.ParseSearchResults(body, d.cfg)
+}
+
+// ParseSearchResults parses a Co-op search results page.
+func ParseSearchResults(r io.Reader) ([]datasource.Product, error) {
+	return scraper.ParseSearchResults(r, selectors)
+}
+
+func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*datasource.Product, error) {
+	productURL := d.cfg.BaseURL + "/product/" + url.PathEscape(productID)
+	body, err := scraper.FetchHTML(ctx, productURL, nil, d.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("coop product fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+
+	p, err := ParseProductPage(body)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = productID
+	p.URL = productURL
+	return p, nil
+}
+
+// ParseProductPage parses a Co-op product page.
+// The returned Product does not have ID or URL set.
+func ParseProductPage(r io.Reader) (*datasource.Product, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("coop: parse product HTML: %w", err)
+	}
+
+	p := scraper.ParseProductFields(doc, selectors.ProductSel, datasource.Coop)
+	if p.Name == "" {
+		return nil, fmt.Errorf("coop: no product found in HTML")
+	}
+	p.Nutrition = scraper.ParseNutritionTable(scraper.FindNutritionTable(doc, nutritionTableSel))
+	return p, nil
+}
+
+func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Category, error) {
+	body, err := scraper.FetchHTML(ctx, d.cfg.BaseURL+"/categories", nil, d.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("coop categories fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return scraper.ParseCategories(body, d.cfg)
+}
+
+// ParseCategories parses the Co-op categories page.
+func ParseCategories(r io.Reader) ([]datasource.Category, error) {
+	return scraper.ParseCategories(r, selectors)
+}
@@ -0,0 +1,56 @@
+package coop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/coop"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseSearchResults(t *testing.T) {
+	products := testutil.ParseSearchFile(t, "testdata/coop_search.html", coop.ParseSearchResults)
+	require.Len(t, products, 2)
+
+	p := products[0]
+	assert.Equal(t, "Co-op British Semi Skimmed Milk 4 Pints 2.272L", p.Name)
+	assert.Equal(t, "co-op-british-semi-skimmed-milk-4-pints-5000128104517", p.ID)
+	assert.Equal(t, datasource.Coop, p.Supermarket)
+	assert.InDelta(t, 1.65, p.Price, 0.001)
+	assert.True(t, *p.Available)
+	assert.Equal(t, "Member Price £1.45", p.Promotion)
+	require.Len(t, p.Offers, 1)
+	assert.Equal(t, "Co-op Membership", p.Offers[0].Loyalty)
+
+	assert.False(t, *products[1].Available)
+}
+
+func TestParseProductPage(t *testing.T) {
+	p := testutil.ParseProductFile(t, "testdata/coop_product.html", coop.ParseProductPage)
+	assert.Equal(t, "Co-op British Semi Skimmed Milk 4 Pints 2.272L", p.Name)
+	assert.InDelta(t, 1.65, p.Price, 0.001)
+	assert.Equal(t, "Milk", p.Ingredients)
+	assert.Contains(t, p.Description, "British milk")
+	require.NotNil(t, p.Nutrition)
+	assert.Equal(t, "4.8g", p.Nutrition.Per100g["Sugars"])
+}
+
+func TestGetProductDetails(t *testing.T) {
+	srv := testutil.HTMLFixtureServer(t, "testdata/coop_product.html")
+	ds := coop.NewDatasource(coop.Config{BaseURL: srv.URL}, srv.Client())
+
+	p, err := ds.GetProductDetails(t.Context(), "co-op-british-semi-skimmed-milk-4-pints-5000128104517")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/product/co-op-british-semi-skimmed-milk-4-pints-5000128104517", p.URL)
+}
+
+func TestParseCategories(t *testing.T) {
+	categories := testutil.ParseCategoryFile(t, "testdata/coop_categories.html", coop.ParseCategories)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "Fresh food", categories[0].Name)
+	assert.Equal(t, "fresh-food", categories[0].ID)
+	assert.Equal(t, "https://shop.coop.co.uk/category/fresh-food", categories[0].URL)
+}
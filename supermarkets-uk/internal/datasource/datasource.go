@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 )
 
 // SupermarketID identifies a supermarket.
@@ -29,10 +30,21 @@ const (
 	TukTukMart SupermarketID = "tuktukmart"
 	// Morueats is the Morueats Asian grocery store.
 	Morueats SupermarketID = "morueats"
+	// Lidl is the Lidl discount supermarket.
+	Lidl SupermarketID = "lidl"
+	// Aldi is the Aldi discount supermarket.
+	Aldi SupermarketID = "aldi"
+	// Iceland is the Iceland frozen food supermarket.
+	Iceland SupermarketID = "iceland"
+	// Coop is the Co-op supermarket.
+	Coop SupermarketID = "coop"
 )
 
 // AllSupermarkets is the list of all supported supermarket IDs.
-var AllSupermarkets = []SupermarketID{Tesco, Sainsburys, Ocado, Morrisons, Asda, Waitrose, Hiyou, TukTukMart, Morueats}
+var AllSupermarkets = []SupermarketID{
+	Tesco, Sainsburys, Ocado, Morrisons, Asda, Waitrose, Hiyou, TukTukMart, Morueats,
+	Lidl, Aldi, Iceland, Coop,
+}
 
 // NutritionInfo holds nutritional information for a product.
 type NutritionInfo struct {
@@ -58,6 +70,16 @@ type Product struct {
 	Ingredients  string         `json:"ingredients,omitempty"`
 	Nutrition    *NutritionInfo `json:"nutrition,omitempty"`
 	DietaryInfo  []string       `json:"dietaryInfo,omitempty"`
+	// Brand and GTIN (the barcode number) are set when the store provides
+	// them; the matching package uses them to find the same product at
+	// other stores.
+	Brand string `json:"brand,omitempty"`
+	GTIN  string `json:"gtin,omitempty"`
+	// Quantity, UnitPrice, and Offers are normalised from the free-form
+	// fields above by Normalise so products can be compared across stores.
+	Quantity  *Quantity  `json:"quantity,omitempty"`
+	UnitPrice *UnitPrice `json:"unitPrice,omitempty"`
+	Offers    []Offer    `json:"offers,omitempty"`
 }
 
 // Category represents a product category in a supermarket.
@@ -97,6 +119,19 @@ type BasketSource interface {
 	UpdateBasketItem(ctx context.Context, productID string, quantity int) (*Basket, error)
 }
 
+// SlotSource provides access to a supermarket's delivery and
+// click-and-collect slots.
+type SlotSource interface {
+	// GetSlots returns the slots starting between from and to, including
+	// those that cannot be booked.
+	GetSlots(ctx context.Context, from, to time.Time) ([]Slot, error)
+}
+
+// SlotBooker books a supermarket's delivery and click-and-collect slots.
+type SlotBooker interface {
+	BookSlot(ctx context.Context, slotID string) (*Slot, error)
+}
+
 // AuthProductSource is a ProductSource that supports session cookie injection
 // and session validation.
 type AuthProductSource interface {
@@ -0,0 +1,154 @@
+// Package iceland provides a datasource for Iceland, scraping its
+// server-rendered search, product, and category pages.
+package iceland
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+const baseURL = "https://www.iceland.co.uk"
+
+var selectors = scraper.Config{
+	ID:          datasource.Iceland,
+	BaseURL:     baseURL,
+	Container:   scraper.ElemSel{Tag: "div", Cls: "product-tile", Att: "data-pid"},
+	CategorySel: scraper.ElemSel{Tag: "a", Cls: "menu-category-link"},
+	SearchSel: scraper.ProductSelectors{
+		Title:       scraper.ElemSel{Tag: "a", Cls: "name-link"},
+		Price:       scraper.ElemSel{Tag: "span", Cls: "product-sales-price"},
+		Unit:        scraper.ElemSel{Tag: "span", Cls: "product-price-per-unit"},
+		Promo:       scraper.ElemSel{Tag: "div", Cls: "promotion-callout"},
+		Image:       scraper.ElemSel{Tag: "img", Cls: "tile-image"},
+		Unavailable: scraper.ElemSel{Tag: "div", Cls: "product-availability-msg"},
+	},
+	ProductSel: scraper.ProductSelectors{
+		Title:       scraper.ElemSel{Tag: "h1", Cls: "product-name"},
+		Price:       scraper.ElemSel{Tag: "span", Cls: "price-sales"},
+		Unit:        scraper.ElemSel{Tag: "span", Cls: "price-per-unit"},
+		Promo:       scraper.ElemSel{Tag: "div", Cls: "promotion-callout"},
+		Image:       scraper.ElemSel{Tag: "img", Cls: "primary-image"},
+		Description: scraper.ElemSel{Tag: "div", Cls: "product-short-description"},
+	},
+}
+
+var (
+	nutritionTableSel = scraper.ElemSel{Tag: "table", Cls: "nutrition-table"}
+	unavailableSel    = scraper.ElemSel{Tag: "div", Cls: "not-available-msg"}
+)
+
+// Config holds optional overrides for an Iceland datasource.
+// Zero values use the built-in defaults.
+type Config struct {
+	BaseURL string
+}
+
+// Datasource scrapes the Iceland website. Iceland pages are server
+// rendered, so no browser is needed.
+type Datasource struct {
+	httpClient *http.Client
+	cfg        scraper.Config
+}
+
+// NewDatasource creates a new Iceland datasource.
+func NewDatasource(cfg Config, httpClient *http.Client) *Datasource {
+	resolved := selectors
+	if cfg.BaseURL != "" {
+		resolved.BaseURL = cfg.BaseURL
+	}
+	return &Datasource{httpClient: httpClient, cfg: resolved}
+}
+
+func (d *Datasource) ID() datasource.SupermarketID { return datasource.Iceland }
+func (d *Datasource) Name() string                 { return "Iceland" }
+func (d *Datasource) Description() string          { return "Frozen food specialist supermarket chain" }
+
+func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+	searchURL := d.cfg.BaseURL + "/search?" + url.Values{"q": {query}}.Encode()
+	body, err := scraper.FetchHTML(ctx, searchURL, nil, d.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("iceland search fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return parseSearchResults(body, d.cfg)
+}
+
+// ParseSearchResults parses an Iceland search results page.
+func ParseSearchResults(r io.Reader) ([]datasource.Product, error) {
+	return parseSearchResults(r, selectors)
+}
+
+// parseSearchResults parses search results, trimming the ".html" suffix
+// product links end in from product IDs.
+func parseSearchResults(r io.Reader, cfg scraper.Config) ([]datasource.Product, error) {
+	products, err := scraper.ParseSearchResults(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		products[i].ID = strings.TrimSuffix(products[i].ID, ".html")
+	}
+	return products, nil
+}
+
+// GetProductDetails fetches a product page. Iceland serves products at
+// /<id>.html without the name slug.
+func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*datasource.Product, error) {
+	productURL := d.cfg.BaseURL + "/" + url.PathEscape(productID) + ".html"
+	body, err := scraper.FetchHTML(ctx, productURL, nil, d.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("iceland product fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+
+	p, err := ParseProductPage(body)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = productID
+	p.URL = productURL
+	return p, nil
+}
+
+// ParseProductPage parses an Iceland product page.
+// The returned Product does not have ID or URL set.
+func ParseProductPage(r io.Reader) (*datasource.Product, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("iceland: parse product HTML: %w", err)
+	}
+
+	p := scraper.ParseProductFields(doc, selectors.ProductSel, datasource.Iceland)
+	if p.Name == "" {
+		return nil, fmt.Errorf("iceland: no product found in HTML")
+	}
+	p.Ingredients = scraper.SectionContent(doc, "h3", "Ingredients")
+	p.Nutrition = scraper.ParseNutritionTable(scraper.FindNutritionTable(doc, nutritionTableSel))
+	if scraper.FindElement(doc, unavailableSel) != nil {
+		p.Available = datasource.BoolPtr(false)
+	}
+	return p, nil
+}
+
+func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Category, error) {
+	body, err := scraper.FetchHTML(ctx, d.cfg.BaseURL+"/", nil, d.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("iceland categories fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return scraper.ParseCategories(body, d.cfg)
+}
+
+// ParseCategories parses the Iceland home page navigation into categories.
+func ParseCategories(r io.Reader) ([]datasource.Category, error) {
+	return scraper.ParseCategories(r, selectors)
+}
@@ -0,0 +1,61 @@
+package iceland_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/iceland"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseSearchResults(t *testing.T) {
+	products := testutil.ParseSearchFile(t, "testdata/iceland_search.html", iceland.ParseSearchResults)
+	require.Len(t, products, 2)
+
+	p := products[0]
+	assert.Equal(t, "Iceland British Semi Skimmed Milk 4 Pints 2272ml", p.Name)
+	assert.Equal(t, "57384", p.ID)
+	assert.Equal(t, datasource.Iceland, p.Supermarket)
+	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.Equal(t, "https://www.iceland.co.uk/p/iceland-british-semi-skimmed-milk-4-pints-2272ml/57384.html", p.URL)
+	assert.True(t, *p.Available)
+	assert.Equal(t, "Bonus Card Price £1.35", p.Promotion)
+	require.Len(t, p.Offers, 1)
+	assert.Equal(t, "Iceland Bonus Card", p.Offers[0].Loyalty)
+
+	p2 := products[1]
+	assert.Equal(t, "68231", p2.ID)
+	assert.False(t, *p2.Available)
+}
+
+func TestParseProductPage(t *testing.T) {
+	p := testutil.ParseProductFile(t, "testdata/iceland_product.html", iceland.ParseProductPage)
+	assert.Equal(t, "Iceland British Semi Skimmed Milk 4 Pints 2272ml", p.Name)
+	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.Equal(t, "Milk", p.Ingredients)
+	assert.Contains(t, p.Description, "British farms")
+	require.NotNil(t, p.Nutrition)
+	assert.Equal(t, "1.8g", p.Nutrition.Per100g["Fat"])
+	assert.Equal(t, "0.22g", p.Nutrition.PerPortion["Salt"])
+}
+
+func TestGetProductDetails(t *testing.T) {
+	srv := testutil.HTMLFixtureServer(t, "testdata/iceland_product.html")
+	ds := iceland.NewDatasource(iceland.Config{BaseURL: srv.URL}, srv.Client())
+
+	p, err := ds.GetProductDetails(t.Context(), "57384")
+	require.NoError(t, err)
+	assert.Equal(t, "57384", p.ID)
+	assert.Equal(t, srv.URL+"/57384.html", p.URL)
+}
+
+func TestParseCategories(t *testing.T) {
+	categories := testutil.ParseCategoryFile(t, "testdata/iceland_categories.html", iceland.ParseCategories)
+	require.Len(t, categories, 3)
+	assert.Equal(t, "Frozen", categories[0].Name)
+	assert.Equal(t, "frozen", categories[0].ID)
+	assert.Equal(t, datasource.Iceland, categories[0].Supermarket)
+}
@@ -0,0 +1,261 @@
+// Package lidl provides a datasource for Lidl using the JSON search API
+// behind its website. Product lookups use the same grid box data the
+// search results are built from; categories are scraped from the
+// server-rendered home page navigation.
+package lidl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+const baseURL = "https://www.lidl.co.uk"
+
+// gridbox is a product as shown in a Lidl product grid.
+type gridbox struct {
+	ERPNumber    string `json:"erpNumber"`
+	FullTitle    string `json:"fullTitle"`
+	CanonicalURL string `json:"canonicalUrl"`
+	Image        string `json:"image"`
+	Brand        struct {
+		Name string `json:"name"`
+	} `json:"brand"`
+	Price struct {
+		Price     float64 `json:"price"`
+		OldPrice  float64 `json:"oldPrice"`
+		BasePrice struct {
+			Text string `json:"text"`
+		} `json:"basePrice"`
+		Packaging struct {
+			Text string `json:"text"`
+		} `json:"packaging"`
+	} `json:"price"`
+	LidlPlus []struct {
+		Price struct {
+			Price float64 `json:"price"`
+		} `json:"price"`
+	} `json:"lidlPlus"`
+	StockAvailability struct {
+		OnlineAvailable *bool `json:"onlineAvailable"`
+	} `json:"stockAvailability"`
+	Keyfacts struct {
+		Description string `json:"description"`
+	} `json:"keyfacts"`
+}
+
+type searchResponse struct {
+	Items []struct {
+		Gridbox struct {
+			Data gridbox `json:"data"`
+		} `json:"gridbox"`
+	} `json:"items"`
+}
+
+// Config holds optional overrides for a Lidl datasource.
+// Zero values use the built-in defaults.
+type Config struct {
+	BaseURL string
+}
+
+// Datasource uses the Lidl search and grid box APIs. Lidl has no grocery
+// login, so it is a plain ProductSource.
+type Datasource struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewDatasource creates a new Lidl datasource.
+func NewDatasource(cfg Config, httpClient *http.Client) *Datasource {
+	base := baseURL
+	if cfg.BaseURL != "" {
+		base = cfg.BaseURL
+	}
+	return &Datasource{httpClient: httpClient, baseURL: base}
+}
+
+func (d *Datasource) ID() datasource.SupermarketID { return datasource.Lidl }
+func (d *Datasource) Name() string                 { return "Lidl" }
+func (d *Datasource) Description() string          { return "Discount supermarket chain" }
+
+// SearchProducts searches for products using the Lidl search API.
+func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]datasource.Product, error) {
+	apiURL := d.baseURL + "/q/api/search?" + url.Values{
+		"q":          {query},
+		"locale":     {"en_GB"},
+		"assortment": {"GB"},
+		"version":    {"2.1.0"},
+		"fetchsize":  {"30"},
+	}.Encode()
+
+	body, err := d.request(ctx, apiURL, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("lidl search: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return ParseSearchResults(body)
+}
+
+// ParseSearchResults parses a Lidl search API response.
+func ParseSearchResults(r io.Reader) ([]datasource.Product, error) {
+	var resp searchResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("lidl: decode search response: %w", err)
+	}
+	products := make([]datasource.Product, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		if item.Gridbox.Data.FullTitle == "" {
+			continue
+		}
+		products = append(products, convertProduct(item.Gridbox.Data))
+	}
+	return products, nil
+}
+
+// GetProductDetails fetches a product's grid box data by ERP number.
+func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*datasource.Product, error) {
+	apiURL := d.baseURL + "/p/api/gridboxes/GB/en?" + url.Values{"erpNumbers": {productID}}.Encode()
+
+	body, err := d.request(ctx, apiURL, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("lidl product: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return ParseProduct(body)
+}
+
+// ParseProduct parses a Lidl grid box API response for a single product.
+func ParseProduct(r io.Reader) (*datasource.Product, error) {
+	var boxes []gridbox
+	if err := json.NewDecoder(r).Decode(&boxes); err != nil {
+		return nil, fmt.Errorf("lidl: decode product: %w", err)
+	}
+	if len(boxes) == 0 {
+		return nil, fmt.Errorf("lidl: product not found")
+	}
+	p := convertProduct(boxes[0])
+	return &p, nil
+}
+
+var categorySelectors = scraper.Config{
+	ID:          datasource.Lidl,
+	BaseURL:     baseURL,
+	CategorySel: scraper.ElemSel{Tag: "a", Cls: "n-navigation__link", Att: "data-ga-label"},
+}
+
+// BrowseCategories returns the categories in the home page navigation.
+func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Category, error) {
+	body, err := d.request(ctx, d.baseURL+"/", "text/html")
+	if err != nil {
+		return nil, fmt.Errorf("lidl categories: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return ParseCategories(body)
+}
+
+// ParseCategories parses the Lidl home page navigation into categories.
+func ParseCategories(r io.Reader) ([]datasource.Category, error) {
+	return scraper.ParseCategories(r, categorySelectors)
+}
+
+func (d *Datasource) request(ctx context.Context, targetURL, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	scraper.SetBrowserHeaders(req)
+	req.Header.Set("Accept", accept)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, targetURL)
+	}
+	return resp.Body, nil
+}
+
+// basePriceRe matches Lidl base prices such as "1 kg = £2.98" or
+// "100 g = 30p".
+var basePriceRe = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)\s*([a-zA-Z]+)\s*=\s*(.+?)\s*$`)
+
+// unitPriceText rewrites a Lidl base price into the "£2.98/1kg" form
+// Normalise understands.
+func unitPriceText(s string) string {
+	m := basePriceRe.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+	return m[3] + "/" + m[1] + m[2]
+}
+
+func convertProduct(g gridbox) datasource.Product {
+	p := datasource.Product{
+		ID:           g.ERPNumber,
+		Supermarket:  datasource.Lidl,
+		Name:         g.FullTitle,
+		Price:        g.Price.Price,
+		PricePerUnit: unitPriceText(g.Price.BasePrice.Text),
+		Currency:     "GBP",
+		ImageURL:     g.Image,
+		Weight:       g.Price.Packaging.Text,
+		Brand:        g.Brand.Name,
+		Description:  stripHTML(g.Keyfacts.Description),
+	}
+	if g.StockAvailability.OnlineAvailable != nil {
+		p.Available = datasource.BoolPtr(*g.StockAvailability.OnlineAvailable)
+	}
+	if g.CanonicalURL != "" {
+		p.URL = scraper.ResolveURL(baseURL, g.CanonicalURL)
+	}
+
+	var promos []string
+	if g.Price.OldPrice > g.Price.Price {
+		promos = append(promos, fmt.Sprintf("Was £%.2f Now £%.2f", g.Price.OldPrice, g.Price.Price))
+	}
+	for _, lp := range g.LidlPlus {
+		if lp.Price.Price > 0 {
+			promos = append(promos, "Lidl Plus Price £"+strconv.FormatFloat(lp.Price.Price, 'f', 2, 64))
+		}
+	}
+	p.Promotion = strings.Join(promos, "; ")
+	p.Normalise()
+	return p
+}
+
+// stripHTML parses an HTML fragment and returns its text content, with
+// list items on separate lines.
+func stripHTML(s string) string {
+	if s == "" {
+		return ""
+	}
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	var lines []string
+	scraper.WalkTree(doc, func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "li" || n.Data == "p") {
+			if text := scraper.TextContent(n); text != "" {
+				lines = append(lines, text)
+			}
+		}
+	})
+	if len(lines) == 0 {
+		return scraper.TextContent(doc)
+	}
+	return strings.Join(lines, "\n")
+}
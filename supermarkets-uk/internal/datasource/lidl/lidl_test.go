@@ -0,0 +1,61 @@
+package lidl_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/lidl"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseSearchResults(t *testing.T) {
+	products := testutil.ParseSearchFile(t, "testdata/lidl_search.json", lidl.ParseSearchResults)
+	// The recipe result has no product data and is skipped.
+	require.Len(t, products, 2)
+
+	p := products[0]
+	assert.Equal(t, "Milbona British Semi Skimmed Milk", p.Name)
+	assert.Equal(t, "10012345", p.ID)
+	assert.Equal(t, datasource.Lidl, p.Supermarket)
+	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.Equal(t, "Milbona", p.Brand)
+	assert.Equal(t, "https://www.lidl.co.uk/p/milbona-british-semi-skimmed-milk/p10012345", p.URL)
+	assert.True(t, *p.Available)
+	assert.Equal(t, "Lidl Plus Price £1.25", p.Promotion)
+	require.Len(t, p.Offers, 1)
+	assert.Equal(t, "Lidl Plus", p.Offers[0].Loyalty)
+	require.NotNil(t, p.Quantity)
+	assert.InDelta(t, 2272, p.Quantity.Total(), 0.001)
+	require.NotNil(t, p.UnitPrice)
+	assert.InDelta(t, 0.64, p.UnitPrice.Price, 0.001)
+	assert.Equal(t, "British milk\nSuitable for vegetarians", p.Description)
+
+	p2 := products[1]
+	assert.False(t, *p2.Available)
+	assert.Equal(t, "Was £2.49 Now £1.99", p2.Promotion)
+	require.NotNil(t, p2.UnitPrice)
+	assert.InDelta(t, 13.3, p2.UnitPrice.Price, 0.001, "per kg from 100 g = £1.33")
+}
+
+func TestGetProductDetails(t *testing.T) {
+	srv := testutil.JSONFixtureServer(t, "testdata/lidl_product.json")
+	ds := lidl.NewDatasource(lidl.Config{BaseURL: srv.URL}, srv.Client())
+
+	p, err := ds.GetProductDetails(t.Context(), "10012345")
+	require.NoError(t, err)
+	assert.Equal(t, "Milbona British Semi Skimmed Milk", p.Name)
+	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.NotEmpty(t, p.Description)
+}
+
+func TestParseCategories(t *testing.T) {
+	categories := testutil.ParseCategoryFile(t, "testdata/lidl_categories.html", lidl.ParseCategories)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "Food & Drink", categories[0].Name)
+	assert.Equal(t, "s10068374", categories[0].ID)
+	assert.Equal(t, "https://www.lidl.co.uk/c/food-drink/s10068374", categories[0].URL)
+	assert.Equal(t, datasource.Lidl, categories[0].Supermarket)
+}
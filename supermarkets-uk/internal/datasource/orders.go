@@ -0,0 +1,33 @@
+package datasource
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OrderDateLayout is the layout of Order.Date.
+const OrderDateLayout = "2006-01-02"
+
+// FormatDeliverySlot formats a delivery slot as e.g. "Sat 7 Mar, 9:00am–10:00am".
+func FormatDeliverySlot(start, end time.Time) string {
+	return fmt.Sprintf("%s, %s–%s",
+		start.Format("Mon 2 Jan"), start.Format("3:04pm"), end.Format("3:04pm"))
+}
+
+// MergeOrders combines the orders from several supermarkets' results,
+// newest first. Orders without a date sort last.
+func MergeOrders(results []OrderHistoryResult) []Order {
+	orders := []Order{}
+	for _, r := range results {
+		orders = append(orders, r.Orders...)
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		a, b := orders[i].Date, orders[j].Date
+		if a == "" || b == "" {
+			return b == "" && a != ""
+		}
+		return a > b
+	})
+	return orders
+}
@@ -0,0 +1,39 @@
+package datasource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+func TestMergeOrders(t *testing.T) {
+	results := []datasource.OrderHistoryResult{
+		{Supermarket: datasource.Tesco, Orders: []datasource.Order{
+			{ID: "t1", Date: "2026-03-01"},
+			{ID: "t2", Date: "2026-02-01"},
+		}},
+		{Supermarket: datasource.Asda, Error: "session expired"},
+		{Supermarket: datasource.Ocado, Orders: []datasource.Order{
+			{ID: "o1"},
+			{ID: "o2", Date: "2026-02-15"},
+		}},
+	}
+
+	orders := datasource.MergeOrders(results)
+	require.Len(t, orders, 4)
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+	assert.Equal(t, []string{"t1", "o2", "t2", "o1"}, ids)
+}
+
+func TestFormatDeliverySlot(t *testing.T) {
+	start := time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)
+	slot := datasource.FormatDeliverySlot(start, start.Add(time.Hour))
+	assert.Equal(t, "Sat 7 Mar, 9:00am–10:00am", slot)
+}
@@ -0,0 +1,123 @@
+package osp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// activeCartPath is the OSP cart API used by the web app for the logged-in
+// customer's current trolley.
+const activeCartPath = "/api/cart/v1/carts/active"
+
+// money is an OSP API amount; amounts are decimal strings.
+type money struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m money) value() float64 {
+	v, _ := strconv.ParseFloat(m.Amount, 64)
+	return v
+}
+
+type cartResponse struct {
+	CartID string `json:"cartId"`
+	Totals struct {
+		ItemCount            int   `json:"itemCount"`
+		ItemPriceAfterPromos money `json:"itemPriceAfterPromos"`
+	} `json:"totals"`
+	Items []cartItem `json:"items"`
+}
+
+type cartItem struct {
+	ProductID  string `json:"productId"`
+	Name       string `json:"name"`
+	ImageURL   string `json:"imageUrl"`
+	Quantity   int    `json:"quantity"`
+	Price      money  `json:"price"`
+	TotalPrice money  `json:"totalPrice"`
+	Promotions []struct {
+		Description string `json:"description"`
+	} `json:"promotions"`
+}
+
+// cartQuantity sets the absolute quantity of a product; zero removes it.
+type cartQuantity struct {
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// GetBasket retrieves the current trolley.
+func (d *ospDatasource) GetBasket(ctx context.Context) (*datasource.Basket, error) {
+	body, err := d.sessionRequest(ctx, http.MethodGet, activeCartPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s basket fetch: %w", d.cfg.id, err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return parseCart(body, d.cfg.id)
+}
+
+// UpdateBasketItem adds, updates, or removes (quantity=0) a product in the
+// trolley.
+func (d *ospDatasource) UpdateBasketItem(
+	ctx context.Context, productID string, quantity int,
+) (*datasource.Basket, error) {
+	payload := struct {
+		Items []cartQuantity `json:"items"`
+	}{[]cartQuantity{{ProductID: productID, Quantity: quantity}}}
+
+	body, err := d.sessionRequest(ctx, http.MethodPost, activeCartPath+"/apply-quantities", payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s basket update: %w", d.cfg.id, err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return parseCart(body, d.cfg.id)
+}
+
+// parseCart parses an OSP cart API response into a basket.
+func parseCart(r io.Reader, id datasource.SupermarketID) (*datasource.Basket, error) {
+	var cart cartResponse
+	if err := json.NewDecoder(r).Decode(&cart); err != nil {
+		return nil, fmt.Errorf("%s: decode cart: %w", id, err)
+	}
+
+	basket := &datasource.Basket{
+		Supermarket: id,
+		TotalPrice:  cart.Totals.ItemPriceAfterPromos.value(),
+		TotalItems:  cart.Totals.ItemCount,
+		Currency:    "GBP",
+	}
+	if c := cart.Totals.ItemPriceAfterPromos.Currency; c != "" {
+		basket.Currency = c
+	}
+
+	countItems := basket.TotalItems == 0
+	for _, item := range cart.Items {
+		promos := make([]string, 0, len(item.Promotions))
+		for _, p := range item.Promotions {
+			if p.Description != "" {
+				promos = append(promos, p.Description)
+			}
+		}
+		basket.Items = append(basket.Items, datasource.BasketItem{
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Cost:      item.TotalPrice.value(),
+			Price:     item.Price.value(),
+			ImageURL:  item.ImageURL,
+			Promotion: strings.Join(promos, "; "),
+		})
+		if countItems {
+			basket.TotalItems += item.Quantity
+		}
+	}
+	return basket, nil
+}
@@ -1,11 +1,16 @@
 package osp_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/osp"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
 )
@@ -69,3 +74,58 @@ func TestMorrisonsBrowseCategories(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, categories, 3)
 }
+
+func TestOcadoUpdateBasketItem(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/ocado_basket.json")
+	require.NoError(t, err)
+
+	var gotMethod, gotPath, gotCookie string
+	var gotBody struct {
+		Items []struct {
+			ProductID string `json:"productId"`
+			Quantity  int    `json:"quantity"`
+		} `json:"items"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotCookie = r.Method, r.URL.Path, r.Header.Get("Cookie")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	ds := osp.NewOcado(osp.Config{BaseURL: srv.URL}, srv.Client())
+	ds.SetCookies([]*http.Cookie{{Name: "session", Value: "abc"}})
+	bs, ok := ds.(datasource.BasketSource)
+	require.True(t, ok)
+
+	basket, err := bs.UpdateBasketItem(t.Context(), "53316011", 0)
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "/api/cart/v1/carts/active/apply-quantities", gotPath)
+	assert.Equal(t, "session=abc", gotCookie)
+	require.Len(t, gotBody.Items, 1)
+	assert.Equal(t, "53316011", gotBody.Items[0].ProductID)
+	assert.Equal(t, 0, gotBody.Items[0].Quantity)
+	assert.Len(t, basket.Items, 2)
+}
+
+func TestOcadoBasketSession(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	ds := osp.NewOcado(osp.Config{BaseURL: srv.URL}, srv.Client())
+	bs, ok := ds.(datasource.BasketSource)
+	require.True(t, ok)
+
+	// Without a session the cart is not requested at all.
+	_, err := bs.GetBasket(t.Context())
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, datasource.ErrSessionExpired)
+
+	ds.SetCookies([]*http.Cookie{{Name: "session", Value: "stale"}})
+	_, err = bs.GetBasket(t.Context())
+	require.ErrorIs(t, err, datasource.ErrSessionExpired)
+}
@@ -69,3 +69,18 @@ func parseMorrisonsProductPage(r io.Reader) (*datasource.Product, error) {
 func ParseMorrisonsCategories(r io.Reader) ([]datasource.Category, error) {
 	return scraper.ParseCategories(r, morrisonsCfg.selectors)
 }
+
+// ParseMorrisonsBasket parses a Morrisons cart API response.
+func ParseMorrisonsBasket(r io.Reader) (*datasource.Basket, error) {
+	return parseCart(r, datasource.Morrisons)
+}
+
+// ParseMorrisonsOrderHistory parses a Morrisons order API response.
+func ParseMorrisonsOrderHistory(r io.Reader) (*datasource.OrderHistoryResult, error) {
+	return parseOrders(r, datasource.Morrisons)
+}
+
+// ParseMorrisonsSlots parses a Morrisons slot API response.
+func ParseMorrisonsSlots(r io.Reader) ([]datasource.Slot, error) {
+	return parseSlots(r, datasource.Morrisons)
+}
@@ -20,6 +20,8 @@ func TestParseMorrisonsSearchResults(t *testing.T) {
 	assert.Equal(t, "Mighty Slice Caramelised Biscuit High Protein Cheesecake 115g", p.Name)
 	assert.InDelta(t, 2.80, p.Price, 0.001)
 	assert.Equal(t, "115g", p.Weight)
+	require.NotNil(t, p.Quantity)
+	assert.Equal(t, datasource.Quantity{Amount: 115, Unit: datasource.Grams, Packs: 1}, *p.Quantity)
 	assert.Equal(t, datasource.Morrisons, p.Supermarket)
 }
 
@@ -42,3 +44,42 @@ func TestParseMorrisonsCategories(t *testing.T) {
 	assert.Equal(t, "Fruit, Veg & Flowers", categories[0].Name)
 	assert.Equal(t, datasource.Morrisons, categories[0].Supermarket)
 }
+
+func TestParseMorrisonsBasket(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/morrisons_basket.json")
+	basket, err := osp.ParseMorrisonsBasket(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Morrisons, basket.Supermarket)
+	assert.InDelta(t, 2.50, basket.TotalPrice, 0.001)
+	assert.Equal(t, 1, basket.TotalItems)
+	require.Len(t, basket.Items, 1)
+	assert.Equal(t, "12345", basket.Items[0].ProductID)
+	assert.Equal(t, "More Card Price £2.50", basket.Items[0].Promotion)
+}
+
+func TestParseMorrisonsOrderHistory(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/morrisons_orders.json")
+	result, err := osp.ParseMorrisonsOrderHistory(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Morrisons, result.Supermarket)
+	require.Len(t, result.Orders, 1)
+	assert.Equal(t, "7200098765", result.Orders[0].ID)
+	assert.Equal(t, "2026-03-03", result.Orders[0].Date)
+	assert.InDelta(t, 47.15, result.Orders[0].TotalPrice, 0.001)
+	require.Len(t, result.Orders[0].Items, 1)
+}
+
+func TestParseMorrisonsSlots(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/morrisons_slots.json")
+	slots, err := osp.ParseMorrisonsSlots(f)
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+
+	assert.Equal(t, datasource.Morrisons, slots[0].Supermarket)
+	assert.Equal(t, datasource.SlotCollection, slots[0].Method)
+	assert.Zero(t, slots[0].Price)
+	assert.Equal(t, datasource.SlotDelivery, slots[1].Method)
+	assert.InDelta(t, 4.50, slots[1].Price, 0.001)
+}
@@ -2,7 +2,9 @@
 package osp
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -92,6 +94,54 @@ func (d *ospDatasource) BrowseCategories(ctx context.Context) ([]datasource.Cate
 	return scraper.ParseCategories(body, d.cfg.selectors)
 }
 
+// sessionRequest sends an API request for the logged-in account, such as
+// cart or order requests. Requests without a session are refused and
+// 401/403 responses report an expired session.
+func (d *ospDatasource) sessionRequest(
+	ctx context.Context, method, path string, payload any,
+) (io.ReadCloser, error) {
+	if len(d.cookies) == 0 {
+		return nil, fmt.Errorf("requires a logged-in session")
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	apiURL := d.cfg.baseURL + path
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	scraper.SetBrowserHeaders(req)
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, c := range d.cookies {
+		req.AddCookie(c)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: HTTP %d from %s", datasource.ErrSessionExpired, resp.StatusCode, apiURL)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, apiURL)
+	}
+	return resp.Body, nil
+}
+
 // parseOSPProductPage parses an OSP product detail page.
 // OSP pages use h2 headings to label sections (e.g. "Product Information",
 // "Ingredients"), so description and ingredients are extracted by heading text
@@ -184,3 +234,18 @@ func parseOcadoProductPage(r io.Reader) (*datasource.Product, error) {
 func ParseOcadoCategories(r io.Reader) ([]datasource.Category, error) {
 	return scraper.ParseCategories(r, ocadoCfg.selectors)
 }
+
+// ParseOcadoBasket parses an Ocado cart API response.
+func ParseOcadoBasket(r io.Reader) (*datasource.Basket, error) {
+	return parseCart(r, datasource.Ocado)
+}
+
+// ParseOcadoOrderHistory parses an Ocado order API response.
+func ParseOcadoOrderHistory(r io.Reader) (*datasource.OrderHistoryResult, error) {
+	return parseOrders(r, datasource.Ocado)
+}
+
+// ParseOcadoSlots parses an Ocado slot API response.
+func ParseOcadoSlots(r io.Reader) ([]datasource.Slot, error) {
+	return parseSlots(r, datasource.Ocado)
+}
@@ -30,3 +30,66 @@ func TestParseOcadoCategories(t *testing.T) {
 	assert.Equal(t, "Fresh & Chilled Food", categories[0].Name)
 	assert.Equal(t, datasource.Ocado, categories[0].Supermarket)
 }
+
+func TestParseOcadoBasket(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/ocado_basket.json")
+	basket, err := osp.ParseOcadoBasket(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Ocado, basket.Supermarket)
+	assert.Equal(t, "GBP", basket.Currency)
+	assert.InDelta(t, 6.15, basket.TotalPrice, 0.001)
+	assert.Equal(t, 3, basket.TotalItems)
+	require.Len(t, basket.Items, 2)
+
+	i1 := basket.Items[0]
+	assert.Equal(t, "24577011", i1.ProductID)
+	assert.Equal(t, "Cravendale Filtered Fresh Whole Milk Fresher for Longer", i1.Name)
+	assert.Equal(t, 2, i1.Quantity)
+	assert.InDelta(t, 4.50, i1.Cost, 0.001)
+	assert.InDelta(t, 2.70, i1.Price, 0.001)
+	assert.Equal(t, "2 for £4.50", i1.Promotion)
+	assert.Empty(t, basket.Items[1].Promotion)
+}
+
+func TestParseOcadoOrderHistory(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/ocado_orders.json")
+	result, err := osp.ParseOcadoOrderHistory(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Ocado, result.Supermarket)
+	require.NotNil(t, result.Total)
+	assert.Equal(t, 2, *result.Total)
+	require.Len(t, result.Orders, 2)
+
+	o := result.Orders[0]
+	assert.Equal(t, "40012345678", o.ID)
+	assert.Equal(t, datasource.Ocado, o.Supermarket)
+	assert.Equal(t, "2026-03-05", o.Date)
+	assert.Equal(t, "Thu 5 Mar, 6:00pm–7:00pm", o.DeliverySlot)
+	assert.InDelta(t, 82.40, o.TotalPrice, 0.001)
+	assert.Equal(t, "GBP", o.Currency)
+	require.Len(t, o.Items, 2)
+	assert.Equal(t, "24577011", o.Items[0].ProductID)
+	assert.Equal(t, 2, o.Items[0].Quantity)
+
+	assert.Equal(t, "CANCELLED", result.Orders[1].Status)
+}
+
+func TestParseOcadoSlots(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/ocado_slots.json")
+	slots, err := osp.ParseOcadoSlots(f)
+	require.NoError(t, err)
+	require.Len(t, slots, 3)
+
+	s := slots[0]
+	assert.Equal(t, "5f1c2e7a-0900", s.ID)
+	assert.Equal(t, datasource.Ocado, s.Supermarket)
+	assert.Equal(t, datasource.SlotDelivery, s.Method)
+	assert.Equal(t, "Sat 7 Mar, 9:00am–10:00am", s.Description)
+	assert.InDelta(t, 3.99, s.Price, 0.001)
+	assert.True(t, s.Available)
+
+	assert.False(t, slots[1].Available)
+	assert.True(t, slots[2].Booked)
+}
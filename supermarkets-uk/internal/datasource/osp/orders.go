@@ -0,0 +1,111 @@
+package osp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+const ordersPageSize = 10
+
+type ordersResponse struct {
+	Orders     []ospOrder `json:"orders"`
+	TotalCount int        `json:"totalCount"`
+	Page       int        `json:"page"`
+	PageSize   int        `json:"pageSize"`
+}
+
+type ospOrder struct {
+	OrderID        string `json:"orderId"`
+	Status         string `json:"status"`
+	DeliveryMethod string `json:"deliveryMethod"`
+	DeliverySlot   struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	} `json:"deliverySlot"`
+	TotalPrice money `json:"totalPrice"`
+	ItemCount  int   `json:"itemCount"`
+	Items      []struct {
+		ProductID string `json:"productId"`
+		Name      string `json:"name"`
+		Quantity  int    `json:"quantity"`
+		ImageURL  string `json:"imageUrl"`
+	} `json:"items"`
+}
+
+// GetOrderHistory retrieves past orders for the logged-in account.
+func (d *ospDatasource) GetOrderHistory(
+	ctx context.Context, page int,
+) (*datasource.OrderHistoryResult, error) {
+	path := "/api/order/v1/orders?" + url.Values{
+		"page": {strconv.Itoa(max(page, 1))},
+		"size": {strconv.Itoa(ordersPageSize)},
+	}.Encode()
+
+	body, err := d.sessionRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s orders fetch: %w", d.cfg.id, err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return parseOrders(body, d.cfg.id)
+}
+
+// parseOrders parses an OSP order API response.
+func parseOrders(r io.Reader, id datasource.SupermarketID) (*datasource.OrderHistoryResult, error) {
+	var resp ordersResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("%s: decode orders: %w", id, err)
+	}
+
+	total := resp.TotalCount
+	result := &datasource.OrderHistoryResult{
+		Supermarket: id,
+		Orders:      make([]datasource.Order, 0, len(resp.Orders)),
+		Total:       &total,
+		Page:        resp.Page,
+		PageSize:    resp.PageSize,
+	}
+	for _, o := range resp.Orders {
+		result.Orders = append(result.Orders, convertOrder(o, id))
+	}
+	return result, nil
+}
+
+func convertOrder(o ospOrder, id datasource.SupermarketID) datasource.Order {
+	order := datasource.Order{
+		ID:             o.OrderID,
+		Supermarket:    id,
+		Status:         o.Status,
+		ShoppingMethod: o.DeliveryMethod,
+		TotalPrice:     o.TotalPrice.value(),
+		TotalItems:     o.ItemCount,
+		Currency:       o.TotalPrice.Currency,
+	}
+	if order.Currency == "" {
+		order.Currency = "GBP"
+	}
+	start, err1 := time.Parse(time.RFC3339, o.DeliverySlot.Start)
+	end, err2 := time.Parse(time.RFC3339, o.DeliverySlot.End)
+	if err1 == nil {
+		order.Date = start.Format(datasource.OrderDateLayout)
+		if err2 == nil {
+			order.DeliverySlot = datasource.FormatDeliverySlot(start, end)
+		}
+	}
+	for _, item := range o.Items {
+		order.Items = append(order.Items, datasource.OrderItem{
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+		})
+	}
+	return order
+}
@@ -0,0 +1,76 @@
+package osp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+type slotsResponse struct {
+	Slots []ospSlot `json:"slots"`
+}
+
+type ospSlot struct {
+	SlotID         string `json:"slotId"`
+	StartTime      string `json:"startTime"`
+	EndTime        string `json:"endTime"`
+	DeliveryMethod string `json:"deliveryMethod"`
+	Price          money  `json:"price"`
+	Available      bool   `json:"available"`
+	Reserved       bool   `json:"reserved"`
+}
+
+// GetSlots retrieves delivery and click-and-collect slots for the
+// logged-in account's address.
+func (d *ospDatasource) GetSlots(
+	ctx context.Context, from, to time.Time,
+) ([]datasource.Slot, error) {
+	path := "/api/slot/v1/slots?" + url.Values{
+		"from": {from.Format(time.RFC3339)},
+		"to":   {to.Format(time.RFC3339)},
+	}.Encode()
+
+	body, err := d.sessionRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s slots fetch: %w", d.cfg.id, err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+
+	slots, err := parseSlots(body, d.cfg.id)
+	if err != nil {
+		return nil, err
+	}
+	return datasource.SlotsBetween(slots, from, to), nil
+}
+
+// parseSlots parses an OSP slot API response. Slots with unparseable times
+// are skipped.
+func parseSlots(r io.Reader, id datasource.SupermarketID) ([]datasource.Slot, error) {
+	var resp slotsResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("%s: decode slots: %w", id, err)
+	}
+
+	slots := make([]datasource.Slot, 0, len(resp.Slots))
+	for _, s := range resp.Slots {
+		start, err1 := time.Parse(time.RFC3339, s.StartTime)
+		end, err2 := time.Parse(time.RFC3339, s.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		method := datasource.SlotDelivery
+		if s.DeliveryMethod == "CLICK_AND_COLLECT" {
+			method = datasource.SlotCollection
+		}
+		slot := datasource.NewSlot(s.SlotID, id, method, start, end, s.Price.value(), s.Available)
+		slot.Booked = s.Reserved
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
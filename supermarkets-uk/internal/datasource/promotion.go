@@ -0,0 +1,324 @@
+package datasource
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OfferKind is the type of a structured promotion.
+type OfferKind string
+
+const (
+	// MultiBuy is a fixed price for a number of items, e.g. "3 for £5".
+	MultiBuy OfferKind = "multibuy"
+	// BuyXPayY charges for fewer items than taken, e.g. "3 for 2" or
+	// "buy one get one free".
+	BuyXPayY OfferKind = "buy_x_pay_y"
+	// LoyaltyPrice is a per-item price for loyalty card holders, e.g.
+	// "Clubcard Price £2.50".
+	LoyaltyPrice OfferKind = "loyalty_price"
+	// PercentOff is a percentage discount, e.g. "Buy 2 save 25%" or
+	// "Buy one get one half price".
+	PercentOff OfferKind = "percent_off"
+	// AmountOff is a fixed discount, e.g. "Buy 2 save £1".
+	AmountOff OfferKind = "amount_off"
+	// SalePrice is a reduced per-item price, e.g. "Was £2 Now £1.50".
+	SalePrice OfferKind = "sale_price"
+)
+
+// Offer is a promotion parsed from a product's promotion text.
+type Offer struct {
+	Kind OfferKind `json:"kind"`
+	Text string    `json:"text"`
+	// Quantity is how many items qualify for the offer (1 for per-item
+	// offers). Per-item PercentOff and AmountOff offers, such as "Save 25%",
+	// describe a reduction already in the shelf price.
+	Quantity int `json:"quantity"`
+	// Price is the bundle price for MultiBuy, or the per-item price for
+	// LoyaltyPrice and SalePrice.
+	Price float64 `json:"price,omitempty"`
+	// PayFor is how many of Quantity items are charged for BuyXPayY.
+	PayFor     int     `json:"payFor,omitempty"`
+	PercentOff float64 `json:"percentOff,omitempty"`
+	AmountOff  float64 `json:"amountOff,omitempty"`
+	// Loyalty names the loyalty scheme required, e.g. "Clubcard".
+	Loyalty string `json:"loyalty,omitempty"`
+	// MixAndMatch is true when different products in the promotion's group
+	// can be combined to qualify, e.g. "Any 3 for £5". Cost calculations
+	// only count the product itself.
+	MixAndMatch bool `json:"mixAndMatch,omitempty"`
+}
+
+// loyaltySchemes pairs lower-case scheme names with their display names.
+var loyaltySchemes = []struct {
+	pattern string
+	name    string
+}{
+	{"clubcard", "Clubcard"},
+	{"nectar", "Nectar"},
+	{"my waitrose", "myWaitrose"},
+	{"asda rewards", "Asda Rewards"},
+	{"more card", "More Card"},
+	{"morrisons more", "More Card"},
+	{"lidl plus", "Lidl Plus"},
+	{"bonus card", "Iceland Bonus Card"},
+	{"member price", "Co-op Membership"},
+	{"co-op member", "Co-op Membership"},
+}
+
+const pricePattern = `(?:£\s*(\d+(?:\.\d{1,2})?)|(\d+)\s*p\b)`
+
+var (
+	multiBuyRe = regexp.MustCompile(`(?i)\b(\d+)\s+for\s+` + pricePattern)
+	// bareMultiBuyRe matches a multi-buy price written without "£", such as
+	// "3 for 2.50"; "3 for 2" is told apart by parseBareMultiBuy.
+	bareMultiBuyRe = regexp.MustCompile(`(?i)\b(\d+)\s+for\s+(\d+(?:\.\d{1,2})?)\b`)
+	buyXPayYRe     = regexp.MustCompile(`(?i)\b(\d+)\s+for\s+(\d+)\b`)
+	buyGetHalfRe   = regexp.MustCompile(
+		`(?i)\bbuy\s+(\d+|one|two)\s+get\s+(?:(?:the\s+)?(\d+|one|two)(?:nd)?\s+)?(?:for\s+)?half\s+price\b`)
+	buyGetFreeRe = regexp.MustCompile(`(?i)\bbuy\s+(\d+|one|two)\s+get\s+(\d+|one)\s+free\b|\bbogof\b`)
+	percentRe    = regexp.MustCompile(
+		`(?i)(?:\bbuy\s+(?:any\s+)?(\d+)\s+)?(?:save\s+(\d+(?:\.\d+)?)\s*%|(\d+(?:\.\d+)?)\s*%\s+off)`)
+	halfPriceRe = regexp.MustCompile(`(?i)\bhalf\s+price\b`)
+	amountOffRe = regexp.MustCompile(`(?i)(?:\bbuy\s+(?:any\s+)?(\d+)\s+)?save\s+` + pricePattern)
+	salePriceRe = regexp.MustCompile(`(?i)\bnow\s+(?:only\s+)?` + pricePattern)
+	wasPriceRe  = regexp.MustCompile(`(?i)\bwas\s+` + pricePattern)
+	anyPriceRe  = regexp.MustCompile(pricePattern)
+	mixMatchRe  = regexp.MustCompile(`(?i)\bany\b|\bmix\s*(?:and|&)\s*match\b`)
+)
+
+var numberWords = map[string]int{"one": 1, "two": 2}
+
+// offerParsers are tried in order; the first to recognise the text wins.
+// Sale prices come first, since their text often repeats the saving, as in
+// "Half Price: Was £3 Now £1.50", and the shelf price is already the sale
+// price. A plain loyalty price is only considered when none match, so
+// "Any 3 for £5 Clubcard Price" is a multi-buy for card holders rather
+// than a per-item price.
+var offerParsers = []func(s string) (Offer, bool){
+	parseSalePrice,
+	parseMultiBuy,
+	parseBuyXPayY,
+	parsePercentOff,
+	parseAmountOff,
+}
+
+// ParsePromotions parses a product's promotion text, which may hold several
+// promotions separated by semicolons or newlines, into structured offers.
+// Text that isn't recognised is skipped.
+func ParsePromotions(s string) []Offer {
+	var offers []Offer
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if o, ok := ParsePromotion(part); ok {
+			offers = append(offers, o)
+		}
+	}
+	return offers
+}
+
+// ParsePromotion parses a single promotion such as "3 for £5",
+// "Clubcard Price £2.50" or "Buy 2 save 25%".
+func ParsePromotion(s string) (Offer, bool) {
+	s = strings.TrimSpace(s)
+	loyalty := loyaltyScheme(s)
+	var o Offer
+	ok := false
+	for _, parse := range offerParsers {
+		if o, ok = parse(s); ok {
+			break
+		}
+	}
+	if !ok && loyalty != "" {
+		if m := anyPriceRe.FindStringSubmatch(s); m != nil {
+			o = Offer{Kind: LoyaltyPrice, Quantity: 1, Price: matchedPrice(m[1], m[2])}
+			ok = o.Price > 0
+		}
+	}
+	if !ok {
+		return Offer{}, false
+	}
+	o.Text = s
+	o.Loyalty = loyalty
+	o.MixAndMatch = mixMatchRe.MatchString(s)
+	return o, true
+}
+
+func loyaltyScheme(s string) string {
+	lower := strings.ToLower(s)
+	for _, scheme := range loyaltySchemes {
+		if strings.Contains(lower, scheme.pattern) {
+			return scheme.name
+		}
+	}
+	return ""
+}
+
+func parseMultiBuy(s string) (Offer, bool) {
+	m := multiBuyRe.FindStringSubmatch(s)
+	if m == nil {
+		return parseBareMultiBuy(s)
+	}
+	n, _ := strconv.Atoi(m[1])
+	price := matchedPrice(m[2], m[3])
+	return Offer{Kind: MultiBuy, Quantity: n, Price: price}, n > 0 && price > 0
+}
+
+// parseBareMultiBuy parses a multi-buy whose price has no "£". The price is
+// taken as pounds when it has pence, as in "3 for 2.50", or is at least the
+// quantity, as in "2 for 5"; otherwise "3 for 2" is a buy-X-pay-Y offer.
+func parseBareMultiBuy(s string) (Offer, bool) {
+	m := bareMultiBuyRe.FindStringSubmatch(s)
+	if m == nil {
+		return Offer{}, false
+	}
+	n, _ := strconv.Atoi(m[1])
+	price, _ := strconv.ParseFloat(m[2], 64)
+	if !strings.Contains(m[2], ".") && price < float64(n) {
+		return Offer{}, false
+	}
+	return Offer{Kind: MultiBuy, Quantity: n, Price: price}, n > 0 && price > 0
+}
+
+func parseBuyXPayY(s string) (Offer, bool) {
+	if m := buyXPayYRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		pay, _ := strconv.Atoi(m[2])
+		return Offer{Kind: BuyXPayY, Quantity: n, PayFor: pay}, pay > 0 && pay < n
+	}
+	m := buyGetFreeRe.FindStringSubmatch(s)
+	if m == nil {
+		return Offer{}, false
+	}
+	buy, free := 1, 1
+	if m[1] != "" {
+		buy, free = wordNumber(m[1]), wordNumber(m[2])
+	}
+	return Offer{Kind: BuyXPayY, Quantity: buy + free, PayFor: buy}, buy > 0 && free > 0
+}
+
+func parsePercentOff(s string) (Offer, bool) {
+	// "Buy 1 get 1 half price" takes half the price of the extra items off
+	// the whole group.
+	if m := buyGetHalfRe.FindStringSubmatch(s); m != nil {
+		buy, half := wordNumber(m[1]), 1
+		if m[2] != "" {
+			half = wordNumber(m[2])
+		}
+		n := buy + half
+		return Offer{Kind: PercentOff, Quantity: n, PercentOff: 50 * float64(half) / float64(n)}, buy > 0 && half > 0
+	}
+	if halfPriceRe.MatchString(s) {
+		return Offer{Kind: PercentOff, Quantity: 1, PercentOff: 50}, true
+	}
+	m := percentRe.FindStringSubmatch(s)
+	if m == nil {
+		return Offer{}, false
+	}
+	pct, _ := strconv.ParseFloat(m[2]+m[3], 64)
+	return Offer{Kind: PercentOff, Quantity: atoiDefault(m[1], 1), PercentOff: pct}, pct > 0 && pct < 100
+}
+
+func parseSalePrice(s string) (Offer, bool) {
+	m := salePriceRe.FindStringSubmatch(s)
+	if m == nil {
+		m = wasPriceRe.FindStringSubmatch(s)
+		if m == nil {
+			return Offer{}, false
+		}
+		// "Was £2 - save 25%" gives only the old price; the shelf price is
+		// the sale price.
+		return Offer{Kind: SalePrice, Quantity: 1}, true
+	}
+	price := matchedPrice(m[1], m[2])
+	return Offer{Kind: SalePrice, Quantity: 1, Price: price}, price > 0
+}
+
+func parseAmountOff(s string) (Offer, bool) {
+	m := amountOffRe.FindStringSubmatch(s)
+	if m == nil {
+		return Offer{}, false
+	}
+	amount := matchedPrice(m[2], m[3])
+	return Offer{Kind: AmountOff, Quantity: atoiDefault(m[1], 1), AmountOff: amount}, amount > 0
+}
+
+// matchedPrice converts a pounds or pence submatch into pounds.
+func matchedPrice(pounds, pence string) float64 {
+	if pounds != "" {
+		v, _ := strconv.ParseFloat(pounds, 64)
+		return v
+	}
+	if pence != "" {
+		v, _ := strconv.ParseFloat(pence, 64)
+		return v / 100
+	}
+	return 0
+}
+
+func wordNumber(s string) int {
+	if n, ok := numberWords[strings.ToLower(s)]; ok {
+		return n
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func atoiDefault(s string, def int) int {
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
+
+// Cost returns the total cost of qty items at the given shelf price with
+// the offer applied. Items that don't make up a full qualifying group are
+// charged at the shelf price, and an offer never increases the cost.
+func (o Offer) Cost(price float64, qty int) float64 {
+	full := price * float64(qty)
+	q := max(o.Quantity, 1)
+	groups, rest := qty/q, float64(qty%q)
+	var cost float64
+	switch o.Kind {
+	case MultiBuy:
+		cost = float64(groups)*o.Price + rest*price
+	case BuyXPayY:
+		cost = float64(groups*o.PayFor)*price + rest*price
+	case PercentOff, AmountOff:
+		if o.Quantity <= 1 {
+			// Per-item reductions are already in the shelf price.
+			return full
+		}
+		if o.Kind == PercentOff {
+			cost = float64(groups*q)*price*(1-o.PercentOff/100) + rest*price
+		} else {
+			cost = float64(groups)*(float64(q)*price-o.AmountOff) + rest*price
+		}
+	case LoyaltyPrice:
+		cost = float64(qty) * o.Price
+	case SalePrice:
+		// The shelf price is already the sale price.
+		return full
+	default:
+		return full
+	}
+	return math.Round(min(cost, full)*100) / 100
+}
+
+// EffectiveCost returns the cheapest total cost of qty of the product using
+// its parsed offers, along with the offer applied (nil if none helps).
+// Offers needing a loyalty card are only used when loyalty is true.
+func EffectiveCost(p Product, qty int, loyalty bool) (float64, *Offer) {
+	best := math.Round(p.Price*float64(qty)*100) / 100
+	var applied *Offer
+	for i, o := range p.Offers {
+		if o.Loyalty != "" && !loyalty {
+			continue
+		}
+		if cost := o.Cost(p.Price, qty); cost < best {
+			best, applied = cost, &p.Offers[i]
+		}
+	}
+	return best, applied
+}
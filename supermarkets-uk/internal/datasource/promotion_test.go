@@ -0,0 +1,113 @@
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+func TestParsePromotion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  datasource.Offer
+	}{
+		{"3 for £5", datasource.Offer{Kind: datasource.MultiBuy, Quantity: 3, Price: 5}},
+		{"Buy any 2 for £4", datasource.Offer{Kind: datasource.MultiBuy, Quantity: 2, Price: 4, MixAndMatch: true}},
+		{"2 for 90p", datasource.Offer{Kind: datasource.MultiBuy, Quantity: 2, Price: 0.9}},
+		{"Any 3 for £5 Clubcard Price", datasource.Offer{
+			Kind: datasource.MultiBuy, Quantity: 3, Price: 5, Loyalty: "Clubcard", MixAndMatch: true,
+		}},
+		{"Clubcard Price £2.50", datasource.Offer{Kind: datasource.LoyaltyPrice, Quantity: 1, Price: 2.5, Loyalty: "Clubcard"}},
+		{"Nectar Price £1.70", datasource.Offer{Kind: datasource.LoyaltyPrice, Quantity: 1, Price: 1.7, Loyalty: "Nectar"}},
+		{"Lidl Plus Price £1.25", datasource.Offer{
+			Kind: datasource.LoyaltyPrice, Quantity: 1, Price: 1.25, Loyalty: "Lidl Plus",
+		}},
+		{"Member Price £2", datasource.Offer{
+			Kind: datasource.LoyaltyPrice, Quantity: 1, Price: 2, Loyalty: "Co-op Membership",
+		}},
+		{"Buy 2 save 25%", datasource.Offer{Kind: datasource.PercentOff, Quantity: 2, PercentOff: 25}},
+		{"Half Price", datasource.Offer{Kind: datasource.PercentOff, Quantity: 1, PercentOff: 50}},
+		{"3 for 2", datasource.Offer{Kind: datasource.BuyXPayY, Quantity: 3, PayFor: 2}},
+		{"Buy one get one free", datasource.Offer{Kind: datasource.BuyXPayY, Quantity: 2, PayFor: 1}},
+		{"Save £1", datasource.Offer{Kind: datasource.AmountOff, Quantity: 1, AmountOff: 1}},
+		{"Was £2.00 Now £1.50", datasource.Offer{Kind: datasource.SalePrice, Quantity: 1, Price: 1.5}},
+		{"Half Price: Was £3 Now £1.50", datasource.Offer{Kind: datasource.SalePrice, Quantity: 1, Price: 1.5}},
+		{"Was £2 now £1.50 - save 25%", datasource.Offer{Kind: datasource.SalePrice, Quantity: 1, Price: 1.5}},
+		{"Was £2 - save 25%", datasource.Offer{Kind: datasource.SalePrice, Quantity: 1}},
+		{"Buy 1 get 1 half price", datasource.Offer{Kind: datasource.PercentOff, Quantity: 2, PercentOff: 25}},
+		{"3 for 2.50", datasource.Offer{Kind: datasource.MultiBuy, Quantity: 3, Price: 2.5}},
+		{"2 for 5", datasource.Offer{Kind: datasource.MultiBuy, Quantity: 2, Price: 5}},
+	}
+	for _, tt := range tests {
+		got, ok := datasource.ParsePromotion(tt.input)
+		require.True(t, ok, tt.input)
+		tt.want.Text = tt.input
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+
+	for _, s := range []string{"", "Clubcard Price", "New", "Low price"} {
+		_, ok := datasource.ParsePromotion(s)
+		assert.False(t, ok, s)
+	}
+}
+
+func TestParsePromotions(t *testing.T) {
+	offers := datasource.ParsePromotions("Nectar Price £1.70; Buy any 2 for £3; Limited edition")
+	require.Len(t, offers, 2)
+	assert.Equal(t, datasource.LoyaltyPrice, offers[0].Kind)
+	assert.Equal(t, datasource.MultiBuy, offers[1].Kind)
+}
+
+func TestOfferCost(t *testing.T) {
+	tests := []struct {
+		offer string
+		price float64
+		qty   int
+		want  float64
+	}{
+		{"3 for £5", 2, 3, 5},
+		{"3 for £5", 2, 4, 7},
+		{"3 for £5", 2, 2, 4},
+		{"3 for 2", 1.5, 6, 6},
+		{"Buy 2 save 25%", 2, 3, 5},
+		// Per-item reductions and sale prices are already in the shelf price.
+		{"Save 50p", 1.2, 2, 2.4},
+		{"Half Price", 1.5, 2, 3},
+		{"Half Price: Was £3 Now £1.50", 1.5, 2, 3},
+		{"Was £2 now £1.50 - save 25%", 1.5, 1, 1.5},
+		{"Buy 1 get 1 half price", 2, 2, 3},
+		{"Buy 1 get 1 half price", 2, 3, 5},
+		{"3 for 2.50", 1, 3, 2.5},
+		{"Clubcard Price £2.50", 3, 2, 5},
+		// A multibuy dearer than the shelf price is never applied.
+		{"2 for £5", 2, 2, 4},
+	}
+	for _, tt := range tests {
+		o, ok := datasource.ParsePromotion(tt.offer)
+		require.True(t, ok, tt.offer)
+		assert.InDelta(t, tt.want, o.Cost(tt.price, tt.qty), 0.001, "%s x%d", tt.offer, tt.qty)
+	}
+}
+
+func TestEffectiveCost(t *testing.T) {
+	p := datasource.Product{Price: 2, Promotion: "Clubcard Price £1.50; 3 for £5"}
+	p.Normalise()
+	require.Len(t, p.Offers, 2)
+
+	cost, offer := datasource.EffectiveCost(p, 3, true)
+	assert.InDelta(t, 4.5, cost, 0.001)
+	require.NotNil(t, offer)
+	assert.Equal(t, datasource.LoyaltyPrice, offer.Kind)
+
+	cost, offer = datasource.EffectiveCost(p, 3, false)
+	assert.InDelta(t, 5, cost, 0.001)
+	require.NotNil(t, offer)
+	assert.Equal(t, datasource.MultiBuy, offer.Kind)
+
+	cost, offer = datasource.EffectiveCost(p, 1, false)
+	assert.InDelta(t, 2, cost, 0.001)
+	assert.Nil(t, offer)
+}
@@ -0,0 +1,230 @@
+package datasource
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unit is the dimension a quantity is measured in.
+type Unit string
+
+const (
+	// Grams measures weight.
+	Grams Unit = "g"
+	// Millilitres measures volume.
+	Millilitres Unit = "ml"
+	// Count measures a number of items.
+	Count Unit = "each"
+)
+
+// Quantity is a pack size normalised to grams, millilitres, or a count.
+// Packs is the number of identical packs in a multipack (1 otherwise).
+type Quantity struct {
+	Amount float64 `json:"amount"`
+	Unit   Unit    `json:"unit"`
+	Packs  int     `json:"packs"`
+}
+
+// Total returns the combined amount across all packs.
+func (q Quantity) Total() float64 {
+	return q.Amount * float64(q.Packs)
+}
+
+// String renders the quantity, e.g. "4 x 415g" or "2000ml".
+func (q Quantity) String() string {
+	amount := strconv.FormatFloat(q.Amount, 'f', -1, 64)
+	s := amount + string(q.Unit)
+	if q.Unit == Count {
+		s = amount
+	}
+	if q.Packs > 1 {
+		return fmt.Sprintf("%d x %s", q.Packs, s)
+	}
+	return s
+}
+
+// unitScales maps size suffixes to their unit and multiplier.
+var unitScales = map[string]struct {
+	unit  Unit
+	scale float64
+}{
+	"kg":     {Grams, 1000},
+	"kilo":   {Grams, 1000},
+	"kilos":  {Grams, 1000},
+	"g":      {Grams, 1},
+	"gr":     {Grams, 1},
+	"gram":   {Grams, 1},
+	"grams":  {Grams, 1},
+	"l":      {Millilitres, 1000},
+	"ltr":    {Millilitres, 1000},
+	"lt":     {Millilitres, 1000},
+	"litre":  {Millilitres, 1000},
+	"litres": {Millilitres, 1000},
+	"liter":  {Millilitres, 1000},
+	"liters": {Millilitres, 1000},
+	"cl":     {Millilitres, 10},
+	"ml":     {Millilitres, 1},
+	"pint":   {Millilitres, 568},
+	"pints":  {Millilitres, 568},
+	"pt":     {Millilitres, 568},
+}
+
+const (
+	numberPattern = `(\d+(?:\.\d+)?)`
+	unitPattern   = `(kg|kilos?|grams?|gr|g|litres?|liters?|ltr|lt|l|cl|ml|pints?|pt)`
+)
+
+var (
+	// multipackRe matches "4 x 415g" style multipacks.
+	multipackRe = regexp.MustCompile(`(?i)\b(\d+)\s*[x×]\s*` + numberPattern + `\s*` + unitPattern + `\b`)
+	// sizeRe matches a single weight or volume such as "2.272L" or "500 g".
+	sizeRe = regexp.MustCompile(`(?i)(?:^|[^\w.])` + numberPattern + `\s*` + unitPattern + `\b`)
+	// countRe matches item counts such as "6 pack", "12 eggs" or "pack of 4".
+	countRe = regexp.MustCompile(
+		`(?i)\b(?:(\d+)\s*(?:pack|pk|pcs|pieces|each|ea|count|ct|rolls|eggs)|pack\s+of\s+(\d+)|x\s?(\d+))\b`)
+)
+
+// ParseQuantity extracts a pack size from text such as a product name or
+// weight field. Weights and volumes take precedence over item counts.
+func ParseQuantity(s string) (Quantity, bool) {
+	q, _, ok := ExtractQuantity(s)
+	return q, ok
+}
+
+// ExtractQuantity finds a pack size in s, returning it together with s with
+// the matched text removed.
+func ExtractQuantity(s string) (Quantity, string, bool) {
+	if m := multipackRe.FindStringSubmatchIndex(s); m != nil {
+		packs, _ := strconv.Atoi(s[m[2]:m[3]])
+		amount, _ := strconv.ParseFloat(s[m[4]:m[5]], 64)
+		q, ok := scaled(amount, s[m[6]:m[7]], packs)
+		if ok {
+			return q, remove(s, m[0], m[1]), true
+		}
+	}
+	if m := sizeRe.FindStringSubmatchIndex(s); m != nil {
+		amount, _ := strconv.ParseFloat(s[m[2]:m[3]], 64)
+		q, ok := scaled(amount, s[m[4]:m[5]], 1)
+		if ok {
+			return q, remove(s, m[2], m[1]), true
+		}
+	}
+	if m := countRe.FindStringSubmatchIndex(s); m != nil {
+		for i := 2; i < len(m); i += 2 {
+			if m[i] < 0 {
+				continue
+			}
+			n, _ := strconv.Atoi(s[m[i]:m[i+1]])
+			if n > 0 {
+				return Quantity{Amount: float64(n), Unit: Count, Packs: 1}, remove(s, m[0], m[1]), true
+			}
+		}
+	}
+	return Quantity{}, s, false
+}
+
+// UnitPrice is a price per standard unit: per kilogram for weights, per
+// litre for volumes, and per item for counts.
+type UnitPrice struct {
+	Price float64 `json:"price"`
+	Per   string  `json:"per"`
+}
+
+// String renders the unit price, e.g. "£1.20/kg".
+func (u UnitPrice) String() string {
+	return fmt.Sprintf("£%.2f/%s", u.Price, u.Per)
+}
+
+// standardUnits gives the amount of each unit a UnitPrice is quoted per.
+var standardUnits = map[Unit]struct {
+	label  string
+	amount float64
+}{
+	Grams:       {"kg", 1000},
+	Millilitres: {"litre", 1000},
+	Count:       {"each", 1},
+}
+
+// countWords are the item words unit prices are quoted per, e.g. "£0.25 each".
+var countWords = map[string]bool{
+	"each": true, "ea": true, "item": true, "items": true, "unit": true, "units": true,
+	"sheet": true, "sheets": true, "roll": true, "rolls": true, "single": true,
+}
+
+// unitPriceRe matches unit prices such as "£1.20/kg", "12p/100g",
+// "£0.25 each" and "(£19.51 per kilo)".
+var unitPriceRe = regexp.MustCompile(
+	`(?i)(?:£\s*(\d+(?:\.\d+)?)|(\d+(?:\.\d+)?)\s*p)\s*(?:/|\bper\b|\ba\b)?\s*(\d+(?:\.\d+)?)?\s*([a-z]+)`)
+
+// ParseUnitPrice parses a store's price-per-unit text into a price per
+// standard unit.
+func ParseUnitPrice(s string) (UnitPrice, bool) {
+	m := unitPriceRe.FindStringSubmatch(s)
+	if m == nil {
+		return UnitPrice{}, false
+	}
+	var price float64
+	if m[1] != "" {
+		price, _ = strconv.ParseFloat(m[1], 64)
+	} else {
+		pence, _ := strconv.ParseFloat(m[2], 64)
+		price = pence / 100
+	}
+	amount := 1.0
+	if m[3] != "" {
+		amount, _ = strconv.ParseFloat(m[3], 64)
+	}
+	per, ok := scaled(amount, m[4], 1)
+	if !ok && countWords[strings.ToLower(m[4])] && amount > 0 {
+		per, ok = Quantity{Amount: amount, Unit: Count, Packs: 1}, true
+	}
+	if !ok || price <= 0 {
+		return UnitPrice{}, false
+	}
+	return PricePer(price, per)
+}
+
+// PricePer returns the price per standard unit of buying q for price.
+func PricePer(price float64, q Quantity) (UnitPrice, bool) {
+	std, ok := standardUnits[q.Unit]
+	if !ok || q.Total() <= 0 || price <= 0 {
+		return UnitPrice{}, false
+	}
+	per := price / q.Total() * std.amount
+	return UnitPrice{Price: math.Round(per*10000) / 10000, Per: std.label}, true
+}
+
+// Normalise sets p's Quantity, UnitPrice, and Offers from its weight, name,
+// price-per-unit text, price, and promotion. The store's own unit price is
+// preferred; otherwise one is derived from the price and parsed quantity.
+func (p *Product) Normalise() {
+	p.Offers = ParsePromotions(p.Promotion)
+	p.Quantity, p.UnitPrice = nil, nil
+	if q, ok := ParseQuantity(p.Weight); ok {
+		p.Quantity = &q
+	} else if q, ok := ParseQuantity(p.Name); ok {
+		p.Quantity = &q
+	}
+	if u, ok := ParseUnitPrice(p.PricePerUnit); ok {
+		p.UnitPrice = &u
+	} else if p.Quantity != nil {
+		if u, ok := PricePer(p.Price, *p.Quantity); ok {
+			p.UnitPrice = &u
+		}
+	}
+}
+
+func scaled(amount float64, suffix string, packs int) (Quantity, bool) {
+	u, ok := unitScales[strings.ToLower(suffix)]
+	if !ok || amount <= 0 || packs <= 0 {
+		return Quantity{}, false
+	}
+	return Quantity{Amount: amount * u.scale, Unit: u.unit, Packs: packs}, true
+}
+
+func remove(s string, start, end int) string {
+	return strings.Join(strings.Fields(s[:start]+" "+s[end:]), " ")
+}
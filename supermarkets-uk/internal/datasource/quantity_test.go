@@ -0,0 +1,96 @@
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input string
+		want  datasource.Quantity
+	}{
+		{"Tesco Semi Skimmed Milk 2.272L/4 Pints", datasource.Quantity{Amount: 2272, Unit: datasource.Millilitres, Packs: 1}},
+		{"Cravendale Semi Skimmed Milk 2L", datasource.Quantity{Amount: 2000, Unit: datasource.Millilitres, Packs: 1}},
+		{"Wholemeal Bread 800g", datasource.Quantity{Amount: 800, Unit: datasource.Grams, Packs: 1}},
+		{"Heinz Baked Beanz 4 x 415g", datasource.Quantity{Amount: 415, Unit: datasource.Grams, Packs: 4}},
+		{"Basmati Rice 1kg", datasource.Quantity{Amount: 1000, Unit: datasource.Grams, Packs: 1}},
+		{"Coca-Cola 33cl", datasource.Quantity{Amount: 330, Unit: datasource.Millilitres, Packs: 1}},
+		{"Free Range Eggs 6 Pack", datasource.Quantity{Amount: 6, Unit: datasource.Count, Packs: 1}},
+		{"Kitchen Roll Pack of 4", datasource.Quantity{Amount: 4, Unit: datasource.Count, Packs: 1}},
+	}
+	for _, tt := range tests {
+		got, ok := datasource.ParseQuantity(tt.input)
+		require.True(t, ok, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+
+	_, ok := datasource.ParseQuantity("Bananas Loose")
+	assert.False(t, ok)
+}
+
+func TestExtractQuantity(t *testing.T) {
+	q, rest, ok := datasource.ExtractQuantity("500g penne")
+	require.True(t, ok)
+	assert.InDelta(t, 500, q.Total(), 0.001)
+	assert.Equal(t, "penne", rest)
+
+	q, rest, ok = datasource.ExtractQuantity("2 x 400g chopped tomatoes")
+	require.True(t, ok)
+	assert.InDelta(t, 800, q.Total(), 0.001)
+	assert.Equal(t, "chopped tomatoes", rest)
+	assert.Equal(t, "2 x 400g", q.String())
+}
+
+func TestParseUnitPrice(t *testing.T) {
+	tests := []struct {
+		input string
+		want  datasource.UnitPrice
+	}{
+		{"£1.20/kg", datasource.UnitPrice{Price: 1.20, Per: "kg"}},
+		{"12p/100g", datasource.UnitPrice{Price: 1.20, Per: "kg"}},
+		{"£0.25 each", datasource.UnitPrice{Price: 0.25, Per: "each"}},
+		{"72.6p/litre", datasource.UnitPrice{Price: 0.726, Per: "litre"}},
+		{"(£19.51 per kilo)", datasource.UnitPrice{Price: 19.51, Per: "kg"}},
+		{"(£16.00 per item)", datasource.UnitPrice{Price: 16, Per: "each"}},
+		{"£1.25/lt)", datasource.UnitPrice{Price: 1.25, Per: "litre"}},
+		{"£13.33 per 75cl", datasource.UnitPrice{Price: 17.7733, Per: "litre"}},
+	}
+	for _, tt := range tests {
+		got, ok := datasource.ParseUnitPrice(tt.input)
+		require.True(t, ok, tt.input)
+		assert.Equal(t, tt.want.Per, got.Per, tt.input)
+		assert.InDelta(t, tt.want.Price, got.Price, 0.0001, tt.input)
+	}
+
+	_, ok := datasource.ParseUnitPrice("£1.50")
+	assert.False(t, ok)
+	_, ok = datasource.ParseUnitPrice("")
+	assert.False(t, ok)
+}
+
+func TestProductNormalise(t *testing.T) {
+	p := datasource.Product{Name: "Heinz Baked Beanz 4 x 415g", Price: 3.32}
+	p.Normalise()
+	require.NotNil(t, p.Quantity)
+	assert.Equal(t, 4, p.Quantity.Packs)
+	require.NotNil(t, p.UnitPrice)
+	assert.Equal(t, "kg", p.UnitPrice.Per)
+	assert.InDelta(t, 2.0, p.UnitPrice.Price, 0.0001)
+
+	// The store's own unit price and weight take precedence.
+	p = datasource.Product{Name: "Milk", Weight: "4 pint", Price: 1.65, PricePerUnit: "£0.73/litre"}
+	p.Normalise()
+	require.NotNil(t, p.Quantity)
+	assert.InDelta(t, 2272, p.Quantity.Total(), 0.001)
+	assert.Equal(t, &datasource.UnitPrice{Price: 0.73, Per: "litre"}, p.UnitPrice)
+
+	p = datasource.Product{Name: "Bananas Loose", Price: 0.15}
+	p.Normalise()
+	assert.Nil(t, p.Quantity)
+	assert.Nil(t, p.UnitPrice)
+}
@@ -0,0 +1,170 @@
+package sainsburys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// apiBasket is the basket returned by the basket/v2 endpoints.
+type apiBasket struct {
+	BasketID      string          `json:"basket_id"`
+	ItemCount     int             `json:"item_count"`
+	SubtotalPrice float64         `json:"subtotal_price"`
+	TotalPrice    float64         `json:"total_price"`
+	Items         []apiBasketItem `json:"items"`
+}
+
+type apiBasketItem struct {
+	ItemUID       string     `json:"item_uid"`
+	Quantity      int        `json:"quantity"`
+	UOM           string     `json:"uom"`
+	SubtotalPrice float64    `json:"subtotal_price"`
+	Product       apiProduct `json:"product"`
+}
+
+// basketItemUpdate sets the quantity of an item already in the basket.
+type basketItemUpdate struct {
+	ItemUID    string `json:"item_uid"`
+	ProductUID string `json:"product_uid"`
+	Quantity   int    `json:"quantity"`
+	UOM        string `json:"uom"`
+}
+
+// basketItemAdd adds a product that is not yet in the basket.
+type basketItemAdd struct {
+	ProductUID string `json:"product_uid"`
+	Quantity   int    `json:"quantity"`
+	UOM        string `json:"uom"`
+}
+
+const defaultUOM = "ea"
+
+// GetBasket retrieves the current shopping basket.
+func (s *Datasource) GetBasket(ctx context.Context) (*datasource.Basket, error) {
+	ab, err := s.fetchBasket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertBasket(ab), nil
+}
+
+// UpdateBasketItem adds, updates, or removes (quantity=0) a product in the
+// basket. Products already in the basket are updated by their basket item
+// UID; new products are added with a separate request.
+func (s *Datasource) UpdateBasketItem(
+	ctx context.Context, productID string, quantity int,
+) (*datasource.Basket, error) {
+	current, err := s.fetchBasket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *apiBasketItem
+	for i, item := range current.Items {
+		if item.Product.ProductUID == productID {
+			existing = &current.Items[i]
+			break
+		}
+	}
+
+	var ab *apiBasket
+	switch {
+	case existing != nil:
+		uom := existing.UOM
+		if uom == "" {
+			uom = defaultUOM
+		}
+		update := struct {
+			Items []basketItemUpdate `json:"items"`
+		}{[]basketItemUpdate{{
+			ItemUID:    existing.ItemUID,
+			ProductUID: productID,
+			Quantity:   quantity,
+			UOM:        uom,
+		}}}
+		ab, err = s.basketRequest(ctx, http.MethodPut, "/basket/v2/basket", update)
+	case quantity > 0:
+		add := basketItemAdd{ProductUID: productID, Quantity: quantity, UOM: defaultUOM}
+		ab, err = s.basketRequest(ctx, http.MethodPost, "/basket/v2/basket/item", add)
+	default:
+		// Removing a product that isn't in the basket leaves it unchanged.
+		ab = current
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sainsburys: update basket: %w", err)
+	}
+	return convertBasket(ab), nil
+}
+
+func (s *Datasource) fetchBasket(ctx context.Context) (*apiBasket, error) {
+	ab, err := s.basketRequest(ctx, http.MethodGet, "/basket/v2/basket", nil)
+	if err != nil {
+		return nil, fmt.Errorf("sainsburys basket: %w", err)
+	}
+	return ab, nil
+}
+
+// basketRequest sends a basket API request and decodes the basket in the
+// response.
+func (s *Datasource) basketRequest(
+	ctx context.Context, method, path string, payload any,
+) (*apiBasket, error) {
+	body, err := s.sessionRequest(ctx, method, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+	return decodeBasket(body)
+}
+
+// ParseBasket parses a Sainsbury's basket API response.
+func ParseBasket(r io.Reader) (*datasource.Basket, error) {
+	ab, err := decodeBasket(r)
+	if err != nil {
+		return nil, err
+	}
+	return convertBasket(ab), nil
+}
+
+func decodeBasket(r io.Reader) (*apiBasket, error) {
+	var ab apiBasket
+	if err := json.NewDecoder(r).Decode(&ab); err != nil {
+		return nil, fmt.Errorf("sainsburys: decode basket: %w", err)
+	}
+	return &ab, nil
+}
+
+func convertBasket(ab *apiBasket) *datasource.Basket {
+	basket := &datasource.Basket{
+		Supermarket: datasource.Sainsburys,
+		TotalPrice:  ab.TotalPrice,
+		TotalItems:  ab.ItemCount,
+		Currency:    "GBP",
+	}
+	if basket.TotalPrice == 0 {
+		basket.TotalPrice = ab.SubtotalPrice
+	}
+
+	countItems := basket.TotalItems == 0
+	for _, item := range ab.Items {
+		p := convertProduct(item.Product)
+		basket.Items = append(basket.Items, datasource.BasketItem{
+			ProductID: p.ID,
+			Name:      p.Name,
+			Quantity:  item.Quantity,
+			Cost:      item.SubtotalPrice,
+			Price:     p.Price,
+			ImageURL:  p.ImageURL,
+			Promotion: p.Promotion,
+		})
+		if countItems {
+			basket.TotalItems += item.Quantity
+		}
+	}
+	return basket
+}
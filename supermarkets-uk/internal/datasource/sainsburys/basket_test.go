@@ -0,0 +1,123 @@
+package sainsburys_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/sainsburys"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+var sessionCookies = []*http.Cookie{{Name: "WC_AUTHENTICATION_12345", Value: "my-auth-token"}}
+
+func TestParseBasket(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/sainsburys_basket.json")
+	basket, err := sainsburys.ParseBasket(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Sainsburys, basket.Supermarket)
+	assert.Equal(t, "GBP", basket.Currency)
+	assert.InDelta(t, 4.60, basket.TotalPrice, 0.001)
+	assert.Equal(t, 3, basket.TotalItems)
+	require.Len(t, basket.Items, 2)
+
+	i1 := basket.Items[0]
+	assert.Equal(t, "7878921", i1.ProductID)
+	assert.Equal(t, "Sainsbury's British Semi Skimmed Milk 2.27L", i1.Name)
+	assert.Equal(t, 2, i1.Quantity)
+	assert.InDelta(t, 2.90, i1.Cost, 0.001)
+	assert.InDelta(t, 1.45, i1.Price, 0.001)
+	assert.Empty(t, i1.Promotion)
+
+	i2 := basket.Items[1]
+	assert.Equal(t, "7920105", i2.ProductID)
+	assert.Equal(t, 1, i2.Quantity)
+	assert.Equal(t, "Nectar Price £1.70", i2.Promotion)
+}
+
+// basketServer serves the basket fixture and records write requests.
+func basketServer(t *testing.T, writes *[]*http.Request, bodies *[]map[string]any) *httptest.Server {
+	t.Helper()
+	fixture, err := os.ReadFile("testdata/sainsburys_basket.json")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*writes = append(*writes, r)
+			*bodies = append(*bodies, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixture)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestUpdateBasketItem(t *testing.T) {
+	var writes []*http.Request
+	var bodies []map[string]any
+	srv := basketServer(t, &writes, &bodies)
+	ds := sainsburys.NewDatasource(sainsburys.Config{BaseURL: srv.URL}, srv.Client())
+	ds.SetCookies(sessionCookies)
+
+	// A product already in the basket is updated by its item UID.
+	_, err := ds.UpdateBasketItem(t.Context(), "7920105", 0)
+	require.NoError(t, err)
+	require.Len(t, writes, 1)
+	assert.Equal(t, http.MethodPut, writes[0].Method)
+	assert.Equal(t, "/basket/v2/basket", writes[0].URL.Path)
+	items, ok := bodies[0]["items"].([]any)
+	require.True(t, ok)
+	require.Len(t, items, 1)
+	item, ok := items[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "0b9e4d37-6a21-4f7c-8e12-c4d5a6b7e802", item["item_uid"])
+	assert.InDelta(t, 0, item["quantity"], 0.001)
+
+	// A new product is added.
+	basket, err := ds.UpdateBasketItem(t.Context(), "1234567", 3)
+	require.NoError(t, err)
+	require.Len(t, writes, 2)
+	assert.Equal(t, http.MethodPost, writes[1].Method)
+	assert.Equal(t, "/basket/v2/basket/item", writes[1].URL.Path)
+	assert.Equal(t, "1234567", bodies[1]["product_uid"])
+	assert.InDelta(t, 3, bodies[1]["quantity"], 0.001)
+	assert.Equal(t, datasource.Sainsburys, basket.Supermarket)
+
+	// Removing a product that isn't in the basket sends nothing.
+	_, err = ds.UpdateBasketItem(t.Context(), "1234567", 0)
+	require.NoError(t, err)
+	assert.Len(t, writes, 2)
+}
+
+func TestBasketRequiresSession(t *testing.T) {
+	var writes []*http.Request
+	var bodies []map[string]any
+	srv := basketServer(t, &writes, &bodies)
+	ds := sainsburys.NewDatasource(sainsburys.Config{BaseURL: srv.URL}, srv.Client())
+
+	_, err := ds.GetBasket(t.Context())
+	assert.Error(t, err)
+}
+
+func TestBasketSessionExpired(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	ds := sainsburys.NewDatasource(sainsburys.Config{BaseURL: srv.URL}, srv.Client())
+	ds.SetCookies(sessionCookies)
+
+	_, err := ds.GetBasket(t.Context())
+	require.ErrorIs(t, err, datasource.ErrSessionExpired)
+}
@@ -0,0 +1,64 @@
+package sainsburys_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/sainsburys"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseOrderHistory(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/sainsburys_orders.json")
+	result, err := sainsburys.ParseOrderHistory(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Sainsburys, result.Supermarket)
+	require.NotNil(t, result.Total)
+	assert.Equal(t, 14, *result.Total)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, 10, result.PageSize)
+	require.Len(t, result.Orders, 2)
+
+	o := result.Orders[0]
+	assert.Equal(t, "1187654321", o.ID)
+	assert.Equal(t, "DELIVERED", o.Status)
+	assert.Equal(t, "2026-03-07", o.Date)
+	assert.Equal(t, "Sat 7 Mar, 9:00am–10:00am", o.DeliverySlot)
+	assert.InDelta(t, 54.21, o.TotalPrice, 0.001)
+	assert.Equal(t, 3, o.TotalItems)
+	assert.Equal(t, "GBP", o.Currency)
+	require.Len(t, o.Items, 2)
+	assert.Equal(t, "7878921", o.Items[0].ProductID)
+	assert.Equal(t, 2, o.Items[0].Quantity)
+
+	assert.Equal(t, "CLICK_AND_COLLECT", result.Orders[1].ShoppingMethod)
+	assert.Empty(t, result.Orders[1].Items)
+}
+
+func TestGetOrderHistoryPage(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/sainsburys_orders.json")
+	require.NoError(t, err)
+
+	var gotPage string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPage = r.URL.Query().Get("page_number")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	ds := sainsburys.NewDatasource(sainsburys.Config{BaseURL: srv.URL}, srv.Client())
+	ds.SetCookies(sessionCookies)
+
+	result, err := ds.GetOrderHistory(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "2", gotPage)
+	assert.Len(t, result.Orders, 2)
+}
@@ -2,6 +2,7 @@
 package sainsburys
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -39,11 +40,12 @@ func (s *stringOrArray) UnmarshalJSON(data []byte) error {
 
 // apiProduct is the JSON structure returned by the Sainsbury's API.
 type apiProduct struct {
-	ProductUID  string `json:"product_uid"`
-	Name        string `json:"name"`
-	FullURL     string `json:"full_url"`
-	ImageURL    string `json:"image"`
-	IsAvailable bool   `json:"is_available"`
+	ProductUID  string   `json:"product_uid"`
+	Name        string   `json:"name"`
+	FullURL     string   `json:"full_url"`
+	ImageURL    string   `json:"image"`
+	IsAvailable bool     `json:"is_available"`
+	EANs        []string `json:"eans"`
 	RetailPrice struct {
 		Price float64 `json:"price"`
 	} `json:"retail_price"`
@@ -217,25 +219,72 @@ func (s *Datasource) apiRequest(
 	ctx context.Context,
 	apiURL string,
 ) (io.ReadCloser, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	resp, err := s.apiDo(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, apiURL)
+	}
+	return resp.Body, nil
+}
+
+// apiDo sends an API request with the session's cookies and auth token.
+// A non-nil body is sent as JSON.
+func (s *Datasource) apiDo(
+	ctx context.Context,
+	method, apiURL string,
+	body io.Reader,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
 	if err != nil {
 		return nil, err
 	}
 	scraper.SetBrowserHeaders(req)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Referer", baseURL+"/shop/gb/groceries")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	for _, c := range s.cookies {
 		req.AddCookie(c)
 		if strings.HasPrefix(c.Name, "WC_AUTHENTICATION_") {
 			req.Header.Set("wcauthtoken", c.Value)
 		}
 	}
+	return s.httpClient.Do(req)
+}
+
+// sessionRequest sends an API request for the logged-in account, such as
+// basket or order requests. Requests without a session are refused and
+// 401/403 responses report an expired session.
+func (s *Datasource) sessionRequest(
+	ctx context.Context, method, path string, payload any,
+) (io.ReadCloser, error) {
+	if len(s.cookies) == 0 {
+		return nil, fmt.Errorf("requires a logged-in session")
+	}
 
-	resp, err := s.httpClient.Do(req)
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	apiURL := s.apiBase + path
+	resp, err := s.apiDo(ctx, method, apiURL, body)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != http.StatusOK {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: HTTP %d from %s", datasource.ErrSessionExpired, resp.StatusCode, apiURL)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
 		_ = resp.Body.Close()
 		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, apiURL)
 	}
@@ -257,6 +306,9 @@ func convertProduct(ap apiProduct) datasource.Product {
 	if ap.FullURL != "" {
 		p.URL = baseURL + ap.FullURL
 	}
+	if len(ap.EANs) > 0 {
+		p.GTIN = ap.EANs[0]
+	}
 	if ap.UnitPrice.Price > 0 {
 		p.PricePerUnit = fmt.Sprintf("%.1fp/%s",
 			ap.UnitPrice.Price*100, ap.UnitPrice.Measure)
@@ -277,5 +329,6 @@ func convertProduct(ap apiProduct) datasource.Product {
 			PortionSize: ap.Nutrition.PortionSize,
 		}
 	}
+	p.Normalise()
 	return p
 }
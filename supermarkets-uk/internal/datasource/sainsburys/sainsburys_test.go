@@ -36,6 +36,8 @@ func TestSearchProducts(t *testing.T) {
 	assert.Equal(t, datasource.Sainsburys, p.Supermarket)
 	assert.Equal(t, "7878921", p.ID)
 	assert.True(t, *p.Available, "is_available=true products should be available")
+	assert.Equal(t, "5000128104517", p.GTIN)
+	assert.Empty(t, products[1].GTIN)
 	assert.Equal(t, "Nectar Price £1.70", products[1].Promotion)
 }
 
@@ -55,6 +57,7 @@ func TestGetProductDetails(t *testing.T) {
 
 	assert.Equal(t, "Sainsbury's British Semi Skimmed Milk 2.27L", p.Name)
 	assert.InDelta(t, 1.45, p.Price, 0.001)
+	assert.Equal(t, "5000128104517", p.GTIN)
 	assert.NotEmpty(t, p.Description)
 	assert.Contains(t, p.Ingredients, "Milk")
 	require.NotNil(t, p.Nutrition)
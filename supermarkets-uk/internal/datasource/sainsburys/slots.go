@@ -0,0 +1,78 @@
+package sainsburys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+type apiSlotsResponse struct {
+	Slots []apiSlot `json:"slots"`
+}
+
+type apiSlot struct {
+	SlotID         string  `json:"slot_id"`
+	StartTime      string  `json:"start_time"`
+	EndTime        string  `json:"end_time"`
+	Price          float64 `json:"price"`
+	Status         string  `json:"status"`
+	FulfilmentType string  `json:"fulfilment_type"`
+	IsBooked       bool    `json:"is_booked"`
+}
+
+// GetSlots retrieves delivery and click-and-collect slots for the
+// logged-in account's address and store.
+func (s *Datasource) GetSlots(
+	ctx context.Context, from, to time.Time,
+) ([]datasource.Slot, error) {
+	path := "/slot/v1/slots?" + url.Values{
+		"start_date": {from.Format(datasource.OrderDateLayout)},
+		"end_date":   {to.Format(datasource.OrderDateLayout)},
+	}.Encode()
+
+	body, err := s.sessionRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sainsburys slots: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+
+	slots, err := ParseSlots(body)
+	if err != nil {
+		return nil, err
+	}
+	return datasource.SlotsBetween(slots, from, to), nil
+}
+
+// ParseSlots parses a Sainsbury's slot API response. Slots with
+// unparseable times are skipped.
+func ParseSlots(r io.Reader) ([]datasource.Slot, error) {
+	var resp apiSlotsResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("sainsburys: decode slots: %w", err)
+	}
+
+	slots := make([]datasource.Slot, 0, len(resp.Slots))
+	for _, as := range resp.Slots {
+		start, err1 := time.Parse(time.RFC3339, as.StartTime)
+		end, err2 := time.Parse(time.RFC3339, as.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		method := datasource.SlotDelivery
+		if as.FulfilmentType == "CLICK_AND_COLLECT" {
+			method = datasource.SlotCollection
+		}
+		slot := datasource.NewSlot(as.SlotID, datasource.Sainsburys, method, start, end,
+			as.Price, strings.EqualFold(as.Status, "AVAILABLE"))
+		slot.Booked = as.IsBooked
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
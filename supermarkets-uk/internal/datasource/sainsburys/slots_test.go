@@ -0,0 +1,57 @@
+package sainsburys_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/sainsburys"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseSlots(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/sainsburys_slots.json")
+	slots, err := sainsburys.ParseSlots(f)
+	require.NoError(t, err)
+	require.Len(t, slots, 3)
+
+	s := slots[0]
+	assert.Equal(t, "2026-03-07T09:00:00Z_DELIVERY", s.ID)
+	assert.Equal(t, datasource.Sainsburys, s.Supermarket)
+	assert.Equal(t, datasource.SlotDelivery, s.Method)
+	assert.Equal(t, "Sat 7 Mar, 9:00am–10:00am", s.Description)
+	assert.InDelta(t, 4.50, s.Price, 0.001)
+	assert.True(t, s.Available)
+
+	assert.False(t, slots[1].Available)
+	assert.Equal(t, datasource.SlotCollection, slots[2].Method)
+	assert.True(t, slots[2].Booked)
+}
+
+func TestGetSlots(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/sainsburys_slots.json")
+	require.NoError(t, err)
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/slot/v1/slots", r.URL.Path)
+		query = r.URL.RawQuery
+		_, _ = w.Write(fixture)
+	}))
+	t.Cleanup(srv.Close)
+
+	ds := sainsburys.NewDatasource(sainsburys.Config{BaseURL: srv.URL}, srv.Client())
+	ds.SetCookies(sessionCookies)
+	from := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	slots, err := ds.GetSlots(t.Context(), from, from.AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	assert.Equal(t, "end_date=2026-03-08&start_date=2026-03-07", query)
+	// The collection slot on the 8th is outside the range.
+	assert.Len(t, slots, 2)
+}
@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// OrderSelectors configures selectors for parsing order summary cards on a
+// browser-rendered order history page.
+type OrderSelectors struct {
+	// Root is present on the order history page whether or not there are
+	// orders; its absence means the page was not the order history,
+	// usually because of a login redirect.
+	Root      ElemSel
+	Container ElemSel
+	ID        ElemSel
+	Date      ElemSel
+	Status    ElemSel
+	Total     ElemSel
+	Items     ElemSel // text containing the item count, e.g. "32 items"
+	Slot      ElemSel // optional
+	Method    ElemSel // optional
+}
+
+var (
+	orderNumberRe = regexp.MustCompile(`\d{4,}`)
+	orderDateRe   = regexp.MustCompile(`(\d{1,2})(?:st|nd|rd|th)?\s+([A-Za-z]{3})[A-Za-z]*\s+(\d{4})`)
+	firstNumberRe = regexp.MustCompile(`\d+`)
+)
+
+// ParseOrderCards parses an order history page into orders, one per
+// container. Dates like "Saturday 7th March 2026" are normalised to
+// datasource.OrderDateLayout.
+func ParseOrderCards(r io.Reader, sel OrderSelectors, sid datasource.SupermarketID) ([]datasource.Order, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: parse orders HTML: %w", sid, err)
+	}
+	if FindElement(doc, sel.Root) == nil {
+		return nil, fmt.Errorf("%s: %w: no order history on page", sid, datasource.ErrSessionExpired)
+	}
+
+	orders := []datasource.Order{}
+	WalkTree(doc, func(n *html.Node) {
+		if !sel.Container.Matches(n) {
+			return
+		}
+		if o, ok := extractOrder(n, sel, sid); ok {
+			orders = append(orders, o)
+		}
+	})
+	return orders, nil
+}
+
+func extractOrder(n *html.Node, sel OrderSelectors, sid datasource.SupermarketID) (datasource.Order, bool) {
+	// A zero selector matches nothing, so optional fields are left empty.
+	text := func(s ElemSel) string {
+		if el := FindElement(n, s); el != nil {
+			return TextContent(el)
+		}
+		return ""
+	}
+
+	o := datasource.Order{
+		Supermarket:    sid,
+		ID:             orderNumberRe.FindString(text(sel.ID)),
+		Date:           ParseOrderDate(text(sel.Date)),
+		Status:         text(sel.Status),
+		TotalPrice:     ParsePrice(text(sel.Total)),
+		DeliverySlot:   text(sel.Slot),
+		ShoppingMethod: text(sel.Method),
+		Currency:       "GBP",
+	}
+	o.TotalItems, _ = strconv.Atoi(firstNumberRe.FindString(text(sel.Items)))
+	if o.ID == "" {
+		if el := FindElement(n, sel.ID); el != nil {
+			o.ID = LastPathSegment(GetAttr(el, "href"))
+		}
+	}
+	return o, o.ID != ""
+}
+
+// ParseOrderDate extracts a date like "7 March 2026" or "Sat 7th Mar 2026"
+// from s and formats it with datasource.OrderDateLayout. It returns "" if
+// no date is found.
+func ParseOrderDate(s string) string {
+	m := orderDateRe.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	t, err := time.Parse("2 Jan 2006", m[1]+" "+strings.ToUpper(m[2][:1])+strings.ToLower(m[2][1:])+" "+m[3])
+	if err != nil {
+		return ""
+	}
+	return t.Format(datasource.OrderDateLayout)
+}
@@ -0,0 +1,22 @@
+package scraper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+func TestParseOrderDate(t *testing.T) {
+	tests := map[string]string{
+		"Placed on Thursday 5th March 2026": "2026-03-05",
+		"Monday 2 March 2026":               "2026-03-02",
+		"Sat 21st Feb 2026":                 "2026-02-21",
+		"Delivered 1 sept 2025":             "2025-09-01",
+		"Order 12345":                       "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, scraper.ParseOrderDate(input), input)
+	}
+}
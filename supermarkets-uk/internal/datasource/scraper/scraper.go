@@ -135,6 +135,7 @@ func ParseProductFields(doc *html.Node, sel ProductSelectors, id datasource.Supe
 		}
 		applyMatchers(n, p, matchers)
 	})
+	p.Normalise()
 
 	return p
 }
@@ -191,6 +192,7 @@ func ExtractProduct(
 	if p.Name == "" {
 		return datasource.Product{}, false
 	}
+	p.Normalise()
 	return p, true
 }
 
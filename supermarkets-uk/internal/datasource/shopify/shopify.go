@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -22,6 +23,7 @@ type Config struct {
 	Name        string
 	Description string
 	BaseURL     string // e.g. "https://hiyou.co"
+	Currency    string // ISO 4217 code; defaults to "GBP"
 }
 
 // Datasource implements datasource.ProductSource for Shopify stores.
@@ -32,6 +34,9 @@ type Datasource struct {
 
 // NewDatasource creates a Datasource with the given HTTP client.
 func NewDatasource(cfg Config, httpClient *http.Client) *Datasource {
+	if cfg.Currency == "" {
+		cfg.Currency = "GBP"
+	}
 	return &Datasource{cfg: cfg, httpClient: httpClient}
 }
 
@@ -71,24 +76,9 @@ func (d *Datasource) SearchProducts(ctx context.Context, query string) ([]dataso
 		"resources[limit]": {"10"},
 	}.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating search request: %w", err)
-	}
-
-	resp, err := d.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("search request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search request returned status %d", resp.StatusCode)
-	}
-
 	var sr searchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
-		return nil, fmt.Errorf("decoding search response: %w", err)
+	if err := d.getJSON(ctx, u, "search", &sr); err != nil {
+		return nil, err
 	}
 
 	products := make([]datasource.Product, 0, len(sr.Resources.Results.Products))
@@ -108,16 +98,18 @@ func (d *Datasource) convertSearchProduct(p searchProduct) datasource.Product {
 	if productURL != "" && !strings.HasPrefix(productURL, "http") {
 		productURL = d.cfg.BaseURL + productURL
 	}
-	return datasource.Product{
+	product := datasource.Product{
 		ID:          p.Handle,
 		Supermarket: d.cfg.ID,
 		Name:        p.Title,
 		Price:       price,
-		Currency:    "GBP",
+		Currency:    d.cfg.Currency,
 		ImageURL:    imageURL,
 		URL:         productURL,
 		Available:   datasource.BoolPtr(p.Available),
 	}
+	product.Normalise()
+	return product
 }
 
 // productResponse is the top-level Shopify product detail response.
@@ -140,6 +132,7 @@ type variant struct {
 	Price      string  `json:"price"`
 	Weight     float64 `json:"weight"`
 	WeightUnit string  `json:"weight_unit"`
+	Barcode    string  `json:"barcode"`
 }
 
 type image struct {
@@ -150,24 +143,9 @@ type image struct {
 func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*datasource.Product, error) {
 	u := d.cfg.BaseURL + "/products/" + url.PathEscape(productID) + ".json"
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating product request: %w", err)
-	}
-
-	resp, err := d.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("product request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("product request returned status %d", resp.StatusCode)
-	}
-
 	var pr productResponse
-	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
-		return nil, fmt.Errorf("decoding product response: %w", err)
+	if err := d.getJSON(ctx, u, "product", &pr); err != nil {
+		return nil, err
 	}
 
 	p := pr.Product
@@ -189,19 +167,37 @@ func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*
 		Supermarket: d.cfg.ID,
 		Name:        p.Title,
 		Price:       price,
-		Currency:    "GBP",
+		Currency:    d.cfg.Currency,
 		ImageURL:    imageURL,
 		URL:         d.cfg.BaseURL + "/products/" + p.Handle,
+		GTIN:        variantGTIN(p.Variants),
 		// Shopify product detail API does not expose availability.
 		Weight: weight,
 	}
 	if p.BodyHTML != "" {
 		result.Description = stripHTML(p.BodyHTML)
 	}
+	result.Normalise()
 
 	return result, nil
 }
 
+// gtinRe matches the lengths of GTIN: GTIN-8, UPC-A, EAN-13 and GTIN-14.
+var gtinRe = regexp.MustCompile(`^(?:\d{8}|\d{12,14})$`)
+
+// variantGTIN returns the first variant's barcode when it is a GTIN. Stores
+// fill the barcode field freely, so SKUs and other codes are ignored.
+func variantGTIN(variants []variant) string {
+	if len(variants) == 0 {
+		return ""
+	}
+	barcode := strings.TrimSpace(variants[0].Barcode)
+	if !gtinRe.MatchString(barcode) {
+		return ""
+	}
+	return barcode
+}
+
 // stripHTML parses an HTML fragment and returns its text content.
 func stripHTML(s string) string {
 	doc, err := html.Parse(strings.NewReader(s))
@@ -226,24 +222,9 @@ type collection struct {
 func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Category, error) {
 	u := d.cfg.BaseURL + "/collections.json"
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating collections request: %w", err)
-	}
-
-	resp, err := d.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("collections request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("collections request returned status %d", resp.StatusCode)
-	}
-
 	var cr collectionsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
-		return nil, fmt.Errorf("decoding collections response: %w", err)
+	if err := d.getJSON(ctx, u, "collections", &cr); err != nil {
+		return nil, err
 	}
 
 	categories := make([]datasource.Category, 0, len(cr.Collections))
@@ -257,3 +238,27 @@ func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Categor
 	}
 	return categories, nil
 }
+
+// getJSON fetches u and decodes the JSON response into v. what names the
+// request in errors, e.g. "search".
+func (d *Datasource) getJSON(ctx context.Context, u, what string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("creating %s request: %w", what, err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request: %w", what, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request returned status %d", what, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s response: %w", what, err)
+	}
+	return nil
+}
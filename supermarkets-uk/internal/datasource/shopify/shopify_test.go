@@ -58,7 +58,10 @@ func TestGetProductDetails(t *testing.T) {
 	assert.Equal(t, "Golden Bowl Thai Hom Mali Rice 1kg", p.Name)
 	assert.InDelta(t, 2.85, p.Price, 0.001)
 	assert.Equal(t, "1kg", p.Weight)
+	require.NotNil(t, p.UnitPrice)
+	assert.Equal(t, datasource.UnitPrice{Price: 2.85, Per: "kg"}, *p.UnitPrice)
 	assert.Equal(t, "golden-bowl-thai-hom-mali-rice-1kg", p.ID)
+	assert.Equal(t, "8853002300015", p.GTIN)
 	assert.NotEmpty(t, p.ImageURL)
 	assert.NotEmpty(t, p.Description)
 	assert.Contains(t, p.Description, "Thai Hom Mali")
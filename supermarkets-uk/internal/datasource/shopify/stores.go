@@ -0,0 +1,162 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// StoreConfig is a user-defined Shopify store as written in the stores file.
+type StoreConfig struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Domain      string `json:"domain"`
+	Currency    string `json:"currency,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// storesFile is the layout of the stores file.
+type storesFile struct {
+	Stores []StoreConfig `json:"stores"`
+}
+
+var (
+	storeIDRe  = regexp.MustCompile(`^[a-z0-9]+$`)
+	currencyRe = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// DefaultStoresPath returns the path of the Shopify stores file: the
+// SHOPIFY_STORES_FILE environment variable if set, otherwise
+// shopify-stores.json in the user config directory.
+func DefaultStoresPath() (string, error) {
+	if path := os.Getenv("SHOPIFY_STORES_FILE"); path != "" {
+		return path, nil
+	}
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("get config dir: %w", err)
+	}
+	return filepath.Join(cfgDir, "supermarkets-uk-mcp", "shopify-stores.json"), nil
+}
+
+// LoadStores reads user-defined Shopify stores from the JSON file at path.
+// A missing file means no stores. Every store is checked, and the errors
+// for all invalid stores are returned together.
+func LoadStores(path string) ([]Config, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path is chosen by the user.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read shopify stores: %w", err)
+	}
+	return ParseStores(data)
+}
+
+// ParseStores parses and checks the contents of a stores file. IDs must be
+// lowercase letters and digits, unique, and not clash with a built-in
+// supermarket.
+func ParseStores(data []byte) ([]Config, error) {
+	var f storesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse shopify stores: %w", err)
+	}
+
+	var (
+		configs []Config
+		errs    []error
+	)
+	seen := make(map[datasource.SupermarketID]bool)
+	for i, sc := range f.Stores {
+		cfg, err := sc.config()
+		if err == nil && seen[cfg.ID] {
+			err = fmt.Errorf("duplicate id %q", cfg.ID)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shopify store %d: %w", i+1, err))
+			continue
+		}
+		seen[cfg.ID] = true
+		configs = append(configs, cfg)
+	}
+	return configs, errors.Join(errs...)
+}
+
+// config checks a store entry and converts it to a datasource Config.
+func (sc StoreConfig) config() (Config, error) {
+	id := datasource.SupermarketID(sc.ID)
+	switch {
+	case !storeIDRe.MatchString(sc.ID):
+		return Config{}, fmt.Errorf("id %q must be lowercase letters and digits", sc.ID)
+	case slices.Contains(datasource.AllSupermarkets, id):
+		return Config{}, fmt.Errorf("id %q is a built-in supermarket", sc.ID)
+	case strings.TrimSpace(sc.Name) == "":
+		return Config{}, fmt.Errorf("store %q has no name", sc.ID)
+	case sc.Currency != "" && !currencyRe.MatchString(sc.Currency):
+		return Config{}, fmt.Errorf("store %q currency %q must be an ISO 4217 code such as GBP", sc.ID, sc.Currency)
+	}
+
+	baseURL, err := storeBaseURL(sc.Domain)
+	if err != nil {
+		return Config{}, fmt.Errorf("store %q: %w", sc.ID, err)
+	}
+	return Config{
+		ID:          id,
+		Name:        strings.TrimSpace(sc.Name),
+		Description: sc.Description,
+		BaseURL:     baseURL,
+		Currency:    sc.Currency,
+	}, nil
+}
+
+// storeBaseURL turns a domain such as "shop.example.co.uk" or
+// "https://shop.example.co.uk/" into a base URL.
+func storeBaseURL(domain string) (string, error) {
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return "", fmt.Errorf("no domain")
+	}
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	u, err := url.Parse(domain)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", fmt.Errorf("invalid domain %q", domain)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// Validate checks that the store's predictive search endpoint responds
+// with the response shape SearchProducts expects, so a misconfigured
+// domain or a store that isn't on Shopify can be spotted before it's used.
+func (d *Datasource) Validate(ctx context.Context) error {
+	u := d.cfg.BaseURL + "/search/suggest.json?" + url.Values{
+		"q":                {"a"},
+		"resources[type]":  {"product"},
+		"resources[limit]": {"1"},
+	}.Encode()
+
+	var probe struct {
+		Resources *struct {
+			Results *struct {
+				Products *[]searchProduct `json:"products"`
+			} `json:"results"`
+		} `json:"resources"`
+	}
+	if err := d.getJSON(ctx, u, "search", &probe); err != nil {
+		return err
+	}
+	if probe.Resources == nil || probe.Resources.Results == nil || probe.Resources.Results.Products == nil {
+		return fmt.Errorf("%s does not look like a Shopify predictive search endpoint", d.cfg.BaseURL)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package shopify_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/shopify"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/testutil"
+)
+
+func TestParseStores(t *testing.T) {
+	configs, err := shopify.ParseStores([]byte(`{"stores": [
+		{"id": "seoulplaza", "name": "Seoul Plaza", "domain": "seoulplaza.co.uk",
+		 "description": "Korean grocery"},
+		{"id": "eurofoods", "name": "Euro Foods", "domain": "https://eurofoods.example.com/", "currency": "EUR"}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+
+	assert.Equal(t, shopify.Config{
+		ID:          "seoulplaza",
+		Name:        "Seoul Plaza",
+		Description: "Korean grocery",
+		BaseURL:     "https://seoulplaza.co.uk",
+	}, configs[0])
+	assert.Equal(t, "https://eurofoods.example.com", configs[1].BaseURL)
+	assert.Equal(t, "EUR", configs[1].Currency)
+}
+
+func TestParseStoresInvalid(t *testing.T) {
+	configs, err := shopify.ParseStores([]byte(`{"stores": [
+		{"id": "good", "name": "Good", "domain": "good.example.com"},
+		{"id": "tesco", "name": "Not Tesco", "domain": "tesco.example.com"},
+		{"id": "Bad ID", "name": "Bad", "domain": "bad.example.com"},
+		{"id": "noname", "domain": "noname.example.com"},
+		{"id": "nodomain", "name": "No Domain"},
+		{"id": "money", "name": "Money", "domain": "money.example.com", "currency": "pounds"},
+		{"id": "good", "name": "Good Again", "domain": "again.example.com"}
+	]}`))
+	require.Len(t, configs, 1, "valid stores are kept")
+	assert.Equal(t, datasource.SupermarketID("good"), configs[0].ID)
+
+	require.Error(t, err)
+	for _, want := range []string{"built-in", "lowercase", "no name", "no domain", "ISO 4217", "duplicate"} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadStores(t *testing.T) {
+	configs, err := shopify.LoadStores(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+
+	path := filepath.Join(t.TempDir(), "stores.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"stores": [{"id": "a", "name": "A", "domain": "a.example"}]}`), 0o600))
+	configs, err = shopify.LoadStores(path)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+	_, err = shopify.LoadStores(path)
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	srv := testutil.JSONFixtureServer(t, "testdata/search.json")
+	ds := shopify.NewDatasource(testConfig(srv.URL), srv.Client())
+	assert.NoError(t, ds.Validate(t.Context()))
+
+	// A store that answers with some other JSON is not a Shopify store.
+	srv = testutil.JSONFixtureServer(t, "testdata/collections.json")
+	ds = shopify.NewDatasource(testConfig(srv.URL), srv.Client())
+	assert.ErrorContains(t, ds.Validate(t.Context()), "predictive search")
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	ds = shopify.NewDatasource(testConfig(notFound.URL), notFound.Client())
+	assert.ErrorContains(t, ds.Validate(t.Context()), "status 404")
+}
@@ -0,0 +1,85 @@
+package datasource
+
+import (
+	"sort"
+	"time"
+)
+
+// Slot methods.
+const (
+	SlotDelivery   = "delivery"
+	SlotCollection = "collection"
+)
+
+// Slot is a delivery or click-and-collect slot.
+type Slot struct {
+	ID          string        `json:"id"`
+	Supermarket SupermarketID `json:"supermarket"`
+	// Method is SlotDelivery or SlotCollection.
+	Method string    `json:"method"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	// Description is the slot in readable form, e.g. "Sat 7 Mar, 9:00am–10:00am".
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	Available   bool    `json:"available"`
+	// Booked is true for the slot currently reserved by the account.
+	Booked bool `json:"booked,omitempty"`
+}
+
+// SlotResult holds one supermarket's slots, or the error fetching them.
+type SlotResult struct {
+	Supermarket SupermarketID `json:"supermarket"`
+	Slots       []Slot        `json:"slots"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// NewSlot builds a slot, filling in its description.
+func NewSlot(
+	id string, sid SupermarketID, method string, start, end time.Time, price float64, available bool,
+) Slot {
+	return Slot{
+		ID:          id,
+		Supermarket: sid,
+		Method:      method,
+		Start:       start,
+		End:         end,
+		Description: FormatDeliverySlot(start, end),
+		Price:       price,
+		Available:   available,
+	}
+}
+
+// SlotsBetween returns the slots starting in [from, to), earliest first.
+func SlotsBetween(slots []Slot, from, to time.Time) []Slot {
+	kept := []Slot{}
+	for _, s := range slots {
+		if !s.Start.Before(from) && s.Start.Before(to) {
+			kept = append(kept, s)
+		}
+	}
+	sortSlots(kept)
+	return kept
+}
+
+// MergeSlots combines the slots from several supermarkets' results,
+// earliest first.
+func MergeSlots(results []SlotResult) []Slot {
+	slots := []Slot{}
+	for _, r := range results {
+		slots = append(slots, r.Slots...)
+	}
+	sortSlots(slots)
+	return slots
+}
+
+// sortSlots orders slots by start time, cheapest first among those
+// starting together.
+func sortSlots(slots []Slot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		if !slots[i].Start.Equal(slots[j].Start) {
+			return slots[i].Start.Before(slots[j].Start)
+		}
+		return slots[i].Price < slots[j].Price
+	})
+}
@@ -0,0 +1,49 @@
+package datasource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+func slotAt(sid datasource.SupermarketID, hour int, price float64) datasource.Slot {
+	start := time.Date(2026, 3, 7, hour, 0, 0, 0, time.UTC)
+	return datasource.NewSlot("", sid, datasource.SlotDelivery, start, start.Add(time.Hour), price, true)
+}
+
+func TestSlotsBetween(t *testing.T) {
+	from := time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)
+	slots := datasource.SlotsBetween([]datasource.Slot{
+		slotAt(datasource.Tesco, 12, 3),
+		slotAt(datasource.Tesco, 8, 3),
+		slotAt(datasource.Tesco, 9, 5),
+		slotAt(datasource.Tesco, 14, 3),
+	}, from, from.Add(5*time.Hour))
+
+	require.Len(t, slots, 2)
+	assert.Equal(t, 9, slots[0].Start.Hour())
+	assert.Equal(t, "Sat 7 Mar, 9:00am–10:00am", slots[0].Description)
+	assert.Equal(t, 12, slots[1].Start.Hour())
+}
+
+func TestMergeSlots(t *testing.T) {
+	slots := datasource.MergeSlots([]datasource.SlotResult{
+		{Supermarket: datasource.Tesco, Slots: []datasource.Slot{
+			slotAt(datasource.Tesco, 9, 4.5),
+			slotAt(datasource.Tesco, 18, 3),
+		}},
+		{Supermarket: datasource.Ocado, Error: "session expired"},
+		{Supermarket: datasource.Sainsburys, Slots: []datasource.Slot{
+			slotAt(datasource.Sainsburys, 9, 4),
+		}},
+	})
+
+	require.Len(t, slots, 3)
+	assert.Equal(t, datasource.Sainsburys, slots[0].Supermarket)
+	assert.Equal(t, datasource.Tesco, slots[1].Supermarket)
+	assert.Equal(t, 18, slots[2].Start.Hour())
+}
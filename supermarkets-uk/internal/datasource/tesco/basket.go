@@ -305,26 +305,7 @@ func resolveBasketItem(
 func (d *Datasource) UpdateBasketItem(
 	ctx context.Context, productID string, quantity int,
 ) (*datasource.Basket, error) {
-	body, token, err := d.browser.FetchAndReadCookie(
-		ctx, trolleyURL, d.cookies, "OAuth.AccessToken", trolleyWaitSelector,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("tesco basket fetch for update: %w", err)
-	}
-	defer body.Close() //nolint:errcheck
-
-	if token == "" {
-		return nil, fmt.Errorf(
-			"tesco: no access token in browser cookies — session may have expired",
-		)
-	}
-
-	data, err := io.ReadAll(body)
-	if err != nil {
-		return nil, fmt.Errorf("tesco: read trolley page: %w", err)
-	}
-
-	cache, err := extractApolloCache(data)
+	token, cache, err := d.accessToken(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -374,6 +355,38 @@ func (d *Datasource) UpdateBasketItem(
 	return parseGraphQLBasket(resp)
 }
 
+// accessToken loads the trolley page, which refreshes the OAuth token, and
+// returns the fresh access token from the browser cookies along with the
+// page's Apollo cache.
+func (d *Datasource) accessToken(
+	ctx context.Context,
+) (string, map[string]json.RawMessage, error) {
+	body, token, err := d.browser.FetchAndReadCookie(
+		ctx, trolleyURL, d.cookies, "OAuth.AccessToken", trolleyWaitSelector,
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("tesco trolley fetch for access token: %w", err)
+	}
+	defer body.Close() //nolint:errcheck
+
+	if token == "" {
+		return "", nil, fmt.Errorf(
+			"tesco: no access token in browser cookies — session may have expired",
+		)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", nil, fmt.Errorf("tesco: read trolley page: %w", err)
+	}
+
+	cache, err := extractApolloCache(data)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, cache, nil
+}
+
 func extractOrderID(
 	cache map[string]json.RawMessage,
 ) (string, error) {
@@ -314,12 +314,7 @@ func formatSlot(s *cachedSlot) string {
 	if err1 != nil || err2 != nil {
 		return ""
 	}
-	return fmt.Sprintf(
-		"%s, %s–%s",
-		start.Format("Mon 2 Jan"),
-		start.Format("3:04pm"),
-		end.Format("3:04pm"),
-	)
+	return datasource.FormatDeliverySlot(start, end)
 }
 
 func formatSlotDate(s *cachedSlot) string {
@@ -327,7 +322,7 @@ func formatSlotDate(s *cachedSlot) string {
 	if err != nil {
 		return ""
 	}
-	return t.Format("2006-01-02")
+	return t.Format(datasource.OrderDateLayout)
 }
 
 func resolveItem(
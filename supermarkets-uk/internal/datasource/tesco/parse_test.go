@@ -15,6 +15,11 @@ func TestParseProductPage(t *testing.T) {
 	assert.Equal(t, "Tesco British Semi Skimmed Milk 2.272L, 4 Pints", p.Name)
 	assert.InDelta(t, 1.65, p.Price, 0.001)
 	assert.Equal(t, "£0.73/litre", p.PricePerUnit)
+	require.NotNil(t, p.UnitPrice)
+	assert.Equal(t, "litre", p.UnitPrice.Per)
+	assert.InDelta(t, 0.73, p.UnitPrice.Price, 0.001)
+	require.NotNil(t, p.Quantity)
+	assert.InDelta(t, 2272, p.Quantity.Total(), 0.001)
 	assert.NotEmpty(t, p.Description)
 	assert.Contains(t, p.Ingredients, "Milk")
 	require.NotNil(t, p.Nutrition)
@@ -22,3 +27,14 @@ func TestParseProductPage(t *testing.T) {
 	assert.NotEmpty(t, p.Nutrition.Per100g["Fat"])
 	assert.NotEmpty(t, p.Nutrition.PerPortion["Energy"])
 }
+
+func TestParseProductDetails(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/tesco_product.html")
+	p, err := ParseProductDetails(f, "254656543")
+	require.NoError(t, err)
+
+	assert.Equal(t, "254656543", p.ID)
+	assert.Equal(t, "Tesco British Semi Skimmed Milk 2.272L, 4 Pints", p.Name)
+	assert.Equal(t, "05000436589563", p.GTIN)
+	assert.Equal(t, "TESCO", p.Brand)
+}
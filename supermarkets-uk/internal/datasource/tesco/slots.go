@@ -0,0 +1,167 @@
+package tesco
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+const slotFields = `
+    id
+    start
+    end
+    charge
+    status
+    shoppingMethod`
+
+const slotsQuery = `query DeliverySlots($start: String!, $end: String!, $shoppingMethod: ShoppingMethodType!) {
+  slots(start: $start, end: $end, shoppingMethod: $shoppingMethod) {` + slotFields + `
+  }
+}`
+
+const bookSlotMutation = `mutation BookSlot($slotId: ID!, $orderId: ID) {
+  bookSlot(slotId: $slotId, orderId: $orderId) {` + slotFields + `
+  }
+}`
+
+// shoppingMethods maps Tesco shopping methods to slot methods.
+var shoppingMethods = map[string]string{
+	"DELIVERY":   datasource.SlotDelivery,
+	"COLLECTION": datasource.SlotCollection,
+}
+
+type graphQLSlot struct {
+	ID             string  `json:"id"`
+	Start          string  `json:"start"`
+	End            string  `json:"end"`
+	Charge         float64 `json:"charge"`
+	Status         string  `json:"status"`
+	ShoppingMethod string  `json:"shoppingMethod"`
+}
+
+// GetSlots retrieves delivery and click-and-collect slots from the GraphQL
+// gateway.
+func (d *Datasource) GetSlots(
+	ctx context.Context, from, to time.Time,
+) ([]datasource.Slot, error) {
+	token, _, err := d.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type slotVars struct {
+		Start          string `json:"start"`
+		End            string `json:"end"`
+		ShoppingMethod string `json:"shoppingMethod"`
+	}
+
+	var slots []datasource.Slot
+	for _, method := range []string{"DELIVERY", "COLLECTION"} {
+		req := graphQLRequest{
+			OperationName: "DeliverySlots",
+			Query:         slotsQuery,
+			Variables: slotVars{
+				Start:          from.Format(time.RFC3339),
+				End:            to.Format(time.RFC3339),
+				ShoppingMethod: method,
+			},
+		}
+		req.Extensions.MFEName = "mfe-slots"
+
+		resp, err := d.graphQL(ctx, token, req)
+		if err != nil {
+			return nil, fmt.Errorf("tesco: get %s slots: %w", strings.ToLower(method), err)
+		}
+		found, err := ParseSlots(bytes.NewReader(resp))
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, found...)
+	}
+	return datasource.SlotsBetween(slots, from, to), nil
+}
+
+// BookSlot books a delivery or click-and-collect slot for the current
+// trolley.
+func (d *Datasource) BookSlot(ctx context.Context, slotID string) (*datasource.Slot, error) {
+	token, cache, err := d.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orderID, err := extractOrderID(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	type bookVars struct {
+		SlotID  string `json:"slotId"`
+		OrderID string `json:"orderId"`
+	}
+	req := graphQLRequest{
+		OperationName: "BookSlot",
+		Query:         bookSlotMutation,
+		Variables:     bookVars{SlotID: slotID, OrderID: orderID},
+	}
+	req.Extensions.MFEName = "mfe-slots"
+
+	resp, err := d.graphQL(ctx, token, req)
+	if err != nil {
+		return nil, fmt.Errorf("tesco: book slot: %w", err)
+	}
+
+	var data struct {
+		BookSlot *graphQLSlot `json:"bookSlot"`
+	}
+	if err := json.Unmarshal(resp, &data); err != nil {
+		return nil, fmt.Errorf("tesco: parse book slot response: %w", err)
+	}
+	if data.BookSlot == nil {
+		return nil, fmt.Errorf("tesco: slot %s was not booked", slotID)
+	}
+	slot, ok := convertSlot(*data.BookSlot)
+	if !ok {
+		return nil, fmt.Errorf("tesco: booked slot %s has invalid times", slotID)
+	}
+	return &slot, nil
+}
+
+// ParseSlots parses the data of a Tesco DeliverySlots GraphQL response.
+// Slots with unparseable times are skipped.
+func ParseSlots(r io.Reader) ([]datasource.Slot, error) {
+	var data struct {
+		Slots []graphQLSlot `json:"slots"`
+	}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("tesco: parse slots response: %w", err)
+	}
+
+	slots := make([]datasource.Slot, 0, len(data.Slots))
+	for _, gs := range data.Slots {
+		if slot, ok := convertSlot(gs); ok {
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
+
+func convertSlot(gs graphQLSlot) (datasource.Slot, bool) {
+	start, err1 := time.Parse(time.RFC3339, gs.Start)
+	end, err2 := time.Parse(time.RFC3339, gs.End)
+	if err1 != nil || err2 != nil {
+		return datasource.Slot{}, false
+	}
+	method, ok := shoppingMethods[strings.ToUpper(gs.ShoppingMethod)]
+	if !ok {
+		method = datasource.SlotDelivery
+	}
+	status := strings.ToUpper(gs.Status)
+	slot := datasource.NewSlot(gs.ID, datasource.Tesco, method, start, end, gs.Charge, status == "AVAILABLE")
+	slot.Booked = status == "BOOKED"
+	return slot, true
+}
@@ -2,7 +2,9 @@
 package tesco
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -98,11 +100,10 @@ func (d *Datasource) GetProductDetails(ctx context.Context, productID string) (*
 	}
 	defer body.Close() //nolint:errcheck // Best-effort close.
 
-	p, err := ParseProductPage(body)
+	p, err := ParseProductDetails(body, productID)
 	if err != nil {
 		return nil, err
 	}
-	p.ID = productID
 	p.URL = baseURL + "/groceries/en-GB/products/" + url.PathEscape(productID)
 	return p, nil
 }
@@ -118,9 +119,69 @@ func (d *Datasource) BrowseCategories(ctx context.Context) ([]datasource.Categor
 	return scraper.ParseCategories(body, selectors)
 }
 
-// ParseSearchResults parses a Tesco search results page.
+// ParseSearchResults parses a Tesco search results page. Brands and GTINs
+// are taken from the page's Apollo cache when it is present.
 func ParseSearchResults(r io.Reader) ([]datasource.Product, error) {
-	return scraper.ParseSearchResults(r, selectors)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("tesco: read search page: %w", err)
+	}
+	products, err := scraper.ParseSearchResults(bytes.NewReader(data), selectors)
+	if err != nil {
+		return nil, err
+	}
+	if cache, err := extractApolloCache(data); err == nil {
+		addIdentifiers(products, cache)
+	}
+	return products, nil
+}
+
+// cachedIdentifiers holds the product identifiers from a ProductType entry
+// in the Apollo cache.
+type cachedIdentifiers struct {
+	GTIN      string `json:"gtin"`
+	BrandName string `json:"brandName"`
+}
+
+// addIdentifiers sets each product's brand and GTIN from its Apollo cache
+// entry, keyed by the same TPNC used as the product ID.
+func addIdentifiers(products []datasource.Product, cache map[string]json.RawMessage) {
+	for i := range products {
+		setIdentifiers(&products[i], cache)
+	}
+}
+
+func setIdentifiers(p *datasource.Product, cache map[string]json.RawMessage) {
+	raw, ok := cache["ProductType:"+p.ID]
+	if !ok {
+		return
+	}
+	var ids cachedIdentifiers
+	if json.Unmarshal(raw, &ids) != nil {
+		return
+	}
+	p.GTIN = ids.GTIN
+	p.Brand = ids.BrandName
+}
+
+// ParseProductDetails parses the Tesco product detail page for productID.
+// The page's Apollo cache also holds related products, so the brand and
+// GTIN are taken from the entry for productID. The returned Product does
+// not have URL set.
+func ParseProductDetails(r io.Reader, productID string) (*datasource.Product, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("tesco: read product page: %w", err)
+	}
+	p, err := ParseProductPage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	p.ID = productID
+	if cache, err := extractApolloCache(data); err == nil {
+		setIdentifiers(p, cache)
+	}
+	return p, nil
 }
 
 // ParseProductPage parses a Tesco product detail page.
@@ -44,6 +44,8 @@ func TestParseSearchResults_Unavailable(t *testing.T) {
 	p := products[0]
 	assert.Equal(t, "Tesco Japanese Style Chicken Sushi 135g", p.Name)
 	assert.False(t, *p.Available, "out-of-stock product should have Available=false")
+	assert.Equal(t, "05063445808049", p.GTIN)
+	assert.Equal(t, "TESCO", p.Brand)
 
 	// Second product should be available.
 	if len(products) > 1 {
@@ -214,3 +216,25 @@ func tescoWithCookies(t *testing.T) *tesco.Datasource {
 	ds.SetCookies(cookies)
 	return ds
 }
+
+func TestParseSlots(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/tesco_slots.json")
+	slots, err := tesco.ParseSlots(f)
+	require.NoError(t, err)
+	require.Len(t, slots, 4)
+
+	s := slots[0]
+	assert.Equal(t, "d-20260307-0900", s.ID)
+	assert.Equal(t, datasource.Tesco, s.Supermarket)
+	assert.Equal(t, datasource.SlotDelivery, s.Method)
+	assert.Equal(t, "Sat 7 Mar, 9:00am–10:00am", s.Description)
+	assert.InDelta(t, 4.50, s.Price, 0.001)
+	assert.True(t, s.Available)
+	assert.False(t, s.Booked)
+
+	assert.False(t, slots[1].Available)
+	assert.True(t, slots[2].Booked)
+	assert.False(t, slots[2].Available)
+	assert.Equal(t, datasource.SlotCollection, slots[3].Method)
+	assert.Zero(t, slots[3].Price)
+}
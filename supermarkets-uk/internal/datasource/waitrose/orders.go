@@ -0,0 +1,66 @@
+package waitrose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource/scraper"
+)
+
+const ordersURL = baseURL + "/ecom/my-account/orders"
+
+// ordersWaitSelector waits for the orders list, which renders even when
+// there are no past orders.
+const ordersWaitSelector = `[data-testid="my-orders"]`
+
+var orderSelectors = scraper.OrderSelectors{
+	Root:      scraper.ElemSel{Tag: "section", Att: "data-testid", Val: "my-orders"},
+	Container: scraper.ElemSel{Tag: "article", Att: "data-testid", Val: "order-summary"},
+	ID:        scraper.ElemSel{Tag: "a", Att: "data-testid", Val: "order-link"},
+	Date:      scraper.ElemSel{Tag: "h3", Att: "data-testid", Val: "order-date"},
+	Status:    scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "order-status"},
+	Total:     scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "order-total"},
+	Items:     scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "order-items"},
+	Slot:      scraper.ElemSel{Tag: "span", Att: "data-testid", Val: "order-slot"},
+}
+
+// GetOrderHistory retrieves past orders from the Waitrose account orders page.
+func (d *Datasource) GetOrderHistory(
+	ctx context.Context, page int,
+) (*datasource.OrderHistoryResult, error) {
+	url := ordersURL
+	if page > 1 {
+		url = ordersURL + "?page=" + strconv.Itoa(page)
+	}
+	body, err := d.browser.Fetch(ctx, url, d.cookies, ordersWaitSelector)
+	if err != nil {
+		return nil, fmt.Errorf("waitrose orders fetch: %w", err)
+	}
+	defer body.Close() //nolint:errcheck // Best-effort close.
+
+	result, err := ParseOrderHistory(body)
+	if err != nil {
+		return nil, err
+	}
+	result.Page = max(page, 1)
+	return result, nil
+}
+
+// ParseOrderHistory parses a Waitrose account orders page. The page doesn't
+// report a total, so Total is nil, and its order cards list no item lines,
+// so each order's Items is empty.
+func ParseOrderHistory(r io.Reader) (*datasource.OrderHistoryResult, error) {
+	orders, err := scraper.ParseOrderCards(r, orderSelectors, datasource.Waitrose)
+	if err != nil {
+		return nil, err
+	}
+	return &datasource.OrderHistoryResult{
+		Supermarket: datasource.Waitrose,
+		Orders:      orders,
+		Page:        1,
+		PageSize:    len(orders),
+	}, nil
+}
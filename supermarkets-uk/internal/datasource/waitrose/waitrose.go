@@ -208,6 +208,7 @@ func parseProducts(doc *html.Node) ([]datasource.Product, error) {
 		// Override price with Waitrose-aware parsing that handles pence.
 		if elem := scraper.FindElement(n, selectors.SearchSel.Price); elem != nil {
 			p.Price = parseWaitrosePrice(scraper.TextContent(elem))
+			p.Normalise()
 		}
 
 		products = append(products, p)
@@ -79,6 +79,29 @@ func TestParseCategories(t *testing.T) {
 	}
 }
 
+func TestParseOrderHistory(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/waitrose_orders.html")
+	result, err := waitrose.ParseOrderHistory(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.Waitrose, result.Supermarket)
+	require.Len(t, result.Orders, 1)
+
+	o := result.Orders[0]
+	assert.Equal(t, "1098765432", o.ID)
+	assert.Equal(t, "2026-03-02", o.Date)
+	assert.Equal(t, "Completed", o.Status)
+	assert.Equal(t, 17, o.TotalItems)
+	assert.InDelta(t, 72.05, o.TotalPrice, 0.001)
+	assert.NotEmpty(t, o.DeliverySlot)
+}
+
+func TestParseOrderHistory_LoginPage(t *testing.T) {
+	f := testutil.OpenTestFile(t, "testdata/waitrose_login.html")
+	_, err := waitrose.ParseOrderHistory(f)
+	require.ErrorIs(t, err, datasource.ErrSessionExpired)
+}
+
 func TestSearchIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -0,0 +1,315 @@
+// Package dietary filters products by dietary requirements, allergens, and
+// nutrition per 100g. Search results rarely carry ingredients or nutrition,
+// so callers fetch product details for products the filter cannot decide
+// on and check them again.
+package dietary
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// Verdict is the outcome of checking a product against a Filter.
+type Verdict int
+
+const (
+	// Unknown means the product lacks the information to decide.
+	Unknown Verdict = iota
+	// Pass means the product meets every requirement.
+	Pass
+	// Fail means the product breaks at least one requirement.
+	Fail
+)
+
+// Op is a nutrition threshold comparison.
+type Op string
+
+// Threshold comparisons.
+const (
+	Less         Op = "<"
+	LessEqual    Op = "<="
+	Greater      Op = ">"
+	GreaterEqual Op = ">="
+)
+
+// Threshold limits a nutrient per 100g, in grams or, for energy, kcal.
+type Threshold struct {
+	Nutrient string
+	Op       Op
+	Value    float64
+}
+
+func (t Threshold) String() string {
+	unit := "g"
+	if t.Nutrient == Energy {
+		unit = "kcal"
+	}
+	return fmt.Sprintf("%s%s%s%s", t.Nutrient, t.Op, strconv.FormatFloat(t.Value, 'f', -1, 64), unit)
+}
+
+func (t Threshold) allows(v float64) bool {
+	switch t.Op {
+	case Less:
+		return v < t.Value
+	case LessEqual:
+		return v <= t.Value
+	case Greater:
+		return v > t.Value
+	default:
+		return v >= t.Value
+	}
+}
+
+// Filter is a set of dietary requirements.
+type Filter struct {
+	// Diets are requirements such as "vegan" or "gluten-free".
+	Diets []string
+	// Allergens are allergens the product must not contain, such as "milk".
+	Allergens  []string
+	Thresholds []Threshold
+}
+
+// Empty reports whether the filter has no requirements.
+func (f Filter) Empty() bool {
+	return len(f.Diets) == 0 && len(f.Allergens) == 0 && len(f.Thresholds) == 0
+}
+
+// String describes the filter, e.g. "vegan, no milk, sugar<5g".
+func (f Filter) String() string {
+	parts := append([]string{}, f.Diets...)
+	for _, a := range f.Allergens {
+		parts = append(parts, "no "+a)
+	}
+	for _, t := range f.Thresholds {
+		parts = append(parts, t.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Check checks p against every requirement, returning Fail with a reason
+// if any requirement is broken, Unknown with the missing information if
+// any cannot be decided, and Pass otherwise.
+func (f Filter) Check(p datasource.Product) (Verdict, string) {
+	verdict, missing := Pass, ""
+	note := func(v Verdict, reason string) bool {
+		if v == Fail {
+			verdict, missing = Fail, reason
+			return true
+		}
+		if v == Unknown && verdict == Pass {
+			verdict, missing = Unknown, reason
+		}
+		return false
+	}
+
+	for _, d := range f.Diets {
+		if note(checkDiet(p, d)) {
+			return verdict, missing
+		}
+	}
+	for _, a := range f.Allergens {
+		if note(checkAllergen(p, a)) {
+			return verdict, missing
+		}
+	}
+	for _, t := range f.Thresholds {
+		if note(checkThreshold(p, t)) {
+			return verdict, missing
+		}
+	}
+	return verdict, missing
+}
+
+// WithDetails returns p with the ingredients, nutrition, and dietary
+// information from its product details filled in where p lacks them.
+func WithDetails(p datasource.Product, details *datasource.Product) datasource.Product {
+	if details == nil {
+		return p
+	}
+	if p.Ingredients == "" {
+		p.Ingredients = details.Ingredients
+	}
+	if p.Nutrition == nil {
+		p.Nutrition = details.Nutrition
+	}
+	if len(p.DietaryInfo) == 0 {
+		p.DietaryInfo = details.DietaryInfo
+	}
+	return p
+}
+
+// Diets and the ingredients that rule them out. Products labelled with a
+// diet, by DietaryInfo or in their name, meet it whatever the ingredients.
+var (
+	meatAndFish = []string{
+		"meat", "beef", "pork", "chicken", "turkey", "lamb", "duck", "bacon", "ham", "gelatine",
+		"gelatin", "anchovy", "fish", "tuna", "salmon", "prawn", "crab", "lobster", "mussel",
+		"lard", "suet", "rennet", "cochineal", "carmine",
+	}
+	dietExclusions = map[string][]string{
+		"vegetarian": meatAndFish,
+		"vegan": concat(
+			[]string{"honey", "beeswax"}, meatAndFish, allergenWords["milk"], allergenWords["eggs"],
+		),
+		"gluten-free": allergenWords["gluten"],
+		"dairy-free":  allergenWords["milk"],
+	}
+	dietAliases = map[string]string{
+		"veggie":       "vegetarian",
+		"gluten free":  "gluten-free",
+		"coeliac":      "gluten-free",
+		"dairy free":   "dairy-free",
+		"lactose-free": "dairy-free",
+		"lactose free": "dairy-free",
+	}
+)
+
+// allergenWords are ingredient words that indicate each of the 14 UK
+// allergens. Other allergens are matched by name.
+var allergenWords = map[string][]string{
+	"milk": {
+		"milk", "cream", "butter", "cheese", "whey", "lactose", "yogurt", "yoghurt", "casein",
+		"ghee",
+	},
+	"eggs":    {"egg"},
+	"gluten":  {"wheat", "barley", "rye", "oat", "spelt", "gluten", "semolina", "couscous"},
+	"peanuts": {"peanut", "groundnut"},
+	"nuts": {
+		"almond", "hazelnut", "walnut", "cashew", "pecan", "pistachio", "brazil nut",
+		"macadamia", "tree nut",
+	},
+	"soya":        {"soya", "soy", "soybean", "edamame", "tofu"},
+	"fish":        {"fish", "anchovy", "tuna", "salmon", "cod", "haddock", "mackerel"},
+	"crustaceans": {"prawn", "shrimp", "crab", "lobster", "crayfish", "langoustine"},
+	"molluscs":    {"mussel", "oyster", "squid", "clam", "scallop", "octopus", "whelk"},
+	"sesame":      {"sesame", "tahini"},
+	"celery":      {"celery", "celeriac"},
+	"mustard":     {"mustard"},
+	"lupin":       {"lupin"},
+	"sulphites":   {"sulphite", "sulphur dioxide", "sulfite", "metabisulphite"},
+}
+
+var allergenAliases = map[string]string{
+	"dairy": "milk", "egg": "eggs", "wheat": "gluten", "peanut": "peanuts", "nut": "nuts",
+	"tree nuts": "nuts", "soy": "soya", "shellfish": "crustaceans", "sulphur dioxide": "sulphites",
+}
+
+// freeFromLabels are DietaryInfo labels that rule out an allergen.
+var freeFromLabels = map[string]string{
+	"milk": "dairy-free", "eggs": "egg-free", "gluten": "gluten-free",
+	"nuts": "nut-free", "peanuts": "peanut-free",
+}
+
+// plainPhrases maps phrases containing an allergen word without the
+// allergen to what they do contain, e.g. "peanut butter" has no milk.
+var plainPhrases = map[string]string{
+	"coconut milk": "coconut", "coconut cream": "coconut", "cocoa butter": "cocoa",
+	"shea butter": "shea", "peanut butter": "peanut", "almond milk": "almond",
+	"cashew butter": "cashew", "almond butter": "almond", "oat milk": "oat", "soya milk": "soya",
+	"milk-free": "", "dairy-free": "", "egg-free": "", "gluten-free": "", "nut-free": "",
+	"peanut-free": "",
+}
+
+// NormaliseDiet returns the canonical name of a diet, or an error if it is
+// not supported.
+func NormaliseDiet(diet string) (string, error) {
+	d := strings.ToLower(strings.TrimSpace(diet))
+	if alias, ok := dietAliases[d]; ok {
+		d = alias
+	}
+	if _, ok := dietExclusions[d]; !ok {
+		return "", fmt.Errorf("unsupported diet %q: use %s", diet, strings.Join(sortedKeys(dietExclusions), ", "))
+	}
+	return d, nil
+}
+
+// NormaliseAllergen returns the canonical name of an allergen. Allergens
+// other than the 14 UK allergens are returned lowercased.
+func NormaliseAllergen(allergen string) string {
+	a := strings.ToLower(strings.TrimSpace(allergen))
+	if alias, ok := allergenAliases[a]; ok {
+		return alias
+	}
+	return a
+}
+
+func checkDiet(p datasource.Product, diet string) (Verdict, string) {
+	if labelled(p, diet) || (diet == "vegetarian" && labelled(p, "vegan")) {
+		return Pass, ""
+	}
+	if p.Ingredients == "" {
+		return Unknown, "no ingredients to check " + diet
+	}
+	if word := findWord(p.Ingredients, dietExclusions[diet]); word != "" {
+		return Fail, fmt.Sprintf("not %s: contains %s", diet, word)
+	}
+	return Pass, ""
+}
+
+func checkAllergen(p datasource.Product, allergen string) (Verdict, string) {
+	if label, ok := freeFromLabels[allergen]; ok && labelled(p, label) {
+		return Pass, ""
+	}
+	if p.Ingredients == "" {
+		return Unknown, "no ingredients to check for " + allergen
+	}
+	words, ok := allergenWords[allergen]
+	if !ok {
+		words = []string{allergen}
+	}
+	if word := findWord(p.Ingredients, words); word != "" {
+		return Fail, fmt.Sprintf("contains %s (%s)", allergen, word)
+	}
+	return Pass, ""
+}
+
+// labelled reports whether p's dietary information or name says it meets
+// label, such as "vegan" or "gluten-free".
+func labelled(p datasource.Product, label string) bool {
+	want := strings.ReplaceAll(label, "-", " ")
+	for _, info := range p.DietaryInfo {
+		if strings.ReplaceAll(strings.ToLower(info), "-", " ") == want {
+			return true
+		}
+	}
+	name := " " + strings.ReplaceAll(strings.ToLower(p.Name), "-", " ") + " "
+	return strings.Contains(name, " "+want+" ")
+}
+
+// findWord returns the first of words found in text as a whole word,
+// allowing a plural "s" or "es", or "" if none are.
+func findWord(text string, words []string) string {
+	text = strings.ToLower(text)
+	for phrase, plain := range plainPhrases {
+		text = strings.ReplaceAll(text, phrase, " "+plain+" ")
+	}
+	for _, w := range words {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(w) + `(?:s|es)?\b`)
+		if re.MatchString(text) {
+			return w
+		}
+	}
+	return ""
+}
+
+func concat(lists ...[]string) []string {
+	var all []string
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+	return all
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
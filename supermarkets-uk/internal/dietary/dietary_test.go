@@ -0,0 +1,132 @@
+package dietary_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/dietary"
+)
+
+func yoghurt() datasource.Product {
+	return datasource.Product{
+		Name:        "Tesco Greek Style Strawberry Yogurt 4 X 100G",
+		Ingredients: "Yogurt (Milk), Strawberry (10%), Sugar, Cornflour, Natural Flavouring",
+		Nutrition: &datasource.NutritionInfo{Per100g: map[string]string{
+			"Energy":             "450kJ/108kcal",
+			"Fat":                "4.8g",
+			"of which saturates": "3.1g",
+			"Carbohydrate":       "13.2g",
+			"of which sugars":    "12.9g",
+			"Protein":            "3.3g",
+			"Salt":               "<0.1g",
+		}},
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := dietary.ParseThresholds("sugar<5g, Sat Fat <= 1.5, salt<300mg, energy>=100kcal")
+	require.NoError(t, err)
+	require.Len(t, thresholds, 4)
+	assert.Equal(t, dietary.Threshold{Nutrient: dietary.Sugars, Op: dietary.Less, Value: 5}, thresholds[0])
+	assert.Equal(t, dietary.Saturates, thresholds[1].Nutrient)
+	assert.Equal(t, dietary.LessEqual, thresholds[1].Op)
+	assert.InDelta(t, 0.3, thresholds[2].Value, 0.0001)
+	assert.Equal(t, "energy>=100kcal", thresholds[3].String())
+
+	_, err = dietary.ParseThresholds("sugar about 5g")
+	require.Error(t, err)
+	_, err = dietary.ParseThresholds("vitamin c>10mg")
+	require.Error(t, err)
+	_, err = dietary.ParseThresholds("sugar<5kcal")
+	require.Error(t, err)
+}
+
+func TestPer100g(t *testing.T) {
+	p := yoghurt()
+	for nutrient, want := range map[string]float64{
+		dietary.Energy: 108, dietary.Fat: 4.8, dietary.Saturates: 3.1, dietary.Sugars: 12.9, dietary.Salt: 0.1,
+	} {
+		v, ok := dietary.Per100g(p, nutrient)
+		require.True(t, ok, nutrient)
+		assert.InDelta(t, want, v, 0.001, nutrient)
+	}
+	_, ok := dietary.Per100g(p, dietary.Fibre)
+	assert.False(t, ok)
+
+	p.Nutrition = &datasource.NutritionInfo{Per100g: map[string]string{
+		"Energy (kJ)": "1046", "Energy (kcal)": "250", "Fibre": "Trace",
+	}}
+	v, ok := dietary.Per100g(p, dietary.Energy)
+	require.True(t, ok)
+	assert.InDelta(t, 250, v, 0.001)
+	v, ok = dietary.Per100g(p, dietary.Fibre)
+	require.True(t, ok)
+	assert.Zero(t, v)
+}
+
+func TestCheck(t *testing.T) {
+	sugar, err := dietary.ParseThresholds("sugar<5g")
+	require.NoError(t, err)
+
+	verdict, reason := dietary.Filter{Thresholds: sugar}.Check(yoghurt())
+	assert.Equal(t, dietary.Fail, verdict)
+	assert.Equal(t, "sugars is 12.9g per 100g", reason)
+
+	verdict, reason = dietary.Filter{Diets: []string{"vegan"}}.Check(yoghurt())
+	assert.Equal(t, dietary.Fail, verdict)
+	assert.Equal(t, "not vegan: contains milk", reason)
+
+	verdict, _ = dietary.Filter{Diets: []string{"vegetarian"}, Allergens: []string{"nuts"}}.Check(yoghurt())
+	assert.Equal(t, dietary.Pass, verdict)
+
+	verdict, _ = dietary.Filter{Allergens: []string{"milk"}}.Check(yoghurt())
+	assert.Equal(t, dietary.Fail, verdict)
+
+	// Search results usually have no ingredients or nutrition.
+	bare := datasource.Product{Name: "Alpro Oat Drink 1L"}
+	verdict, reason = dietary.Filter{Diets: []string{"vegan"}, Thresholds: sugar}.Check(bare)
+	assert.Equal(t, dietary.Unknown, verdict)
+	assert.Equal(t, "no ingredients to check vegan", reason)
+
+	enriched := dietary.WithDetails(bare, &datasource.Product{
+		Ingredients: "Water, Oat (10%), Sunflower Oil, Calcium Carbonate, Salt",
+		Nutrition:   &datasource.NutritionInfo{Per100g: map[string]string{"Sugars": "3.3g"}},
+	})
+	verdict, _ = dietary.Filter{Diets: []string{"vegan"}, Thresholds: sugar}.Check(enriched)
+	assert.Equal(t, dietary.Pass, verdict)
+	verdict, reason = dietary.Filter{Diets: []string{"gluten-free"}}.Check(enriched)
+	assert.Equal(t, dietary.Fail, verdict)
+	assert.Equal(t, "not gluten-free: contains oat", reason)
+}
+
+func TestCheckLabels(t *testing.T) {
+	p := datasource.Product{
+		Name:        "Asda Free From Chocolate Chip Cookies",
+		DietaryInfo: []string{"Gluten-free", "Vegan"},
+	}
+	verdict, _ := dietary.Filter{
+		Diets:     []string{"gluten-free", "vegetarian"},
+		Allergens: []string{"gluten"},
+	}.Check(p)
+	assert.Equal(t, dietary.Pass, verdict)
+
+	// Plant "butter" and "milk" are not dairy, but peanuts are still peanuts.
+	p = datasource.Product{Name: "Peanut Butter Oat Bar", Ingredients: "Oats, Peanut Butter (20%), Coconut Milk"}
+	verdict, _ = dietary.Filter{Diets: []string{"vegan"}}.Check(p)
+	assert.Equal(t, dietary.Pass, verdict)
+	verdict, reason := dietary.Filter{Allergens: []string{dietary.NormaliseAllergen("Peanut")}}.Check(p)
+	assert.Equal(t, dietary.Fail, verdict)
+	assert.Equal(t, "contains peanuts (peanut)", reason)
+}
+
+func TestNormaliseDiet(t *testing.T) {
+	diet, err := dietary.NormaliseDiet("Gluten Free")
+	require.NoError(t, err)
+	assert.Equal(t, "gluten-free", diet)
+
+	_, err = dietary.NormaliseDiet("paleo")
+	require.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package dietary
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// Nutrients that thresholds can be set on.
+const (
+	Energy       = "energy"
+	Fat          = "fat"
+	Saturates    = "saturates"
+	Carbohydrate = "carbohydrate"
+	Sugars       = "sugars"
+	Fibre        = "fibre"
+	Protein      = "protein"
+	Salt         = "salt"
+)
+
+var nutrientAliases = map[string]string{
+	"energy": Energy, "calories": Energy, "kcal": Energy,
+	"fat": Fat, "total fat": Fat,
+	"saturates": Saturates, "saturated fat": Saturates, "sat fat": Saturates, "saturated": Saturates,
+	"carbohydrate": Carbohydrate, "carbohydrates": Carbohydrate, "carbs": Carbohydrate,
+	"sugar": Sugars, "sugars": Sugars,
+	"fibre": Fibre, "fiber": Fibre,
+	"protein": Protein,
+	"salt":    Salt,
+}
+
+var (
+	thresholdRe = regexp.MustCompile(`^([a-z ]+?)\s*(<=|>=|<|>)\s*([0-9]+(?:\.[0-9]+)?)\s*(g|mg|kcal)?$`)
+	amountRe    = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*(mg|g)?`)
+	kcalRe      = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*kcal`)
+	keyWordRe   = regexp.MustCompile(`[a-z]+`)
+)
+
+// ParseThresholds parses comma-separated nutrition thresholds per 100g,
+// such as "sugar<5g, salt<=0.3, protein>10g, energy<150kcal". Amounts are
+// grams unless given in mg, and energy is in kcal.
+func ParseThresholds(s string) ([]Threshold, error) {
+	var thresholds []Threshold
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		m := thresholdRe.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid nutrition threshold %q: use e.g. 'sugar<5g'", part)
+		}
+		nutrient, ok := nutrientAliases[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown nutrient %q: use %s", m[1], strings.Join(nutrientNames(), ", "))
+		}
+		value, _ := strconv.ParseFloat(m[3], 64)
+		switch {
+		case m[4] == "mg":
+			value /= 1000
+		case (m[4] == "kcal") != (nutrient == Energy):
+			return nil, fmt.Errorf("invalid nutrition threshold %q: only energy is in kcal", part)
+		}
+		thresholds = append(thresholds, Threshold{Nutrient: nutrient, Op: Op(m[2]), Value: value})
+	}
+	return thresholds, nil
+}
+
+func nutrientNames() []string {
+	seen := map[string]bool{}
+	for _, n := range nutrientAliases {
+		seen[n] = true
+	}
+	return sortedKeys(seen)
+}
+
+func checkThreshold(p datasource.Product, t Threshold) (Verdict, string) {
+	v, ok := Per100g(p, t.Nutrient)
+	if !ok {
+		return Unknown, "no " + t.Nutrient + " per 100g"
+	}
+	if !t.allows(v) {
+		return Fail, fmt.Sprintf("%s is %s per 100g", t.Nutrient, formatAmount(t.Nutrient, v))
+	}
+	return Pass, ""
+}
+
+func formatAmount(nutrient string, v float64) string {
+	if nutrient == Energy {
+		return strconv.FormatFloat(v, 'f', -1, 64) + "kcal"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + "g"
+}
+
+// Per100g returns the amount of a nutrient per 100g from p's nutrition
+// table, in grams or, for energy, kcal. Stores label rows differently, e.g.
+// "Sugars", "of which sugars" and "- Sugars", so rows are matched by the
+// nutrient's words. "Trace" reads as zero and "<0.5g" as 0.5.
+func Per100g(p datasource.Product, nutrient string) (float64, bool) {
+	if p.Nutrition == nil {
+		return 0, false
+	}
+	keys := sortedKeys(p.Nutrition.Per100g)
+	for _, key := range keys {
+		if nutrientOf(key) != nutrient {
+			continue
+		}
+		if v, ok := parseAmount(nutrient, key, p.Nutrition.Per100g[key]); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// nutrientOf returns the nutrient a nutrition table row is for, or "".
+func nutrientOf(key string) string {
+	words := keyWordRe.FindAllString(strings.ToLower(key), -1)
+	has := func(prefix string) bool {
+		for _, w := range words {
+			if strings.HasPrefix(w, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("energy") || has("calor") || has("kcal"):
+		return Energy
+	case has("saturate"):
+		return Saturates
+	case has("mono") || has("poly") || has("trans"):
+		return ""
+	case has("fat"):
+		return Fat
+	case has("sugar"):
+		return Sugars
+	case has("carbohydrate"):
+		return Carbohydrate
+	case has("fibre") || has("fiber"):
+		return Fibre
+	case has("protein"):
+		return Protein
+	case has("salt"):
+		return Salt
+	}
+	return ""
+}
+
+func parseAmount(nutrient, key, value string) (float64, bool) {
+	value = strings.ToLower(value)
+	if nutrient == Energy {
+		if m := kcalRe.FindStringSubmatch(value); m != nil {
+			v, _ := strconv.ParseFloat(m[1], 64)
+			return v, true
+		}
+		// A separate kcal row may hold a bare number.
+		if !strings.Contains(strings.ToLower(key), "kcal") || strings.Contains(value, "kj") {
+			return 0, false
+		}
+	}
+	if strings.Contains(value, "trace") {
+		return 0, true
+	}
+	m := amountRe.FindStringSubmatch(value)
+	if m == nil {
+		return 0, false
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+	if m[2] == "mg" {
+		v /= 1000
+	}
+	return v, true
+}
@@ -0,0 +1,84 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// maxRateDrop is how far a field's share of products may fall between an
+// old and a refreshed fixture before it counts as a regression.
+const maxRateDrop = 0.1
+
+// Diff compares the products parsed from an old and a refreshed fixture.
+type Diff struct {
+	Old     Coverage
+	New     Coverage
+	Added   []string // names only in the new products
+	Removed []string // names only in the old products
+	// Regressions describes drops in coverage large enough to suggest the
+	// parser no longer matches the page.
+	Regressions []string
+}
+
+// Compare diffs the products parsed from an old fixture against those
+// parsed from its refreshed replacement. Products are matched by name;
+// different products between captures are expected, so only coverage
+// drops are regressions.
+func Compare(oldProducts, newProducts []datasource.Product) Diff {
+	d := Diff{Old: Measure(oldProducts), New: Measure(newProducts)}
+	d.Added = missingNames(newProducts, oldProducts)
+	d.Removed = missingNames(oldProducts, newProducts)
+
+	if d.New.Products*2 < d.Old.Products {
+		d.Regressions = append(d.Regressions,
+			fmt.Sprintf("products fell from %d to %d", d.Old.Products, d.New.Products))
+	}
+	oldFields, newFields := d.Old.fields(), d.New.fields()
+	for i, f := range oldFields {
+		if d.Old.rate(f.count)-d.New.rate(newFields[i].count) > maxRateDrop {
+			d.Regressions = append(d.Regressions, fmt.Sprintf("%s fell from %d of %d to %d of %d",
+				f.name, f.count, d.Old.Products, newFields[i].count, d.New.Products))
+		}
+	}
+	return d
+}
+
+// missingNames returns the names in products that aren't in others.
+func missingNames(products, others []datasource.Product) []string {
+	seen := make(map[string]bool, len(others))
+	for _, p := range others {
+		seen[p.Name] = true
+	}
+	var names []string
+	for _, p := range products {
+		if p.Name != "" && !seen[p.Name] {
+			seen[p.Name] = true
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// String renders the diff as a report: coverage before and after, then
+// added and removed products, then any regressions.
+func (d Diff) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "products: %d -> %d\n", d.Old.Products, d.New.Products)
+	oldFields, newFields := d.Old.fields(), d.New.fields()
+	for i, f := range oldFields {
+		fmt.Fprintf(&sb, "%s: %d/%d -> %d/%d\n",
+			f.name, f.count, d.Old.Products, newFields[i].count, d.New.Products)
+	}
+	for _, name := range d.Added {
+		fmt.Fprintf(&sb, "+ %s\n", name)
+	}
+	for _, name := range d.Removed {
+		fmt.Fprintf(&sb, "- %s\n", name)
+	}
+	for _, r := range d.Regressions {
+		fmt.Fprintf(&sb, "REGRESSION: %s\n", r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,176 @@
+// Package healthcheck detects stores whose markup or API has changed under
+// a datasource. Scraped stores rarely fail outright when selectors drift;
+// they return fewer products, or products missing names, prices, or URLs.
+// A healthcheck runs a canned search at each store and checks how
+// completely the results were parsed.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/cache"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// DefaultQuery is the canned search for stores without their own.
+const DefaultQuery = "milk"
+
+// queries holds canned searches for stores that don't stock DefaultQuery.
+var queries = map[datasource.SupermarketID]string{
+	datasource.Hiyou:      "rice",
+	datasource.TukTukMart: "rice",
+	datasource.Morueats:   "rice",
+}
+
+const (
+	// MinProducts is the fewest products a canned search should return.
+	MinProducts = 3
+	// MinRate is the share of products that must have each field.
+	MinRate = 0.9
+)
+
+// Query returns the canned search for a store.
+func Query(id datasource.SupermarketID) string {
+	if q, ok := queries[id]; ok {
+		return q
+	}
+	return DefaultQuery
+}
+
+// Coverage counts how many parsed products have each field.
+type Coverage struct {
+	Products int `json:"products"`
+	Names    int `json:"names"`
+	Prices   int `json:"prices"`
+	URLs     int `json:"urls"`
+}
+
+// Measure counts the fields present in products.
+func Measure(products []datasource.Product) Coverage {
+	c := Coverage{Products: len(products)}
+	for _, p := range products {
+		if p.Name != "" {
+			c.Names++
+		}
+		if p.Price > 0 {
+			c.Prices++
+		}
+		if p.URL != "" {
+			c.URLs++
+		}
+	}
+	return c
+}
+
+// fields returns each field's name and count, in report order.
+func (c Coverage) fields() []struct {
+	name  string
+	count int
+} {
+	return []struct {
+		name  string
+		count int
+	}{{"names", c.Names}, {"prices", c.Prices}, {"URLs", c.URLs}}
+}
+
+func (c Coverage) rate(count int) float64 {
+	if c.Products == 0 {
+		return 0
+	}
+	return float64(count) / float64(c.Products)
+}
+
+// Problems describes every way c falls short of MinProducts and MinRate.
+func (c Coverage) Problems() []string {
+	var problems []string
+	if c.Products < MinProducts {
+		problems = append(problems, fmt.Sprintf("only %d product(s), want at least %d", c.Products, MinProducts))
+	}
+	if c.Products == 0 {
+		return problems
+	}
+	for _, f := range c.fields() {
+		if c.rate(f.count) < MinRate {
+			problems = append(problems, fmt.Sprintf("%s on %d of %d product(s), want at least %.0f%%",
+				f.name, f.count, c.Products, MinRate*100))
+		}
+	}
+	return problems
+}
+
+// Result is the healthcheck outcome for one store.
+type Result struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	Query       string                   `json:"query"`
+	Healthy     bool                     `json:"healthy"`
+	Coverage    Coverage                 `json:"coverage"`
+	Problems    []string                 `json:"problems,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// Evaluate checks one store's search result for the canned query.
+func Evaluate(r datasource.SearchResult, query string) Result {
+	res := Result{Supermarket: r.Supermarket, Query: query}
+	if r.Error != "" {
+		res.Error = r.Error
+		return res
+	}
+	res.Coverage = Measure(r.Products)
+	res.Problems = res.Coverage.Problems()
+	res.Healthy = len(res.Problems) == 0
+	return res
+}
+
+// Searcher searches stores; *client.Client implements it.
+type Searcher interface {
+	SearchAll(ctx context.Context, query string, supermarkets []datasource.SupermarketID) []datasource.SearchResult
+	ListSupermarkets() []client.SupermarketInfo
+}
+
+// Run runs each store's canned search, bypassing the response cache and
+// without starting a login, and evaluates the results. With no ids, every
+// store is checked. Results are in the order of ids.
+func Run(ctx context.Context, s Searcher, ids []datasource.SupermarketID) []Result {
+	if len(ids) == 0 {
+		for _, info := range s.ListSupermarkets() {
+			ids = append(ids, info.ID)
+		}
+	}
+	ctx = client.WithoutAuth(cache.WithFresh(ctx))
+
+	// Stores sharing a canned query are searched together.
+	var order []string
+	groups := make(map[string][]datasource.SupermarketID)
+	for _, id := range ids {
+		q := Query(id)
+		if _, ok := groups[q]; !ok {
+			order = append(order, q)
+		}
+		groups[q] = append(groups[q], id)
+	}
+
+	byID := make(map[datasource.SupermarketID]Result, len(ids))
+	for _, q := range order {
+		for _, r := range s.SearchAll(ctx, q, groups[q]) {
+			byID[r.Supermarket] = Evaluate(r, q)
+		}
+	}
+
+	results := make([]Result, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, byID[id])
+	}
+	return results
+}
+
+// Healthy reports whether every result is healthy.
+func Healthy(results []Result) bool {
+	for _, r := range results {
+		if !r.Healthy {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package healthcheck_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/healthcheck"
+)
+
+func products(n int, fn func(i int, p *datasource.Product)) []datasource.Product {
+	ps := make([]datasource.Product, n)
+	for i := range ps {
+		ps[i] = datasource.Product{
+			Name:  fmt.Sprintf("Product %d", i),
+			Price: 1.5,
+			URL:   fmt.Sprintf("https://example.com/%d", i),
+		}
+		if fn != nil {
+			fn(i, &ps[i])
+		}
+	}
+	return ps
+}
+
+func TestEvaluate(t *testing.T) {
+	r := healthcheck.Evaluate(datasource.SearchResult{
+		Supermarket: datasource.Tesco, Products: products(10, nil),
+	}, "milk")
+	assert.True(t, r.Healthy)
+	assert.Empty(t, r.Problems)
+	assert.Equal(t, healthcheck.Coverage{Products: 10, Names: 10, Prices: 10, URLs: 10}, r.Coverage)
+
+	// Prices missing on most products: the price selector has drifted.
+	r = healthcheck.Evaluate(datasource.SearchResult{
+		Supermarket: datasource.Waitrose,
+		Products: products(10, func(i int, p *datasource.Product) {
+			if i > 1 {
+				p.Price = 0
+			}
+		}),
+	}, "milk")
+	assert.False(t, r.Healthy)
+	require.Len(t, r.Problems, 1)
+	assert.Contains(t, r.Problems[0], "prices on 2 of 10")
+
+	r = healthcheck.Evaluate(datasource.SearchResult{Supermarket: datasource.Asda, Products: products(1, nil)}, "milk")
+	assert.False(t, r.Healthy)
+	assert.Contains(t, r.Problems[0], "only 1 product")
+
+	r = healthcheck.Evaluate(datasource.SearchResult{Supermarket: datasource.Asda, Error: "HTTP 403"}, "milk")
+	assert.False(t, r.Healthy)
+	assert.Equal(t, "HTTP 403", r.Error)
+}
+
+type fakeSearcher struct {
+	queries map[string][]datasource.SupermarketID
+}
+
+func (f *fakeSearcher) SearchAll(
+	_ context.Context, query string, ids []datasource.SupermarketID,
+) []datasource.SearchResult {
+	f.queries[query] = append(f.queries[query], ids...)
+	results := make([]datasource.SearchResult, len(ids))
+	for i, id := range ids {
+		results[i] = datasource.SearchResult{Supermarket: id, Products: products(5, nil)}
+	}
+	return results
+}
+
+func (f *fakeSearcher) ListSupermarkets() []client.SupermarketInfo {
+	return []client.SupermarketInfo{{ID: datasource.Tesco}, {ID: datasource.Hiyou}, {ID: datasource.Asda}}
+}
+
+func TestRun(t *testing.T) {
+	s := &fakeSearcher{queries: make(map[string][]datasource.SupermarketID)}
+	results := healthcheck.Run(t.Context(), s, nil)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, datasource.Tesco, results[0].Supermarket)
+	assert.Equal(t, datasource.Hiyou, results[1].Supermarket)
+	assert.Equal(t, "rice", results[1].Query)
+	assert.True(t, healthcheck.Healthy(results))
+	assert.Equal(t, map[string][]datasource.SupermarketID{
+		"milk": {datasource.Tesco, datasource.Asda},
+		"rice": {datasource.Hiyou},
+	}, s.queries)
+}
+
+func TestCompare(t *testing.T) {
+	old := products(10, nil)
+	refreshed := products(9, func(i int, p *datasource.Product) {
+		if i == 8 {
+			p.Name = "New Product"
+		}
+	})
+	d := healthcheck.Compare(old, refreshed)
+	assert.Equal(t, []string{"New Product"}, d.Added)
+	assert.Equal(t, []string{"Product 8", "Product 9"}, d.Removed)
+	assert.Empty(t, d.Regressions)
+
+	// URLs lost from most products after a markup change.
+	broken := products(10, func(_ int, p *datasource.Product) { p.URL = "" })
+	d = healthcheck.Compare(old, broken)
+	require.Len(t, d.Regressions, 1)
+	assert.Equal(t, "URLs fell from 10 of 10 to 0 of 10", d.Regressions[0])
+	assert.Contains(t, d.String(), "REGRESSION: URLs fell")
+
+	d = healthcheck.Compare(old, products(3, nil))
+	assert.Equal(t, []string{"products fell from 10 to 3"}, d.Regressions)
+}
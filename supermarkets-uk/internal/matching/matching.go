@@ -0,0 +1,292 @@
+// Package matching recognises the same product at different supermarkets.
+// Stores give the same product different IDs and slightly different names,
+// so products are compared by GTIN where both stores provide one, and
+// otherwise by brand, normalised name tokens, and pack size.
+package matching
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// DefaultMinConfidence is the confidence below which products are not
+// considered the same.
+const DefaultMinConfidence = 0.5
+
+// Match is a product judged to be the same as another.
+type Match struct {
+	Product    datasource.Product `json:"product"`
+	Confidence float64            `json:"confidence"`
+	// Reasons explains the confidence, e.g. "same brand" or "different size".
+	Reasons []string `json:"reasons"`
+}
+
+// Cluster is a group of products judged to be the same product, at most
+// one per supermarket.
+type Cluster struct {
+	Products []datasource.Product `json:"products"`
+	// Confidence is the lowest confidence between the first product and
+	// any other in the cluster.
+	Confidence float64 `json:"confidence"`
+}
+
+// ownBrands maps the leading words of supermarket own-label product names
+// and brands to the supermarket. Own-label products at different stores
+// are equivalents rather than the same product.
+var ownBrands = map[string]datasource.SupermarketID{
+	"tesco":              datasource.Tesco,
+	"sainsburys":         datasource.Sainsburys,
+	"by sainsburys":      datasource.Sainsburys,
+	"asda":               datasource.Asda,
+	"the bakery at asda": datasource.Asda,
+	"morrisons":          datasource.Morrisons,
+	"waitrose":           datasource.Waitrose,
+	"essential waitrose": datasource.Waitrose,
+	"ocado":              datasource.Ocado,
+	"lidl":               datasource.Lidl,
+	"aldi":               datasource.Aldi,
+	"iceland":            datasource.Iceland,
+	"co op":              datasource.Coop,
+}
+
+// maxOwnBrandWords is the length in words of the longest ownBrands key.
+const maxOwnBrandWords = 4
+
+var (
+	wordRe = regexp.MustCompile(`[a-z0-9]+(?:\.[0-9]+)?`)
+	// sizeTokenRe matches pack size tokens such as "415g", "2l", "4x" and
+	// "2.272litre", which are compared through Product.Quantity instead.
+	sizeTokenRe = regexp.MustCompile(`^[0-9.]+(?:x|g|kg|ml|cl|l|ltr|litres?|pints?|pk|pack)?$`)
+)
+
+// noiseWords carry no information about which product it is.
+var noiseWords = map[string]bool{
+	"and": true, "of": true, "the": true, "with": true, "a": true, "in": true,
+	"x": true, "g": true, "kg": true, "ml": true, "l": true, "litre": true, "pint": true,
+	"pack": true, "pk": true, "each": true, "approx": true,
+}
+
+// features are the parts of a product compared when matching.
+type features struct {
+	brand    string
+	ownBrand datasource.SupermarketID
+	// words are the name's words without the brand or pack size, and
+	// tokens the same words singularised for comparison.
+	words  []string
+	tokens []string
+	size   *datasource.Quantity
+	gtin   string
+}
+
+func extract(p datasource.Product) features {
+	words := wordRe.FindAllString(strings.ToLower(strings.ReplaceAll(p.Name, "'", "")), -1)
+	f := features{size: p.Quantity, gtin: strings.TrimLeft(p.GTIN, "0")}
+
+	brandWords := wordRe.FindAllString(strings.ToLower(strings.ReplaceAll(p.Brand, "'", "")), -1)
+	if id, ok := ownBrands[strings.Join(brandWords, " ")]; ok {
+		f.ownBrand, f.brand = id, string(id)
+	} else if len(brandWords) > 0 {
+		f.brand = brandWords[0]
+	}
+
+	// Own-label names start with the store name, and branded names
+	// usually start with the brand.
+	skip := 0
+	for n := min(maxOwnBrandWords, len(words)); n > 0; n-- {
+		if id, ok := ownBrands[strings.Join(words[:n], " ")]; ok {
+			f.ownBrand, f.brand, skip = id, string(id), n
+			break
+		}
+	}
+	if f.brand == "" && len(words) > 0 {
+		f.brand, skip = words[0], 1
+	}
+
+	for i, w := range words {
+		token := strings.TrimSuffix(w, "s")
+		if i < skip || w == f.brand || noiseWords[token] || sizeTokenRe.MatchString(w) {
+			continue
+		}
+		f.words = append(f.words, w)
+		f.tokens = append(f.tokens, token)
+	}
+	return f
+}
+
+// Score returns the confidence, from 0 to 1, that a and b are the same
+// product, and the reasons for it.
+func Score(a, b datasource.Product) (float64, []string) {
+	fa, fb := extract(a), extract(b)
+	if fa.gtin != "" && fb.gtin != "" {
+		if fa.gtin == fb.gtin {
+			return 1, []string{"same GTIN"}
+		}
+		return 0, []string{"different GTIN"}
+	}
+
+	name := nameSimilarity(fa.tokens, fb.tokens)
+	reasons := []string{nameReason(name)}
+	brand, reason := brandFactor(fa, fb)
+	reasons = append(reasons, reason)
+	size, reason := sizeFactor(fa.size, fb.size)
+	if reason != "" {
+		reasons = append(reasons, reason)
+	}
+	return math.Round(name*brand*size*100) / 100, reasons
+}
+
+func nameReason(similarity float64) string {
+	switch {
+	case similarity == 1:
+		return "same name"
+	case similarity >= 0.75:
+		return "similar name"
+	default:
+		return "partly matching name"
+	}
+}
+
+func brandFactor(a, b features) (float64, string) {
+	switch {
+	case a.ownBrand != "" && b.ownBrand != "":
+		if a.ownBrand == b.ownBrand {
+			return 1, "same brand"
+		}
+		return 0.8, "own-label equivalents"
+	case a.brand == b.brand:
+		return 1, "same brand"
+	default:
+		return 0.4, "different brand"
+	}
+}
+
+// sizeTolerance is how far apart two pack sizes can be and still be the
+// same, allowing for "2.272L" against "4 pints".
+const sizeTolerance = 0.05
+
+func sizeFactor(a, b *datasource.Quantity) (float64, string) {
+	switch {
+	case a == nil || b == nil:
+		return 0.85, ""
+	case a.Unit != b.Unit:
+		return 0.5, "different size"
+	case math.Abs(a.Total()-b.Total()) <= sizeTolerance*math.Max(a.Total(), b.Total()):
+		return 1, "same size"
+	default:
+		return 0.6, "different size"
+	}
+}
+
+// nameSimilarity is the Dice coefficient of two token lists. Tokens of
+// four or more letters match if one is a prefix of the other, so "bean"
+// matches "beanz".
+func nameSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	used := make([]bool, len(b))
+	common := 0
+	for _, x := range a {
+		for j, y := range b {
+			if !used[j] && tokensMatch(x, y) {
+				used[j] = true
+				common++
+				break
+			}
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+func tokensMatch(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// FindEquivalents returns the best match for target at each other
+// supermarket among candidates, most confident first. Matches below
+// minConfidence are left out.
+func FindEquivalents(target datasource.Product, candidates []datasource.Product, minConfidence float64) []Match {
+	best := map[datasource.SupermarketID]Match{}
+	for _, c := range candidates {
+		if c.Supermarket == target.Supermarket {
+			continue
+		}
+		score, reasons := Score(target, c)
+		if score < minConfidence {
+			continue
+		}
+		if m, ok := best[c.Supermarket]; !ok || score > m.Confidence {
+			best[c.Supermarket] = Match{Product: c, Confidence: score, Reasons: reasons}
+		}
+	}
+
+	matches := make([]Match, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].Product.Supermarket < matches[j].Product.Supermarket
+	})
+	return matches
+}
+
+// ClusterProducts groups products that are the same product at different
+// supermarkets. Each product joins the most confident existing cluster
+// that has no product from its supermarket, if that confidence is at
+// least minConfidence, and otherwise starts a new cluster. Clusters keep
+// the order of their first products.
+func ClusterProducts(products []datasource.Product, minConfidence float64) []Cluster {
+	var clusters []Cluster
+	for _, p := range products {
+		bestIdx, bestScore := -1, 0.0
+		for i, c := range clusters {
+			if hasSupermarket(c, p.Supermarket) {
+				continue
+			}
+			if score, _ := Score(c.Products[0], p); score >= minConfidence && score > bestScore {
+				bestIdx, bestScore = i, score
+			}
+		}
+		if bestIdx < 0 {
+			clusters = append(clusters, Cluster{Products: []datasource.Product{p}, Confidence: 1})
+			continue
+		}
+		c := &clusters[bestIdx]
+		c.Products = append(c.Products, p)
+		c.Confidence = math.Min(c.Confidence, bestScore)
+	}
+	return clusters
+}
+
+func hasSupermarket(c Cluster, id datasource.SupermarketID) bool {
+	for _, p := range c.Products {
+		if p.Supermarket == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchQuery returns search terms likely to find p at other supermarkets:
+// its name without the store's own-label prefix or pack size.
+func SearchQuery(p datasource.Product) string {
+	f := extract(p)
+	words := f.words
+	if f.ownBrand == "" && f.brand != "" {
+		words = append([]string{f.brand}, words...)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,97 @@
+package matching_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/matching"
+)
+
+func product(id datasource.SupermarketID, name string, price float64) datasource.Product {
+	p := datasource.Product{ID: string(id) + "-1", Supermarket: id, Name: name, Price: price}
+	p.Normalise()
+	return p
+}
+
+func TestScore(t *testing.T) {
+	tescoBeans := product(datasource.Tesco, "Heinz Baked Beans In Tomato Sauce 415G", 1.40)
+
+	score, reasons := matching.Score(tescoBeans, product(datasource.Asda, "Heinz Baked Beans in Tomato Sauce 415g", 1.35))
+	assert.InDelta(t, 1, score, 0.001)
+	assert.Equal(t, []string{"same name", "same brand", "same size"}, reasons)
+
+	score, _ = matching.Score(tescoBeans, product(datasource.Ocado, "Heinz Baked Beanz 415g", 1.40))
+	assert.GreaterOrEqual(t, score, matching.DefaultMinConfidence)
+
+	multipack, reasons := matching.Score(tescoBeans, product(datasource.Asda, "Heinz Baked Beans 4 x 415g", 4.50))
+	assert.Less(t, multipack, score)
+	assert.Contains(t, reasons, "different size")
+
+	other, reasons := matching.Score(tescoBeans, product(datasource.Asda, "Branston Baked Beans In Tomato Sauce 410G", 0.95))
+	assert.Less(t, other, matching.DefaultMinConfidence)
+	assert.Contains(t, reasons, "different brand")
+
+	ownLabel, reasons := matching.Score(
+		product(datasource.Tesco, "Tesco Semi Skimmed Milk 2.272L/4 Pints", 1.65),
+		product(datasource.Sainsburys, "Sainsbury's British Semi Skimmed Milk 2.27L (4 pint)", 1.65),
+	)
+	assert.GreaterOrEqual(t, ownLabel, matching.DefaultMinConfidence)
+	assert.Contains(t, reasons, "own-label equivalents")
+}
+
+func TestScoreGTIN(t *testing.T) {
+	a := product(datasource.Tesco, "Tesco Japanese Style Chicken Sushi 135g", 3.30)
+	b := product(datasource.Ocado, "Tesco Sushi Chicken", 3.30)
+	a.GTIN, b.GTIN = "05063445808049", "5063445808049"
+
+	score, reasons := matching.Score(a, b)
+	assert.InDelta(t, 1, score, 0.001)
+	assert.Equal(t, []string{"same GTIN"}, reasons)
+
+	b.GTIN = "05063445786675"
+	score, _ = matching.Score(a, b)
+	assert.Zero(t, score)
+}
+
+func TestFindEquivalents(t *testing.T) {
+	target := product(datasource.Tesco, "Heinz Baked Beans In Tomato Sauce 415G", 1.40)
+	candidates := []datasource.Product{
+		product(datasource.Tesco, "Heinz Baked Beans In Tomato Sauce 4X415g", 4.50),
+		product(datasource.Asda, "Heinz Baked Beans 4 x 415g", 4.50),
+		product(datasource.Asda, "Heinz Baked Beans in Tomato Sauce 415g", 1.35),
+		product(datasource.Sainsburys, "Heinz Baked Beanz 415g", 1.40),
+		product(datasource.Waitrose, "Heinz Tomato Ketchup 460g", 2.50),
+	}
+
+	matches := matching.FindEquivalents(target, candidates, matching.DefaultMinConfidence)
+	require.Len(t, matches, 2)
+	assert.Equal(t, datasource.Asda, matches[0].Product.Supermarket)
+	assert.InDelta(t, 1.35, matches[0].Product.Price, 0.001)
+	assert.Equal(t, datasource.Sainsburys, matches[1].Product.Supermarket)
+	assert.Greater(t, matches[0].Confidence, matches[1].Confidence)
+}
+
+func TestClusterProducts(t *testing.T) {
+	clusters := matching.ClusterProducts([]datasource.Product{
+		product(datasource.Tesco, "Heinz Baked Beans In Tomato Sauce 415G", 1.40),
+		product(datasource.Tesco, "Branston Baked Beans In Tomato Sauce 410G", 1.00),
+		product(datasource.Asda, "Heinz Baked Beans in Tomato Sauce 415g", 1.35),
+		product(datasource.Asda, "Branston Baked Beans in Rich Tomato Sauce 410g", 0.95),
+		product(datasource.Ocado, "Heinz Baked Beans in Tomato Sauce 415g", 1.40),
+	}, matching.DefaultMinConfidence)
+
+	require.Len(t, clusters, 2)
+	assert.Len(t, clusters[0].Products, 3)
+	assert.Len(t, clusters[1].Products, 2)
+	assert.Equal(t, datasource.Asda, clusters[1].Products[1].Supermarket)
+}
+
+func TestSearchQuery(t *testing.T) {
+	assert.Equal(t, "heinz baked beans tomato sauce",
+		matching.SearchQuery(product(datasource.Tesco, "Heinz Baked Beans In Tomato Sauce 415G", 1.40)))
+	assert.Equal(t, "semi skimmed milk",
+		matching.SearchQuery(product(datasource.Tesco, "Tesco Semi Skimmed Milk 2.272L/4 Pints", 1.65)))
+}
@@ -0,0 +1,342 @@
+// Package pricehistory records product prices seen over time in a local
+// embedded database and tracks watched products for price drops.
+package pricehistory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+var (
+	observationsBucket = []byte("observations")
+	watchesBucket      = []byte("watches")
+)
+
+// refreshInterval is how often an unchanged price is recorded again, so
+// history shows a product was still on sale at that price.
+const refreshInterval = 24 * time.Hour
+
+// Observation is a product's price and promotion at a point in time.
+type Observation struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	ProductID   string                   `json:"productId"`
+	Name        string                   `json:"name"`
+	Price       float64                  `json:"price"`
+	UnitPrice   *datasource.UnitPrice    `json:"unitPrice,omitempty"`
+	Promotion   string                   `json:"promotion,omitempty"`
+	Time        time.Time                `json:"time"`
+}
+
+// Watch is a product being tracked for price drops.
+type Watch struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	ProductID   string                   `json:"productId"`
+	Name        string                   `json:"name,omitempty"`
+	// TargetPrice, if set, alerts whenever the price is at or below it.
+	TargetPrice float64   `json:"targetPrice,omitempty"`
+	Added       time.Time `json:"added"`
+}
+
+// Store is a price history database.
+type Store struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// DefaultPath returns the database path, from PRICE_HISTORY_DB or under the
+// user config directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("PRICE_HISTORY_DB"); path != "" {
+		return path, nil
+	}
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("get config dir: %w", err)
+	}
+	return filepath.Join(cfgDir, "supermarkets-uk-mcp", "price-history.db"), nil
+}
+
+// Open opens or creates the database at path.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create price history dir: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open price history: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{observationsBucket, watchesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init price history: %w", err)
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// productKey identifies a product; observation keys append a timestamp.
+func productKey(id datasource.SupermarketID, productID string) []byte {
+	return []byte(string(id) + "\x00" + productID + "\x00")
+}
+
+// observationKey zero-pads the timestamp so keys sort chronologically.
+func observationKey(id datasource.SupermarketID, productID string, t time.Time) []byte {
+	return fmt.Appendf(productKey(id, productID), "%020d", t.UnixNano())
+}
+
+// Record stores an observation for each priced product with an ID. A
+// product is recorded again only when its price or promotion changes, or
+// once its last observation is older than a day.
+func (s *Store) Record(products []datasource.Product) error {
+	now := s.now()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(observationsBucket)
+		for _, p := range products {
+			if p.ID == "" || p.Price <= 0 {
+				continue
+			}
+			last, ok, err := latest(b, p.Supermarket, p.ID)
+			if err != nil {
+				return err
+			}
+			if ok && unchanged(last, p) && now.Sub(last.Time) < refreshInterval {
+				continue
+			}
+			obs := Observation{
+				Supermarket: p.Supermarket,
+				ProductID:   p.ID,
+				Name:        p.Name,
+				Price:       p.Price,
+				UnitPrice:   p.UnitPrice,
+				Promotion:   p.Promotion,
+				Time:        now,
+			}
+			data, err := json.Marshal(obs)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(observationKey(p.Supermarket, p.ID, now), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func unchanged(last Observation, p datasource.Product) bool {
+	return last.Price == p.Price && last.Promotion == p.Promotion
+}
+
+// latest returns the most recent observation of a product.
+func latest(b *bolt.Bucket, id datasource.SupermarketID, productID string) (Observation, bool, error) {
+	prefix := productKey(id, productID)
+	c := b.Cursor()
+	// Seek past the last possible key for the product, then step back.
+	k, _ := c.Seek(append(append([]byte(nil), prefix...), 0xff))
+	if k == nil {
+		k, _ = c.Last()
+	} else {
+		k, _ = c.Prev()
+	}
+	if k == nil || !bytes.HasPrefix(k, prefix) {
+		return Observation{}, false, nil
+	}
+	var obs Observation
+	if err := json.Unmarshal(b.Get(k), &obs); err != nil {
+		return Observation{}, false, fmt.Errorf("decode observation: %w", err)
+	}
+	return obs, true, nil
+}
+
+// History returns a product's observations since the given time, oldest
+// first.
+func (s *Store) History(id datasource.SupermarketID, productID string, since time.Time) ([]Observation, error) {
+	var history []Observation
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := productKey(id, productID)
+		c := tx.Bucket(observationsBucket).Cursor()
+		k, v := c.Seek(observationKey(id, productID, since))
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var obs Observation
+			if err := json.Unmarshal(v, &obs); err != nil {
+				return fmt.Errorf("decode observation: %w", err)
+			}
+			history = append(history, obs)
+		}
+		return nil
+	})
+	return history, err
+}
+
+// Summary describes a product's price over a period.
+type Summary struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	ProductID   string                   `json:"productId"`
+	Name        string                   `json:"name,omitempty"`
+	Weeks       int                      `json:"weeks"`
+	Current     *Observation             `json:"current,omitempty"`
+	Lowest      float64                  `json:"lowest"`
+	Highest     float64                  `json:"highest"`
+	Average     float64                  `json:"average"`
+	// LowestInPeriod is true when the current price is the lowest seen in
+	// the period and lower than the highest, i.e. a genuine drop.
+	LowestInPeriod bool `json:"lowestInPeriod"`
+	// Promotions lists the distinct promotions seen in the period.
+	Promotions   []string      `json:"promotions,omitempty"`
+	Observations []Observation `json:"observations,omitempty"`
+}
+
+// Summarise returns a product's history and price statistics over the last
+// given number of weeks.
+func (s *Store) Summarise(id datasource.SupermarketID, productID string, weeks int) (*Summary, error) {
+	since := s.now().AddDate(0, 0, -7*weeks)
+	history, err := s.History(id, productID, since)
+	if err != nil {
+		return nil, err
+	}
+	sum := &Summary{Supermarket: id, ProductID: productID, Weeks: weeks, Observations: history}
+	if len(history) == 0 {
+		sum.Observations = []Observation{}
+		return sum, nil
+	}
+
+	seen := map[string]bool{}
+	var total float64
+	sum.Lowest, sum.Highest = history[0].Price, history[0].Price
+	for _, obs := range history {
+		sum.Lowest = min(sum.Lowest, obs.Price)
+		sum.Highest = max(sum.Highest, obs.Price)
+		total += obs.Price
+		if obs.Promotion != "" && !seen[obs.Promotion] {
+			seen[obs.Promotion] = true
+			sum.Promotions = append(sum.Promotions, obs.Promotion)
+		}
+	}
+	current := history[len(history)-1]
+	sum.Current = &current
+	sum.Name = current.Name
+	sum.Average = math.Round(total/float64(len(history))*100) / 100
+	sum.LowestInPeriod = current.Price <= sum.Lowest && current.Price < sum.Highest
+	return sum, nil
+}
+
+// Watch adds or updates a watched product.
+func (s *Store) Watch(w Watch) error {
+	if w.Added.IsZero() {
+		w.Added = s.now()
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(watchesBucket).Put(productKey(w.Supermarket, w.ProductID), data)
+	})
+}
+
+// ErrNotWatched is returned when removing a product that is not watched.
+var ErrNotWatched = errors.New("product is not watched")
+
+// Unwatch stops watching a product.
+func (s *Store) Unwatch(id datasource.SupermarketID, productID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(watchesBucket)
+		key := productKey(id, productID)
+		if b.Get(key) == nil {
+			return ErrNotWatched
+		}
+		return b.Delete(key)
+	})
+}
+
+// Watches returns all watched products, ordered by supermarket and ID.
+func (s *Store) Watches() ([]Watch, error) {
+	watches := []Watch{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(watchesBucket).ForEach(func(_, v []byte) error {
+			var w Watch
+			if err := json.Unmarshal(v, &w); err != nil {
+				return fmt.Errorf("decode watch: %w", err)
+			}
+			watches = append(watches, w)
+			return nil
+		})
+	})
+	return watches, err
+}
+
+// Alert reports a watched product that is on offer or at a low price.
+type Alert struct {
+	Watch   Watch    `json:"watch"`
+	Summary *Summary `json:"summary"`
+	Reasons []string `json:"reasons"`
+}
+
+// Alerts checks every watched product's latest price against its history
+// over the last given number of weeks. Watched products with no recorded
+// price are returned separately as unpriced.
+func (s *Store) Alerts(weeks int) ([]Alert, []Watch, error) {
+	watches, err := s.Watches()
+	if err != nil {
+		return nil, nil, err
+	}
+	alerts := []Alert{}
+	var unpriced []Watch
+	for _, w := range watches {
+		sum, err := s.Summarise(w.Supermarket, w.ProductID, weeks)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sum.Current == nil {
+			unpriced = append(unpriced, w)
+			continue
+		}
+		if reasons := alertReasons(w, sum); len(reasons) > 0 {
+			// Alerts report the current state, not the full history.
+			sum.Observations = nil
+			alerts = append(alerts, Alert{Watch: w, Summary: sum, Reasons: reasons})
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return len(alerts[i].Reasons) > len(alerts[j].Reasons)
+	})
+	return alerts, unpriced, nil
+}
+
+func alertReasons(w Watch, sum *Summary) []string {
+	var reasons []string
+	cur := sum.Current
+	if cur.Promotion != "" {
+		reasons = append(reasons, "on promotion: "+cur.Promotion)
+	}
+	if sum.LowestInPeriod {
+		reasons = append(reasons, fmt.Sprintf("lowest price in %d weeks (£%.2f, was up to £%.2f)",
+			sum.Weeks, cur.Price, sum.Highest))
+	}
+	if w.TargetPrice > 0 && cur.Price <= w.TargetPrice {
+		reasons = append(reasons, fmt.Sprintf("at or below target price £%.2f", w.TargetPrice))
+	}
+	return reasons
+}
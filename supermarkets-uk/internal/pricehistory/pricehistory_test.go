@@ -0,0 +1,148 @@
+package pricehistory
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// openTestStore opens a store in a temp dir with a controllable clock.
+func openTestStore(t *testing.T) (*Store, *time.Time) {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func milk(price float64, promo string) datasource.Product {
+	return datasource.Product{
+		ID:          "254656543",
+		Supermarket: datasource.Tesco,
+		Name:        "Tesco Semi Skimmed Milk 2.272L",
+		Price:       price,
+		Promotion:   promo,
+	}
+}
+
+func TestRecordAndHistory(t *testing.T) {
+	s, now := openTestStore(t)
+
+	require.NoError(t, s.Record([]datasource.Product{milk(1.65, ""), {Name: "no ID", Price: 1}}))
+
+	// An unchanged price within a day is not recorded again.
+	*now = now.Add(time.Hour)
+	require.NoError(t, s.Record([]datasource.Product{milk(1.65, "")}))
+
+	*now = now.Add(time.Hour)
+	require.NoError(t, s.Record([]datasource.Product{milk(1.45, "Clubcard Price")}))
+
+	*now = now.Add(2 * refreshInterval)
+	require.NoError(t, s.Record([]datasource.Product{milk(1.45, "Clubcard Price")}))
+
+	history, err := s.History(datasource.Tesco, "254656543", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.InDelta(t, 1.65, history[0].Price, 0.001)
+	assert.Equal(t, "Clubcard Price", history[1].Promotion)
+	assert.True(t, history[1].Time.Before(history[2].Time))
+
+	// Other products sharing an ID prefix are not included.
+	other := milk(2, "")
+	other.ID = "2546565"
+	require.NoError(t, s.Record([]datasource.Product{other}))
+	history, err = s.History(datasource.Tesco, "2546565", time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestSummarise(t *testing.T) {
+	s, now := openTestStore(t)
+	start := *now
+
+	for _, price := range []float64{1.65, 1.55, 1.45} {
+		require.NoError(t, s.Record([]datasource.Product{milk(price, "")}))
+		*now = now.AddDate(0, 0, 7)
+	}
+
+	sum, err := s.Summarise(datasource.Tesco, "254656543", 4)
+	require.NoError(t, err)
+	require.NotNil(t, sum.Current)
+	assert.InDelta(t, 1.45, sum.Current.Price, 0.001)
+	assert.InDelta(t, 1.45, sum.Lowest, 0.001)
+	assert.InDelta(t, 1.65, sum.Highest, 0.001)
+	assert.InDelta(t, 1.55, sum.Average, 0.001)
+	assert.True(t, sum.LowestInPeriod)
+	assert.Len(t, sum.Observations, 3)
+
+	// The oldest observation falls outside a two-week window.
+	sum, err = s.Summarise(datasource.Tesco, "254656543", 2)
+	require.NoError(t, err)
+	assert.Len(t, sum.Observations, 2)
+	assert.True(t, sum.Observations[0].Time.After(start))
+
+	sum, err = s.Summarise(datasource.Tesco, "missing", 4)
+	require.NoError(t, err)
+	assert.Nil(t, sum.Current)
+}
+
+func TestWatchesAndAlerts(t *testing.T) {
+	s, now := openTestStore(t)
+
+	require.NoError(t, s.Watch(Watch{Supermarket: datasource.Tesco, ProductID: "254656543"}))
+	require.NoError(t, s.Watch(Watch{Supermarket: datasource.Asda, ProductID: "123", TargetPrice: 1}))
+
+	watches, err := s.Watches()
+	require.NoError(t, err)
+	assert.Len(t, watches, 2)
+
+	require.NoError(t, s.Record([]datasource.Product{milk(1.65, "")}))
+	*now = now.AddDate(0, 0, 7)
+	require.NoError(t, s.Record([]datasource.Product{milk(1.65, "")}))
+
+	alerts, unpriced, err := s.Alerts(8)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	require.Len(t, unpriced, 1)
+	assert.Equal(t, "123", unpriced[0].ProductID)
+
+	*now = now.Add(time.Hour)
+	require.NoError(t, s.Record([]datasource.Product{milk(1.25, "Clubcard Price")}))
+
+	alerts, _, err = s.Alerts(8)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Len(t, alerts[0].Reasons, 2)
+	assert.Contains(t, alerts[0].Reasons[0], "Clubcard Price")
+	assert.Contains(t, alerts[0].Reasons[1], "lowest price in 8 weeks")
+
+	require.NoError(t, s.Unwatch(datasource.Tesco, "254656543"))
+	require.ErrorIs(t, s.Unwatch(datasource.Tesco, "254656543"), ErrNotWatched)
+	alerts, _, err = s.Alerts(8)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestOpenPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	s, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Watch(Watch{Supermarket: datasource.Tesco, ProductID: "1", TargetPrice: 2}))
+	require.NoError(t, s.Close())
+
+	s, err = Open(path)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	watches, err := s.Watches()
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	assert.InDelta(t, 2, watches[0].TargetPrice, 0.001)
+}
@@ -4,12 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/dietary"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/healthcheck"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/matching"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/pricehistory"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/shopping"
 )
 
 func formatSearchResults(results []datasource.SearchResult) (*mcp.CallToolResult, error) {
@@ -30,13 +37,64 @@ func formatSearchResults(results []datasource.SearchResult) (*mcp.CallToolResult
 	return mcp.NewToolResultText(msg), nil
 }
 
-func formatPriceComparison(query string, results []datasource.SearchResult) (*mcp.CallToolResult, error) {
+func formatFilteredSearchResults(
+	filter dietary.Filter, results []filteredSearchResult,
+) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format results: %v", err)), nil
+	}
+
+	total, checked := 0, 0
+	for _, r := range results {
+		total += len(r.Products)
+		checked += r.Checked
+	}
+
+	msg := fmt.Sprintf(
+		"Found %d product(s) matching %s among the top %d result(s) from %d supermarket(s):\n\n%s",
+		total, filter, checked, len(results), string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
+// comparedProduct is a product priced for a compare_prices quantity, with
+// any promotion applied.
+type comparedProduct struct {
+	datasource.Product
+	// Each is the effective price per item after offers.
+	Each float64
+	// Unit is the effective unit price after offers, if known.
+	Unit  *datasource.UnitPrice
+	Offer *datasource.Offer
+}
+
+func compareProduct(p datasource.Product, qty int, loyalty bool) comparedProduct {
+	cost, offer := datasource.EffectiveCost(p, qty, loyalty)
+	c := comparedProduct{Product: p, Each: p.Price, Unit: p.UnitPrice, Offer: offer}
+	if offer != nil && p.Price > 0 {
+		c.Each = cost / float64(qty)
+		if p.UnitPrice != nil {
+			unit := *p.UnitPrice
+			unit.Price = math.Round(unit.Price*c.Each/p.Price*10000) / 10000
+			c.Unit = &unit
+		}
+	}
+	return c
+}
+
+func formatPriceComparison(
+	query string, results []datasource.SearchResult, qty int, loyalty bool,
+) (*mcp.CallToolResult, error) {
 	var sb strings.Builder
-	fmt.Fprintf(&sb, "Price comparison for \"%s\":\n\n", query)
+	fmt.Fprintf(&sb, "Price comparison for \"%s\"", query)
+	if qty > 1 {
+		fmt.Fprintf(&sb, " when buying %d", qty)
+	}
+	sb.WriteString(":\n\n")
 
-	cheapestPrice := math.MaxFloat64
-	cheapestStore := ""
-	cheapestProduct := ""
+	per := commonUnit(results)
+	var ranked []comparedProduct
 
 	for _, r := range results {
 		fmt.Fprintf(&sb, "## %s\n", r.Supermarket)
@@ -48,30 +106,130 @@ func formatPriceComparison(query string, results []datasource.SearchResult) (*mc
 			sb.WriteString("  No products found.\n\n")
 			continue
 		}
-		for _, p := range r.Products {
-			fmt.Fprintf(&sb, "  - %s: £%.2f", p.Name, p.Price)
-			if p.PricePerUnit != "" {
-				fmt.Fprintf(&sb, " (%s)", p.PricePerUnit)
-			}
-			if p.Promotion != "" {
-				fmt.Fprintf(&sb, " [%s]", p.Promotion)
-			}
-			sb.WriteString("\n")
-
-			if p.Price > 0 && p.Price < cheapestPrice {
-				cheapestPrice = p.Price
-				cheapestStore = string(r.Supermarket)
-				cheapestProduct = p.Name
+		products := make([]comparedProduct, len(r.Products))
+		for i, p := range r.Products {
+			products[i] = compareProduct(p, qty, loyalty)
+		}
+		sortByUnitPrice(products, per)
+		for _, c := range products {
+			writeComparedProduct(&sb, c)
+			if c.Price > 0 {
+				ranked = append(ranked, c)
 			}
 		}
 		sb.WriteString("\n")
 	}
 
-	if cheapestStore != "" {
-		fmt.Fprintf(&sb, "**Cheapest:** %s at %s (£%.2f)\n", cheapestProduct, cheapestStore, cheapestPrice)
+	writeUnitPriceRanking(&sb, ranked, per)
+	writeSameProducts(&sb, ranked)
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func writeComparedProduct(sb *strings.Builder, c comparedProduct) {
+	fmt.Fprintf(sb, "  - %s: £%.2f", c.Name, c.Price)
+	if c.Offer != nil {
+		fmt.Fprintf(sb, " → £%.2f each with %q", c.Each, c.Offer.Text)
 	}
+	if c.Unit != nil {
+		fmt.Fprintf(sb, " (%s)", c.Unit)
+	} else if c.PricePerUnit != "" {
+		fmt.Fprintf(sb, " (%s)", c.PricePerUnit)
+	}
+	if c.Promotion != "" && c.Offer == nil {
+		fmt.Fprintf(sb, " [%s]", c.Promotion)
+	}
+	sb.WriteString("\n")
+}
 
-	return mcp.NewToolResultText(sb.String()), nil
+// writeUnitPriceRanking highlights the best value product and lists the
+// cheapest by unit price across all stores. Without comparable unit prices
+// it falls back to the lowest price per item.
+func writeUnitPriceRanking(sb *strings.Builder, ranked []comparedProduct, per string) {
+	if len(ranked) == 0 {
+		return
+	}
+	sortByUnitPrice(ranked, per)
+	best := ranked[0]
+	if per == "" {
+		fmt.Fprintf(sb, "**Cheapest:** %s at %s (£%.2f)\n", best.Name, best.Supermarket, best.Each)
+		return
+	}
+
+	fmt.Fprintf(sb, "**Best value (per %s):** %s at %s (%s, £%.2f)\n",
+		per, best.Name, best.Supermarket, best.Unit, best.Each)
+	sb.WriteString("\nRanked by unit price:\n")
+	for i, c := range ranked {
+		if i == maxRankedProducts || c.Unit == nil || c.Unit.Per != per {
+			break
+		}
+		fmt.Fprintf(sb, "%d. %s — %s at %s (£%.2f)\n", i+1, c.Unit, c.Name, c.Supermarket, c.Each)
+	}
+}
+
+// maxRankedProducts bounds the cross-store unit price ranking and the list
+// of products found at several stores.
+const maxRankedProducts = 10
+
+// writeSameProducts lists products found at more than one store, so that
+// their prices can be compared directly.
+func writeSameProducts(sb *strings.Builder, ranked []comparedProduct) {
+	products := make([]datasource.Product, len(ranked))
+	for i, c := range ranked {
+		products[i] = c.Product
+	}
+	written := 0
+	for _, cluster := range matching.ClusterProducts(products, matching.DefaultMinConfidence) {
+		if len(cluster.Products) < 2 || written == maxRankedProducts {
+			continue
+		}
+		if written == 0 {
+			sb.WriteString("\nSame product at several stores:\n")
+		}
+		written++
+		prices := make([]string, len(cluster.Products))
+		for i, p := range cluster.Products {
+			prices[i] = fmt.Sprintf("%s £%.2f", p.Supermarket, p.Price)
+		}
+		fmt.Fprintf(sb, "- %s: %s (confidence %.2f)\n",
+			cluster.Products[0].Name, strings.Join(prices, ", "), cluster.Confidence)
+	}
+}
+
+// commonUnit returns the unit most products are priced per, so that only
+// like-for-like unit prices are compared. It is empty if none have one.
+func commonUnit(results []datasource.SearchResult) string {
+	counts := map[string]int{}
+	best := ""
+	for _, r := range results {
+		for _, p := range r.Products {
+			if p.UnitPrice == nil {
+				continue
+			}
+			counts[p.UnitPrice.Per]++
+			if c := counts[p.UnitPrice.Per]; c > counts[best] || (c == counts[best] && p.UnitPrice.Per < best) {
+				best = p.UnitPrice.Per
+			}
+		}
+	}
+	return best
+}
+
+// sortByUnitPrice orders products priced per unit "per" cheapest first,
+// followed by the rest by price per item, all after offers.
+func sortByUnitPrice(products []comparedProduct, per string) {
+	comparable := func(c comparedProduct) bool {
+		return c.Unit != nil && c.Unit.Per == per
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if comparable(a) != comparable(b) {
+			return comparable(a)
+		}
+		if comparable(a) && a.Unit.Price != b.Unit.Price {
+			return a.Unit.Price < b.Unit.Price
+		}
+		return a.Each < b.Each
+	})
 }
 
 func formatCategories(categories []datasource.Category) (*mcp.CallToolResult, error) {
@@ -119,6 +277,102 @@ func formatOrderHistory(result *datasource.OrderHistoryResult) (*mcp.CallToolRes
 	return mcp.NewToolResultText(msg), nil
 }
 
+// mergedOrderHistory is order history merged across supermarkets.
+type mergedOrderHistory struct {
+	Page   int                `json:"page"`
+	Orders []datasource.Order `json:"orders"`
+	Errors []storeError       `json:"errors,omitempty"`
+}
+
+type storeError struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	Error       string                   `json:"error"`
+}
+
+func formatMergedOrderHistory(
+	page int, results []datasource.OrderHistoryResult,
+) (*mcp.CallToolResult, error) {
+	merged := mergedOrderHistory{Page: page, Orders: datasource.MergeOrders(results)}
+	stores := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Error != "" {
+			merged.Errors = append(merged.Errors, storeError{Supermarket: r.Supermarket, Error: r.Error})
+			continue
+		}
+		stores = append(stores, string(r.Supermarket))
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format order history: %v", err),
+		), nil
+	}
+	from := "none"
+	if len(stores) > 0 {
+		from = strings.Join(stores, ", ")
+	}
+	msg := fmt.Sprintf(
+		"Order history across %d supermarkets (page %d, %d orders from %s):\n\n%s",
+		len(results), page, len(merged.Orders), from, string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
+// slotList is the slots found across one or more supermarkets.
+type slotList struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Slots  []datasource.Slot `json:"slots"`
+	Errors []storeError      `json:"errors,omitempty"`
+}
+
+func formatSlots(from, to time.Time, results []datasource.SlotResult) (*mcp.CallToolResult, error) {
+	list := slotList{
+		From:  from.Format(datasource.OrderDateLayout),
+		To:    to.Format(datasource.OrderDateLayout),
+		Slots: datasource.MergeSlots(results),
+	}
+	stores := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Error != "" {
+			list.Errors = append(list.Errors, storeError{Supermarket: r.Supermarket, Error: r.Error})
+			continue
+		}
+		stores = append(stores, string(r.Supermarket))
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format slots: %v", err),
+		), nil
+	}
+	at := "none"
+	if len(stores) > 0 {
+		at = strings.Join(stores, ", ")
+	}
+	msg := fmt.Sprintf(
+		"%d slot(s) from %s to %s at %s:\n\n%s",
+		len(list.Slots), list.From, list.To, at, string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
+func formatBookedSlot(slot *datasource.Slot) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(slot, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format slot: %v", err),
+		), nil
+	}
+	msg := fmt.Sprintf(
+		"Booked %s %s slot %s (£%.2f):\n\n%s",
+		slot.Supermarket, slot.Method, slot.Description, slot.Price, string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
 func formatBasket(basket *datasource.Basket) (*mcp.CallToolResult, error) {
 	data, err := json.MarshalIndent(basket, "", "  ")
 	if err != nil {
@@ -134,6 +388,127 @@ func formatBasket(basket *datasource.Basket) (*mcp.CallToolResult, error) {
 	return mcp.NewToolResultText(msg), nil
 }
 
+func formatReorder(r *shopping.Reorder) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format reorder: %v", err),
+		), nil
+	}
+
+	header := "Reorder preview"
+	if !r.Preview {
+		header = "Reordered"
+	}
+	summary := r.Describe()
+	if r.Warning != "" {
+		summary += "; " + r.Warning
+	}
+	msg := fmt.Sprintf(
+		"%s of %s order %s: %s, total £%.2f:\n\n%s",
+		header, r.Supermarket, r.OrderID, summary, r.Total, string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
+func formatEquivalents(result equivalents) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format equivalents: %v", err),
+		), nil
+	}
+	msg := fmt.Sprintf(
+		"Found %d match(es) for %s at %s (searched for %q):\n\n%s",
+		len(result.Matches), result.Product.Name, result.Product.Supermarket, result.Query,
+		string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
+func formatShoppingPlan(plan *shopping.Plan) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format shopping plan: %v", err),
+		), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Shopping plan for %d item(s):\n", len(plan.Items))
+	if b := plan.CheapestSingleStore; b != nil {
+		fmt.Fprintf(&sb, "- Cheapest single store: %s, £%.2f\n", b.Stores[0], b.Total)
+	} else {
+		sb.WriteString("- No single store can supply the whole list.\n")
+	}
+	if b := plan.CheapestSplit; b != nil {
+		stores := make([]string, len(b.Stores))
+		for i, id := range b.Stores {
+			stores[i] = string(id)
+		}
+		fmt.Fprintf(&sb, "- Cheapest split (max %d stores): %s, £%.2f\n",
+			plan.MaxStores, strings.Join(stores, " + "), b.Total)
+	}
+	if len(plan.Unmatched) > 0 {
+		fmt.Fprintf(&sb, "- Not found anywhere: %s\n", strings.Join(plan.Unmatched, ", "))
+	}
+	fmt.Fprintf(&sb, "\n%s", string(data))
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func formatPriceHistory(sum *pricehistory.Summary) (*mcp.CallToolResult, error) {
+	if sum.Current == nil {
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"No prices recorded for %s at %s in the last %d weeks. "+
+				"Prices are recorded when the product appears in searches or product details.",
+			sum.ProductID, sum.Supermarket, sum.Weeks,
+		)), nil
+	}
+	data, err := json.MarshalIndent(sum, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format price history: %v", err),
+		), nil
+	}
+	msg := fmt.Sprintf(
+		"Price history for %s at %s over %d weeks (%d observation(s), now £%.2f, low £%.2f, high £%.2f):\n\n%s",
+		sum.Name, sum.Supermarket, sum.Weeks, len(sum.Observations),
+		sum.Current.Price, sum.Lowest, sum.Highest, string(data),
+	)
+	return mcp.NewToolResultText(msg), nil
+}
+
+func formatWatch(w pricehistory.Watch, product *datasource.Product) (*mcp.CallToolResult, error) {
+	msg := fmt.Sprintf("Watching %s at %s (currently £%.2f", w.Name, w.Supermarket, product.Price)
+	if product.Promotion != "" {
+		msg += ", " + product.Promotion
+	}
+	msg += ")"
+	if w.TargetPrice > 0 {
+		msg += fmt.Sprintf(", alerting at or below £%.2f", w.TargetPrice)
+	}
+	return mcp.NewToolResultText(msg + "."), nil
+}
+
+func formatPriceAlerts(
+	alerts []pricehistory.Alert, unpriced []pricehistory.Watch, refreshErrors []string,
+) (*mcp.CallToolResult, error) {
+	result := struct {
+		Alerts        []pricehistory.Alert `json:"alerts"`
+		Unpriced      []pricehistory.Watch `json:"unpriced,omitempty"`
+		RefreshErrors []string             `json:"refreshErrors,omitempty"`
+	}{alerts, unpriced, refreshErrors}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to format price alerts: %v", err),
+		), nil
+	}
+	msg := fmt.Sprintf("%d watched product(s) with alerts:\n\n%s", len(alerts), string(data))
+	return mcp.NewToolResultText(msg), nil
+}
+
 func formatSupermarkets(infos []client.SupermarketInfo) (*mcp.CallToolResult, error) {
 	data, err := json.MarshalIndent(infos, "", "  ")
 	if err != nil {
@@ -141,3 +516,19 @@ func formatSupermarkets(infos []client.SupermarketInfo) (*mcp.CallToolResult, er
 	}
 	return mcp.NewToolResultText(fmt.Sprintf("Supported supermarkets:\n\n%s", string(data))), nil
 }
+
+func formatHealthcheck(results []healthcheck.Result) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format healthcheck: %v", err)), nil
+	}
+
+	healthy := 0
+	for _, r := range results {
+		if r.Healthy {
+			healthy++
+		}
+	}
+	msg := fmt.Sprintf("%d of %d supermarket(s) healthy:\n\n%s", healthy, len(results), string(data))
+	return mcp.NewToolResultText(msg), nil
+}
@@ -3,11 +3,14 @@ package server
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/cache"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
 	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/shopping"
 )
 
 func (s *Server) registerTools() {
@@ -15,7 +18,10 @@ func (s *Server) registerTools() {
 		mcp.WithDescription(
 			"Search for grocery products across UK supermarkets. "+
 				"Returns products with prices, promotions, and availability. "+
-				"The 'available' field is true/false when known, or omitted when unknown."),
+				"The 'available' field is true/false when known, or omitted when unknown. "+
+				"Dietary, allergen, and nutrition filters check each supermarket's top 10 results "+
+				"against their ingredients and nutrition per 100g, leaving out products that fail "+
+				"or lack the information to tell."),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("Search term for products (e.g. 'milk', 'bread', 'chicken breast')"),
@@ -26,18 +32,71 @@ func (s *Server) registerTools() {
 					"Use list_supermarkets to see all available IDs. "+
 					"Omit to search all."),
 		),
+		mcp.WithString("diet",
+			mcp.Description(
+				"Comma-separated dietary requirements: vegan, vegetarian, gluten-free, dairy-free."),
+		),
+		mcp.WithString("excludeAllergens",
+			mcp.Description(
+				"Comma-separated allergens the products must not contain (e.g. 'milk, peanuts, sesame')."),
+		),
+		mcp.WithString("nutrition",
+			mcp.Description(
+				"Comma-separated nutrition limits per 100g, in grams or kcal for energy "+
+					"(e.g. 'sugar<5g, salt<=0.3g, protein>10g, energy<200kcal')."),
+		),
+		freshOption(),
 	), s.handleSearchProducts)
 
 	s.mcpServer.AddTool(mcp.NewTool("compare_prices",
 		mcp.WithDescription(
 			"Compare prices for a product across all UK supermarkets. "+
-				"Searches all supermarkets and highlights the cheapest option."),
+				"Searches all supermarkets and ranks products by normalised unit price "+
+				"(per kg, litre, or item) to highlight the best value. "+
+				"Multi-buy and loyalty card offers are applied for the quantity being bought. "+
+				"Products found at more than one store are listed together."),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("Product to compare prices for (e.g. 'semi skimmed milk 2 pint')"),
 		),
+		mcp.WithNumber("quantity",
+			mcp.Description("Number of items being bought, for multi-buy offers (default 1)."),
+		),
+		mcp.WithBoolean("loyaltyPrices",
+			mcp.Description("Apply loyalty card prices such as Clubcard and Nectar Prices (default true)."),
+		),
+		freshOption(),
 	), s.handleComparePrices)
 
+	s.mcpServer.AddTool(mcp.NewTool("plan_shopping",
+		mcp.WithDescription(
+			"Plan the cheapest way to buy a whole shopping list across UK supermarkets. "+
+				"Searches every store for each item, matches products by pack size and unit, "+
+				"and returns the cheapest single-store basket, the cheapest split across at most "+
+				"maxStores stores, and per-item alternatives. Multi-buy and loyalty offers "+
+				"are applied to each item's cost. "+
+				"Baskets honour delivery minimums configured with <ID>_DELIVERY_MINIMUM."),
+		mcp.WithString("list",
+			mcp.Required(),
+			mcp.Description(
+				"Shopping list separated by commas or newlines, with optional sizes or counts "+
+					"(e.g. '2L semi-skimmed milk, 500g penne, 6 eggs')"),
+		),
+		mcp.WithNumber("maxStores",
+			mcp.Description("Maximum number of stores to split the list across (default 2)."),
+		),
+		mcp.WithBoolean("loyaltyPrices",
+			mcp.Description("Apply loyalty card prices such as Clubcard and Nectar Prices (default true)."),
+		),
+		mcp.WithString("supermarkets",
+			mcp.Description(
+				"Comma-separated supermarket IDs to consider. "+
+					"Use list_supermarkets to see all available IDs. "+
+					"Omit to consider all."),
+		),
+		freshOption(),
+	), s.handlePlanShopping)
+
 	s.mcpServer.AddTool(mcp.NewTool("browse_categories",
 		mcp.WithDescription("Browse product categories for a specific supermarket."),
 		mcp.WithString("supermarket",
@@ -59,16 +118,18 @@ func (s *Server) registerTools() {
 			mcp.Required(),
 			mcp.Description("Product ID from search results"),
 		),
+		freshOption(),
 	), s.handleGetProductDetails)
 
 	s.mcpServer.AddTool(mcp.NewTool("get_order_history",
 		mcp.WithDescription(
-			"Get past grocery order history. Supported supermarkets: tesco. "+
+			"Get past grocery order history. "+
+				"Supported supermarkets: tesco, sainsburys, ocado, morrisons, asda, waitrose. "+
 				"Requires a logged-in session. "+
-				"Returns orders with items, totals, delivery slots, and status."),
+				"Returns orders with items, totals, delivery slots, and status. "+
+				"Omit supermarket to merge orders from every logged-in supermarket, newest first."),
 		mcp.WithString("supermarket",
-			mcp.Required(),
-			mcp.Description("Supermarket ID — must be 'tesco'."),
+			mcp.Description("Supermarket ID, e.g. 'tesco'. Omit or use 'all' for every logged-in supermarket."),
 		),
 		mcp.WithNumber("page",
 			mcp.Description("Page number for pagination (default 1, 10 orders per page)."),
@@ -77,24 +138,25 @@ func (s *Server) registerTools() {
 
 	s.mcpServer.AddTool(mcp.NewTool("get_basket",
 		mcp.WithDescription(
-			"Get the current shopping basket contents. Supported supermarkets: tesco. "+
+			"Get the current shopping basket contents. "+
+				"Supported supermarkets: tesco, sainsburys, ocado, morrisons. "+
 				"Requires a logged-in session. "+
 				"Returns items with quantities, prices, and totals."),
 		mcp.WithString("supermarket",
 			mcp.Required(),
-			mcp.Description("Supermarket ID — must be 'tesco'."),
+			mcp.Description("Supermarket ID: 'tesco', 'sainsburys', 'ocado', or 'morrisons'."),
 		),
 	), s.handleGetBasket)
 
 	s.mcpServer.AddTool(mcp.NewTool("add_to_basket",
 		mcp.WithDescription(
 			"Add a product to the shopping basket or update its quantity. "+
-				"Supported supermarkets: tesco. "+
+				"Supported supermarkets: tesco, sainsburys, ocado, morrisons. "+
 				"Use product IDs from search results. "+
 				"Requires a logged-in session."),
 		mcp.WithString("supermarket",
 			mcp.Required(),
-			mcp.Description("Supermarket ID — must be 'tesco'."),
+			mcp.Description("Supermarket ID: 'tesco', 'sainsburys', 'ocado', or 'morrisons'."),
 		),
 		mcp.WithString("productId",
 			mcp.Required(),
@@ -108,11 +170,11 @@ func (s *Server) registerTools() {
 	s.mcpServer.AddTool(mcp.NewTool("remove_from_basket",
 		mcp.WithDescription(
 			"Remove a product from the shopping basket. "+
-				"Supported supermarkets: tesco. "+
+				"Supported supermarkets: tesco, sainsburys, ocado, morrisons. "+
 				"Requires a logged-in session."),
 		mcp.WithString("supermarket",
 			mcp.Required(),
-			mcp.Description("Supermarket ID — must be 'tesco'."),
+			mcp.Description("Supermarket ID: 'tesco', 'sainsburys', 'ocado', or 'morrisons'."),
 		),
 		mcp.WithString("productId",
 			mcp.Required(),
@@ -120,6 +182,12 @@ func (s *Server) registerTools() {
 		),
 	), s.handleRemoveFromBasket)
 
+	s.registerMatchingTools()
+	s.registerSlotTools()
+	s.registerReorderTools()
+	s.registerPriceHistoryTools()
+	s.registerHealthcheckTools()
+
 	s.mcpServer.AddTool(mcp.NewTool("list_supermarkets",
 		mcp.WithDescription("List all supported UK supermarkets with their IDs and status."),
 	), s.handleListSupermarkets)
@@ -130,6 +198,7 @@ func (s *Server) handleSearchProducts(
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
+	ctx = withFresh(ctx, args)
 
 	query, ok := args["query"].(string)
 	if !ok || query == "" {
@@ -141,21 +210,111 @@ func (s *Server) handleSearchProducts(
 		supermarkets = client.ParseSupermarketIDs(v)
 	}
 
+	filter, err := parseDietaryFilter(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	results := s.client.SearchAll(ctx, query, supermarkets)
-	return formatSearchResults(results)
+	if filter.Empty() {
+		return formatSearchResults(results)
+	}
+	return formatFilteredSearchResults(filter, s.filterSearchResults(ctx, results, filter))
 }
 
 func (s *Server) handleComparePrices(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	query, ok := request.GetArguments()["query"].(string)
+	args := request.GetArguments()
+	ctx = withFresh(ctx, args)
+
+	query, ok := args["query"].(string)
 	if !ok || query == "" {
 		return mcp.NewToolResultError("query is required"), nil
 	}
 
+	quantity := 1
+	if v, ok := args["quantity"].(float64); ok && v > 0 {
+		quantity = int(v)
+	}
+	loyalty := true
+	if v, ok := args["loyaltyPrices"].(bool); ok {
+		loyalty = v
+	}
+
 	results := s.client.SearchAll(ctx, query, nil)
-	return formatPriceComparison(query, results)
+	return formatPriceComparison(query, results, quantity, loyalty)
+}
+
+// freshOption is the argument that bypasses the response cache.
+func freshOption() mcp.ToolOption {
+	return mcp.WithBoolean("fresh",
+		mcp.Description("Fetch from the supermarkets instead of reusing recently cached results (default false)."),
+	)
+}
+
+// withFresh returns ctx set to bypass the response cache if the fresh
+// argument is true.
+func withFresh(ctx context.Context, args map[string]any) context.Context {
+	if fresh, _ := args["fresh"].(bool); fresh {
+		return cache.WithFresh(ctx)
+	}
+	return ctx
+}
+
+// planSearchConcurrency bounds how many list items are searched at once;
+// each search already fans out to every supermarket.
+const planSearchConcurrency = 3
+
+func (s *Server) handlePlanShopping(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	ctx = withFresh(ctx, args)
+
+	list, _ := args["list"].(string)
+	items := shopping.ParseList(list)
+	if len(items) == 0 {
+		return mcp.NewToolResultError("list is required"), nil
+	}
+
+	maxStores := 2
+	if v, ok := args["maxStores"].(float64); ok && v >= 1 {
+		maxStores = int(v)
+	}
+
+	loyalty := true
+	if v, ok := args["loyaltyPrices"].(bool); ok {
+		loyalty = v
+	}
+
+	var supermarkets []datasource.SupermarketID
+	if v, ok := args["supermarkets"].(string); ok && v != "" {
+		supermarkets = client.ParseSupermarketIDs(v)
+	}
+
+	results := make([][]datasource.SearchResult, len(items))
+	sem := make(chan struct{}, planSearchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = s.client.SearchAll(ctx, item.Query, supermarkets)
+		}()
+	}
+	wg.Wait()
+
+	plan := shopping.MakePlan(items, results, shopping.Options{
+		MaxStores:     maxStores,
+		Minimums:      s.client.DeliveryMinimums(),
+		LoyaltyPrices: loyalty,
+	})
+	return formatShoppingPlan(plan)
 }
 
 func (s *Server) handleBrowseCategories(
@@ -181,6 +340,7 @@ func (s *Server) handleGetProductDetails(
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
+	ctx = withFresh(ctx, args)
 
 	supermarketID, ok := args["supermarket"].(string)
 	if !ok || supermarketID == "" {
@@ -207,16 +367,22 @@ func (s *Server) handleGetOrderHistory(
 ) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	supermarketID, ok := args["supermarket"].(string)
-	if !ok || supermarketID == "" {
-		return mcp.NewToolResultError("supermarket is required"), nil
-	}
-
 	page := 1
 	if v, ok := args["page"].(float64); ok && v > 0 {
 		page = int(v)
 	}
 
+	supermarketID, _ := args["supermarket"].(string)
+	if supermarketID == "" || supermarketID == "all" {
+		results := s.client.GetAllOrderHistory(ctx, page)
+		if len(results) == 0 {
+			return mcp.NewToolResultError(
+				"no logged-in supermarkets support order history; " +
+					"enable login with <SUPERMARKET>_LOGIN=true"), nil
+		}
+		return formatMergedOrderHistory(page, results)
+	}
+
 	sid := datasource.SupermarketID(supermarketID)
 	result, err := s.client.GetOrderHistory(ctx, sid, page)
 	if err != nil {
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/dietary"
+)
+
+// dietaryCandidates is how many of each supermarket's top search results
+// are checked against dietary filters. Checking a result usually needs a
+// product details request, since search results rarely include
+// ingredients or nutrition.
+const dietaryCandidates = 10
+
+// dietaryDetailsConcurrency bounds how many product details requests are
+// made at once while checking search results.
+const dietaryDetailsConcurrency = 4
+
+// filteredSearchResult is one supermarket's search results after dietary
+// filtering.
+type filteredSearchResult struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	Products    []datasource.Product     `json:"products"`
+	// Checked is how many top results were checked; the rest were not.
+	Checked  int `json:"checked"`
+	Excluded int `json:"excluded"`
+	// Unverified is how many checked results lacked the ingredients or
+	// nutrition to tell, and were left out.
+	Unverified int    `json:"unverified"`
+	Error      string `json:"error,omitempty"`
+}
+
+// parseDietaryFilter reads the search_products dietary arguments.
+func parseDietaryFilter(args map[string]any) (dietary.Filter, error) {
+	var f dietary.Filter
+	if v, ok := args["diet"].(string); ok {
+		for _, d := range splitList(v) {
+			diet, err := dietary.NormaliseDiet(d)
+			if err != nil {
+				return dietary.Filter{}, err
+			}
+			f.Diets = append(f.Diets, diet)
+		}
+	}
+	if v, ok := args["excludeAllergens"].(string); ok {
+		for _, a := range splitList(v) {
+			f.Allergens = append(f.Allergens, dietary.NormaliseAllergen(a))
+		}
+	}
+	if v, ok := args["nutrition"].(string); ok {
+		thresholds, err := dietary.ParseThresholds(v)
+		if err != nil {
+			return dietary.Filter{}, err
+		}
+		f.Thresholds = thresholds
+	}
+	return f, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// filterSearchResults checks each supermarket's top results against f,
+// fetching product details for those the search result alone cannot
+// decide on.
+func (s *Server) filterSearchResults(
+	ctx context.Context, results []datasource.SearchResult, f dietary.Filter,
+) []filteredSearchResult {
+	verdicts := make([][]dietary.Verdict, len(results))
+	sem := make(chan struct{}, dietaryDetailsConcurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		candidates := results[i].Products[:min(dietaryCandidates, len(results[i].Products))]
+		verdicts[i] = make([]dietary.Verdict, len(candidates))
+		for j := range candidates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				candidates[j], verdicts[i][j] = s.checkProduct(ctx, candidates[j], f)
+			}()
+		}
+	}
+	wg.Wait()
+
+	filtered := make([]filteredSearchResult, len(results))
+	for i, r := range results {
+		fr := filteredSearchResult{
+			Supermarket: r.Supermarket, Products: []datasource.Product{},
+			Checked: len(verdicts[i]), Error: r.Error,
+		}
+		for j, v := range verdicts[i] {
+			switch v {
+			case dietary.Pass:
+				fr.Products = append(fr.Products, r.Products[j])
+			case dietary.Fail:
+				fr.Excluded++
+			default:
+				fr.Unverified++
+			}
+		}
+		filtered[i] = fr
+	}
+	return filtered
+}
+
+// checkProduct checks p against f, first with what the search returned and
+// then, if that is not enough, with its product details.
+func (s *Server) checkProduct(
+	ctx context.Context, p datasource.Product, f dietary.Filter,
+) (datasource.Product, dietary.Verdict) {
+	if v, _ := f.Check(p); v != dietary.Unknown {
+		return p, v
+	}
+	details, err := s.client.GetProductDetails(ctx, p.Supermarket, p.ID)
+	if err != nil {
+		return p, dietary.Unknown
+	}
+	p = dietary.WithDetails(p, details)
+	v, _ := f.Check(p)
+	return p, v
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/healthcheck"
+)
+
+func (s *Server) registerHealthcheckTools() {
+	s.mcpServer.AddTool(mcp.NewTool("healthcheck",
+		mcp.WithDescription(
+			"Check that each supermarket's search still parses correctly. Runs a canned search "+
+				"at each store, bypassing the cache, and reports stores that fail, return too few "+
+				"products, or return products missing names, prices, or URLs. "+
+				"Use when a store's results look empty or incomplete, as a sign its website has changed."),
+		mcp.WithString("supermarkets",
+			mcp.Description(
+				"Comma-separated supermarket IDs to check. "+
+					"Use list_supermarkets to see all available IDs. "+
+					"Omit to check all."),
+		),
+	), s.handleHealthcheck)
+}
+
+func (s *Server) handleHealthcheck(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	var ids []datasource.SupermarketID
+	if v, ok := args["supermarkets"].(string); ok {
+		ids = client.ParseSupermarketIDs(v)
+	}
+	return formatHealthcheck(healthcheck.Run(ctx, s.client, ids))
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/cache"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/pricehistory"
+)
+
+const (
+	defaultHistoryWeeks = 12
+	defaultAlertWeeks   = 8
+)
+
+func (s *Server) registerPriceHistoryTools() {
+	s.mcpServer.AddTool(mcp.NewTool("get_price_history",
+		mcp.WithDescription(
+			"Get the recorded price history of a product. Prices are recorded locally "+
+				"whenever a product is seen in search results or product details. "+
+				"Returns each observed price and promotion, with the lowest, highest, "+
+				"and average price over the period."),
+		mcp.WithString("supermarket",
+			mcp.Required(),
+			mcp.Description("Supermarket ID. Use list_supermarkets to see all available IDs."),
+		),
+		mcp.WithString("productId",
+			mcp.Required(),
+			mcp.Description("Product ID from search results"),
+		),
+		mcp.WithNumber("weeks",
+			mcp.Description("Number of weeks of history to return (default 12)."),
+		),
+	), s.handleGetPriceHistory)
+
+	s.mcpServer.AddTool(mcp.NewTool("watch_product",
+		mcp.WithDescription(
+			"Watch a product for price drops and promotions, or stop watching it. "+
+				"Use check_price_alerts to see which watched products are on offer."),
+		mcp.WithString("supermarket",
+			mcp.Required(),
+			mcp.Description("Supermarket ID. Use list_supermarkets to see all available IDs."),
+		),
+		mcp.WithString("productId",
+			mcp.Required(),
+			mcp.Description("Product ID from search results"),
+		),
+		mcp.WithNumber("targetPrice",
+			mcp.Description("Optional price in pounds at or below which to always alert."),
+		),
+		mcp.WithBoolean("remove",
+			mcp.Description("Stop watching the product instead (default false)."),
+		),
+	), s.handleWatchProduct)
+
+	s.mcpServer.AddTool(mcp.NewTool("check_price_alerts",
+		mcp.WithDescription(
+			"Check watched products for promotions, prices at their lowest in the last N weeks, "+
+				"and prices at or below their target. Fetches current prices first unless "+
+				"refresh is false."),
+		mcp.WithNumber("weeks",
+			mcp.Description("Number of weeks to compare current prices against (default 8)."),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Fetch current prices for watched products before checking (default true)."),
+		),
+	), s.handleCheckPriceAlerts)
+}
+
+const noHistoryMsg = "price history is unavailable (the database could not be opened)"
+
+func (s *Server) handleGetPriceHistory(
+	_ context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	history := s.client.History()
+	if history == nil {
+		return mcp.NewToolResultError(noHistoryMsg), nil
+	}
+	args := request.GetArguments()
+
+	supermarketID, ok := args["supermarket"].(string)
+	if !ok || supermarketID == "" {
+		return mcp.NewToolResultError("supermarket is required"), nil
+	}
+	productID, ok := args["productId"].(string)
+	if !ok || productID == "" {
+		return mcp.NewToolResultError("productId is required"), nil
+	}
+	weeks := defaultHistoryWeeks
+	if v, ok := args["weeks"].(float64); ok && v >= 1 {
+		weeks = int(v)
+	}
+
+	sum, err := history.Summarise(datasource.SupermarketID(supermarketID), productID, weeks)
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to get price history: %v", err),
+		), nil
+	}
+
+	return formatPriceHistory(sum)
+}
+
+func (s *Server) handleWatchProduct(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	history := s.client.History()
+	if history == nil {
+		return mcp.NewToolResultError(noHistoryMsg), nil
+	}
+	args := request.GetArguments()
+
+	supermarketID, ok := args["supermarket"].(string)
+	if !ok || supermarketID == "" {
+		return mcp.NewToolResultError("supermarket is required"), nil
+	}
+	productID, ok := args["productId"].(string)
+	if !ok || productID == "" {
+		return mcp.NewToolResultError("productId is required"), nil
+	}
+	sid := datasource.SupermarketID(supermarketID)
+
+	if remove, _ := args["remove"].(bool); remove {
+		return unwatchProduct(history, sid, productID)
+	}
+
+	w := pricehistory.Watch{Supermarket: sid, ProductID: productID}
+	if v, ok := args["targetPrice"].(float64); ok && v > 0 {
+		w.TargetPrice = v
+	}
+
+	// Looking the product up checks it exists and records its current price,
+	// so it bypasses the cache.
+	product, err := s.client.GetProductDetails(cache.WithFresh(ctx), sid, productID)
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to get product details: %v", err),
+		), nil
+	}
+	w.Name = product.Name
+
+	if err := history.Watch(w); err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to watch product: %v", err),
+		), nil
+	}
+
+	return formatWatch(w, product)
+}
+
+func (s *Server) handleCheckPriceAlerts(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	history := s.client.History()
+	if history == nil {
+		return mcp.NewToolResultError(noHistoryMsg), nil
+	}
+	args := request.GetArguments()
+
+	weeks := defaultAlertWeeks
+	if v, ok := args["weeks"].(float64); ok && v >= 1 {
+		weeks = int(v)
+	}
+
+	var refreshErrors []string
+	if refresh, ok := args["refresh"].(bool); !ok || refresh {
+		watches, err := history.Watches()
+		if err != nil {
+			return mcp.NewToolResultError(
+				fmt.Sprintf("failed to list watched products: %v", err),
+			), nil
+		}
+		// Fetching details records each product's current price; cached
+		// details would record an old price as a new observation.
+		fresh := cache.WithFresh(ctx)
+		for _, w := range watches {
+			if _, err := s.client.GetProductDetails(fresh, w.Supermarket, w.ProductID); err != nil {
+				refreshErrors = append(refreshErrors, fmt.Sprintf("%s/%s: %v", w.Supermarket, w.ProductID, err))
+			}
+		}
+	}
+
+	alerts, unpriced, err := history.Alerts(weeks)
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to check price alerts: %v", err),
+		), nil
+	}
+
+	return formatPriceAlerts(alerts, unpriced, refreshErrors)
+}
+
+func unwatchProduct(
+	history *pricehistory.Store, sid datasource.SupermarketID, productID string,
+) (*mcp.CallToolResult, error) {
+	if err := history.Unwatch(sid, productID); err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to stop watching product: %v", err),
+		), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Stopped watching %s at %s.", productID, sid)), nil
+}
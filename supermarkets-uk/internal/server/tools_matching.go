@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/client"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/matching"
+)
+
+func (s *Server) registerMatchingTools() {
+	s.mcpServer.AddTool(mcp.NewTool("find_equivalents",
+		mcp.WithDescription(
+			"Find the same product at other supermarkets. Looks up the product, searches the "+
+				"other supermarkets for it, and returns the best match at each with a confidence "+
+				"from 0 to 1. Products are matched by barcode (GTIN) where both stores provide one, "+
+				"and otherwise by brand, name, and pack size. Supermarket own-label products are "+
+				"matched with other stores' own-label equivalents at lower confidence."),
+		mcp.WithString("supermarket",
+			mcp.Required(),
+			mcp.Description("Supermarket ID of the product. Use list_supermarkets to see all available IDs."),
+		),
+		mcp.WithString("productId",
+			mcp.Required(),
+			mcp.Description("Product ID from search results"),
+		),
+		mcp.WithString("supermarkets",
+			mcp.Description("Comma-separated supermarket IDs to search. Omit to search all others."),
+		),
+		mcp.WithNumber("minConfidence",
+			mcp.Description("Lowest confidence to report a match at (default 0.5)."),
+		),
+		freshOption(),
+	), s.handleFindEquivalents)
+}
+
+// equivalents is the find_equivalents result.
+type equivalents struct {
+	Product  datasource.Product         `json:"product"`
+	Query    string                     `json:"query"`
+	Matches  []matching.Match           `json:"matches"`
+	NotFound []datasource.SupermarketID `json:"notFound,omitempty"`
+	Errors   []storeError               `json:"errors,omitempty"`
+}
+
+func (s *Server) handleFindEquivalents(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	ctx = withFresh(ctx, args)
+
+	supermarketID, ok := args["supermarket"].(string)
+	if !ok || supermarketID == "" {
+		return mcp.NewToolResultError("supermarket is required"), nil
+	}
+	productID, ok := args["productId"].(string)
+	if !ok || productID == "" {
+		return mcp.NewToolResultError("productId is required"), nil
+	}
+	minConfidence := matching.DefaultMinConfidence
+	if v, ok := args["minConfidence"].(float64); ok && v > 0 && v <= 1 {
+		minConfidence = v
+	}
+
+	sid := datasource.SupermarketID(supermarketID)
+	product, err := s.client.GetProductDetails(ctx, sid, productID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get product details: %v", err)), nil
+	}
+	target := *product
+	target.ID, target.Supermarket = productID, sid
+
+	query := matching.SearchQuery(target)
+	if query == "" {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("product %s has no name to search other supermarkets for", productID),
+		), nil
+	}
+
+	var others []datasource.SupermarketID
+	if v, ok := args["supermarkets"].(string); ok && v != "" {
+		others = withoutSupermarket(client.ParseSupermarketIDs(v), sid)
+	} else {
+		others = s.otherSupermarkets(sid)
+	}
+	if len(others) == 0 {
+		return mcp.NewToolResultError("no other supermarkets to search"), nil
+	}
+
+	return formatEquivalents(s.searchEquivalents(ctx, target, query, others, minConfidence))
+}
+
+// otherSupermarkets returns every configured supermarket except sid.
+func (s *Server) otherSupermarkets(sid datasource.SupermarketID) []datasource.SupermarketID {
+	var ids []datasource.SupermarketID
+	for _, info := range s.client.ListSupermarkets() {
+		if info.ID != sid {
+			ids = append(ids, info.ID)
+		}
+	}
+	return ids
+}
+
+// searchEquivalents searches the given supermarkets for target and picks
+// the best match at each.
+func (s *Server) searchEquivalents(
+	ctx context.Context, target datasource.Product, query string,
+	supermarkets []datasource.SupermarketID, minConfidence float64,
+) equivalents {
+	result := equivalents{Product: target, Query: query}
+	var candidates []datasource.Product
+	var searched []datasource.SupermarketID
+	for _, r := range s.client.SearchAll(ctx, query, supermarkets) {
+		if r.Error != "" {
+			result.Errors = append(result.Errors, storeError{Supermarket: r.Supermarket, Error: r.Error})
+			continue
+		}
+		candidates = append(candidates, r.Products...)
+		searched = append(searched, r.Supermarket)
+	}
+	result.Matches = matching.FindEquivalents(target, candidates, minConfidence)
+	for _, m := range result.Matches {
+		searched = withoutSupermarket(searched, m.Product.Supermarket)
+	}
+	result.NotFound = searched
+	return result
+}
+
+func withoutSupermarket(ids []datasource.SupermarketID, drop datasource.SupermarketID) []datasource.SupermarketID {
+	var kept []datasource.SupermarketID
+	for _, id := range ids {
+		if id != drop {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
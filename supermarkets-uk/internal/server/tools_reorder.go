@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/cache"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/shopping"
+)
+
+// reorderMaxPages bounds how far back in the order history reorder looks
+// for the requested order.
+const reorderMaxPages = 5
+
+func (s *Server) registerReorderTools() {
+	s.mcpServer.AddTool(mcp.NewTool("reorder",
+		mcp.WithDescription(
+			"Rebuy a past order. Checks every item's current price and availability, "+
+				"and suggests substitutes for items that are unavailable. "+
+				"By default only previews the order; set preview to false to add the "+
+				"available items to the basket, on top of anything already in it. "+
+				"Nothing is added if any item's current details cannot be looked up. "+
+				"Supported supermarkets: tesco, sainsburys, ocado, morrisons. "+
+				"Requires a logged-in session."),
+		mcp.WithString("supermarket",
+			mcp.Required(),
+			mcp.Description("Supermarket ID: 'tesco', 'sainsburys', 'ocado', or 'morrisons'."),
+		),
+		mcp.WithString("orderId",
+			mcp.Required(),
+			mcp.Description("Order ID from get_order_history"),
+		),
+		mcp.WithBoolean("preview",
+			mcp.Description("Only report what would be added, without changing the basket (default true)."),
+		),
+	), s.handleReorder)
+}
+
+func (s *Server) handleReorder(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	supermarketID, ok := args["supermarket"].(string)
+	if !ok || supermarketID == "" {
+		return mcp.NewToolResultError("supermarket is required"), nil
+	}
+	orderID, ok := args["orderId"].(string)
+	if !ok || orderID == "" {
+		return mcp.NewToolResultError("orderId is required"), nil
+	}
+	preview := true
+	if v, ok := args["preview"].(bool); ok {
+		preview = v
+	}
+
+	sid := datasource.SupermarketID(supermarketID)
+	order, err := s.findOrder(ctx, sid, orderID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to find order: %v", err)), nil
+	}
+	if len(order.Items) == 0 {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("order %s has no item details to reorder", orderID),
+		), nil
+	}
+
+	r := &shopping.Reorder{
+		Supermarket: sid,
+		OrderID:     order.ID,
+		OrderDate:   order.Date,
+		Preview:     preview,
+		Lines:       s.checkOrderItems(ctx, sid, order.Items),
+	}
+	r.Summarise()
+	if !preview && r.Errors > 0 {
+		// A partial basket is easy to miss, so add nothing until every item
+		// could be checked.
+		r.Preview = true
+		r.Warning = fmt.Sprintf("basket not changed: %d items could not be checked", r.Errors)
+	}
+	if !r.Preview {
+		if err := s.addReorderLines(ctx, r); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get basket: %v", err)), nil
+		}
+	}
+	r.Summarise()
+	return formatReorder(r)
+}
+
+// findOrder pages through the order history for the order with the given ID.
+func (s *Server) findOrder(
+	ctx context.Context, sid datasource.SupermarketID, orderID string,
+) (*datasource.Order, error) {
+	for page := 1; page <= reorderMaxPages; page++ {
+		result, err := s.client.GetOrderHistory(ctx, sid, page)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result.Orders {
+			if result.Orders[i].ID == orderID {
+				return &result.Orders[i], nil
+			}
+		}
+		if len(result.Orders) == 0 || (result.Total != nil && page*result.PageSize >= *result.Total) {
+			break
+		}
+	}
+	return nil, fmt.Errorf("order %s not found in the last %d pages of %s order history",
+		orderID, reorderMaxPages, sid)
+}
+
+// checkOrderItems looks up each item's current details, bypassing the
+// cache, and searches for substitutes for those that are unavailable.
+func (s *Server) checkOrderItems(
+	ctx context.Context, sid datasource.SupermarketID, items []datasource.OrderItem,
+) []shopping.ReorderLine {
+	ctx = cache.WithFresh(ctx)
+	lines := make([]shopping.ReorderLine, len(items))
+	sem := make(chan struct{}, planSearchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			product, err := s.client.GetProductDetails(ctx, sid, item.ProductID)
+			lines[i] = shopping.NewReorderLine(item, product, err)
+			if lines[i].Status == shopping.ReorderUnavailable && lines[i].Name != "" {
+				var found []datasource.Product
+				for _, res := range s.client.SearchAll(ctx, lines[i].Name, []datasource.SupermarketID{sid}) {
+					found = append(found, res.Products...)
+				}
+				lines[i].Substitutes = shopping.SubstitutesFor(lines[i], found)
+			}
+		}()
+	}
+	wg.Wait()
+	return lines
+}
+
+// addReorderLines adds the available lines to the basket. Basket updates set
+// absolute quantities, so each is added to any quantity already there.
+func (s *Server) addReorderLines(ctx context.Context, r *shopping.Reorder) error {
+	basket, err := s.client.GetBasket(ctx, r.Supermarket)
+	if err != nil {
+		return err
+	}
+	inBasket := make(map[string]int, len(basket.Items))
+	for _, item := range basket.Items {
+		inBasket[item.ProductID] = item.Quantity
+	}
+
+	for i := range r.Lines {
+		line := &r.Lines[i]
+		if line.Status != shopping.ReorderAvailable {
+			continue
+		}
+		updated, err := s.client.UpdateBasketItem(ctx, r.Supermarket, line.ProductID,
+			inBasket[line.ProductID]+line.Quantity)
+		if err != nil {
+			line.Status, line.Error = shopping.ReorderFailed, err.Error()
+			continue
+		}
+		line.Status = shopping.ReorderAdded
+		basket = updated
+	}
+	r.Basket = basket
+	return nil
+}
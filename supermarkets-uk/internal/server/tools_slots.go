@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+const (
+	defaultSlotDays = 7
+	maxSlotDays     = 21
+)
+
+func (s *Server) registerSlotTools() {
+	s.mcpServer.AddTool(mcp.NewTool("get_delivery_slots",
+		mcp.WithDescription(
+			"List delivery and click-and-collect slots with prices. "+
+				"Supported supermarkets: tesco, sainsburys, ocado, morrisons. "+
+				"Requires a logged-in session. "+
+				"Omit supermarket to list slots from every logged-in supermarket, earliest first."),
+		mcp.WithString("supermarket",
+			mcp.Description("Supermarket ID, e.g. 'tesco'. Omit or use 'all' for every logged-in supermarket."),
+		),
+		mcp.WithString("from",
+			mcp.Description("First date to list slots for, as YYYY-MM-DD (default today)."),
+		),
+		mcp.WithString("to",
+			mcp.Description("Last date to list slots for, as YYYY-MM-DD (default 6 days after from, at most 21 days)."),
+		),
+		mcp.WithString("method",
+			mcp.Description("Only list 'delivery' or 'collection' slots (default both)."),
+		),
+		mcp.WithBoolean("includeUnavailable",
+			mcp.Description("Also list slots that are full or otherwise cannot be booked (default false)."),
+		),
+	), s.handleGetDeliverySlots)
+
+	s.mcpServer.AddTool(mcp.NewTool("book_slot",
+		mcp.WithDescription(
+			"Book a delivery or click-and-collect slot for the current basket. "+
+				"Supported supermarkets: tesco. "+
+				"Requires a logged-in session."),
+		mcp.WithString("supermarket",
+			mcp.Required(),
+			mcp.Description("Supermarket ID: 'tesco'."),
+		),
+		mcp.WithString("slotId",
+			mcp.Required(),
+			mcp.Description("Slot ID from get_delivery_slots"),
+		),
+	), s.handleBookSlot)
+}
+
+func (s *Server) handleGetDeliverySlots(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	from, to, err := slotRange(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	method, _ := args["method"].(string)
+	if method != "" && method != datasource.SlotDelivery && method != datasource.SlotCollection {
+		return mcp.NewToolResultError("method must be 'delivery' or 'collection'"), nil
+	}
+	includeUnavailable, _ := args["includeUnavailable"].(bool)
+
+	var results []datasource.SlotResult
+	supermarketID, _ := args["supermarket"].(string)
+	if supermarketID == "" || supermarketID == "all" {
+		results = s.client.GetAllSlots(ctx, from, to)
+		if len(results) == 0 {
+			return mcp.NewToolResultError(
+				"no logged-in supermarkets support slot lookup; " +
+					"enable login with <SUPERMARKET>_LOGIN=true"), nil
+		}
+	} else {
+		sid := datasource.SupermarketID(supermarketID)
+		slots, err := s.client.GetSlots(ctx, sid, from, to)
+		if err != nil {
+			return mcp.NewToolResultError(
+				fmt.Sprintf("failed to get delivery slots: %v", err),
+			), nil
+		}
+		results = []datasource.SlotResult{{Supermarket: sid, Slots: slots}}
+	}
+
+	for i := range results {
+		results[i].Slots = filterSlots(results[i].Slots, method, includeUnavailable)
+	}
+	return formatSlots(from, to.AddDate(0, 0, -1), results)
+}
+
+// slotRange returns the start of the from date and the end of the to date
+// given as arguments, in local time.
+func slotRange(args map[string]any) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if v, ok := args["from"].(string); ok && v != "" {
+		t, err := time.ParseInLocation(datasource.OrderDateLayout, v, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be a date as YYYY-MM-DD: %q", v)
+		}
+		from = t
+	}
+
+	to := from.AddDate(0, 0, defaultSlotDays-1)
+	if v, ok := args["to"].(string); ok && v != "" {
+		t, err := time.ParseInLocation(datasource.OrderDateLayout, v, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be a date as YYYY-MM-DD: %q", v)
+		}
+		to = t
+	}
+
+	switch {
+	case to.Before(from):
+		return time.Time{}, time.Time{}, fmt.Errorf("to must not be before from")
+	case to.Sub(from) >= maxSlotDays*24*time.Hour:
+		return time.Time{}, time.Time{}, fmt.Errorf("at most %d days of slots can be listed at once", maxSlotDays)
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func filterSlots(slots []datasource.Slot, method string, includeUnavailable bool) []datasource.Slot {
+	kept := []datasource.Slot{}
+	for _, slot := range slots {
+		if (method == "" || slot.Method == method) && (includeUnavailable || slot.Available || slot.Booked) {
+			kept = append(kept, slot)
+		}
+	}
+	return kept
+}
+
+func (s *Server) handleBookSlot(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	supermarketID, ok := args["supermarket"].(string)
+	if !ok || supermarketID == "" {
+		return mcp.NewToolResultError("supermarket is required"), nil
+	}
+	slotID, ok := args["slotId"].(string)
+	if !ok || slotID == "" {
+		return mcp.NewToolResultError("slotId is required"), nil
+	}
+
+	slot, err := s.client.BookSlot(ctx, datasource.SupermarketID(supermarketID), slotID)
+	if err != nil {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("failed to book slot: %v", err),
+		), nil
+	}
+
+	return formatBookedSlot(slot)
+}
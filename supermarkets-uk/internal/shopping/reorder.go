@@ -0,0 +1,183 @@
+package shopping
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// ReorderStatus is the outcome of reordering one item of a past order.
+type ReorderStatus string
+
+const (
+	// ReorderAvailable means the product can be added; used in previews.
+	ReorderAvailable ReorderStatus = "available"
+	// ReorderUnavailable means the product is out of stock or no longer sold.
+	ReorderUnavailable ReorderStatus = "unavailable"
+	// ReorderError means the product's current details could not be looked
+	// up, so its availability is unknown.
+	ReorderError ReorderStatus = "error"
+	// ReorderAdded means the product was added to the basket.
+	ReorderAdded ReorderStatus = "added"
+	// ReorderFailed means adding the product to the basket failed.
+	ReorderFailed ReorderStatus = "failed"
+)
+
+// maxSubstitutes is how many substitutes are suggested per unavailable item.
+const maxSubstitutes = 3
+
+// Substitute is a suggested replacement for an unavailable item.
+type Substitute struct {
+	ProductID string                `json:"productId"`
+	Name      string                `json:"name"`
+	Price     float64               `json:"price"`
+	UnitPrice *datasource.UnitPrice `json:"unitPrice,omitempty"`
+	Promotion string                `json:"promotion,omitempty"`
+	// Match is the fraction of the original item's name words that the
+	// substitute's name shares.
+	Match float64 `json:"match"`
+}
+
+// ReorderLine is one item of a past order and how it can be bought now.
+type ReorderLine struct {
+	ProductID string        `json:"productId"`
+	Name      string        `json:"name"`
+	Quantity  int           `json:"quantity"`
+	Status    ReorderStatus `json:"status"`
+	// Price and Cost are the current shelf price and the cost of Quantity
+	// items after any offer, assuming the account's loyalty card.
+	Price       float64           `json:"price,omitempty"`
+	Cost        float64           `json:"cost,omitempty"`
+	Offer       *datasource.Offer `json:"offer,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Substitutes []Substitute      `json:"substitutes,omitempty"`
+}
+
+// Reorder is a past order checked against current availability and prices.
+type Reorder struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	OrderID     string                   `json:"orderId"`
+	OrderDate   string                   `json:"orderDate,omitempty"`
+	// Preview is true when the basket was not changed.
+	Preview bool          `json:"preview"`
+	Lines   []ReorderLine `json:"lines"`
+	// Total is the current cost of the available (or added) items.
+	Total       float64 `json:"total"`
+	Unavailable int     `json:"unavailable"`
+	// Errors counts items whose current details could not be looked up.
+	Errors int                `json:"errors,omitempty"`
+	Failed int                `json:"failed,omitempty"`
+	Basket *datasource.Basket `json:"basket,omitempty"`
+	// Warning explains why the basket was left unchanged when it was asked
+	// to be updated.
+	Warning string `json:"warning,omitempty"`
+}
+
+// NewReorderLine checks an order item against its current product details.
+// A lookup error gives an error line, and an out-of-stock product an
+// unavailable one.
+func NewReorderLine(item datasource.OrderItem, current *datasource.Product, err error) ReorderLine {
+	line := ReorderLine{
+		ProductID: item.ProductID,
+		Name:      item.Name,
+		Quantity:  max(item.Quantity, 1),
+		Status:    ReorderAvailable,
+	}
+	switch {
+	case err != nil:
+		line.Status = ReorderError
+		line.Error = err.Error()
+	case current == nil || current.Price <= 0 || (current.Available != nil && !*current.Available):
+		line.Status = ReorderUnavailable
+	default:
+		if line.Name == "" {
+			line.Name = current.Name
+		}
+		line.Price = current.Price
+		line.Cost, line.Offer = datasource.EffectiveCost(*current, line.Quantity, true)
+	}
+	return line
+}
+
+// SubstitutesFor ranks search results as replacements for an unavailable
+// product, best match first, skipping the product itself, unavailable
+// products, and products sharing no words with its name.
+func SubstitutesFor(line ReorderLine, products []datasource.Product) []Substitute {
+	words := queryWords(line.Name)
+	if len(words) == 0 {
+		return nil
+	}
+	var subs []Substitute
+	for _, p := range products {
+		if p.ID == line.ProductID || p.Price <= 0 || (p.Available != nil && !*p.Available) {
+			continue
+		}
+		matched := 0
+		for _, w := range words {
+			if matchesWords(p.Name, []string{w}) {
+				matched++
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		subs = append(subs, Substitute{
+			ProductID: p.ID,
+			Name:      p.Name,
+			Price:     p.Price,
+			UnitPrice: p.UnitPrice,
+			Promotion: p.Promotion,
+			Match:     math.Round(float64(matched)/float64(len(words))*100) / 100,
+		})
+	}
+	sort.SliceStable(subs, func(i, j int) bool {
+		if subs[i].Match != subs[j].Match {
+			return subs[i].Match > subs[j].Match
+		}
+		return subs[i].Price < subs[j].Price
+	})
+	if len(subs) > maxSubstitutes {
+		subs = subs[:maxSubstitutes]
+	}
+	return subs
+}
+
+// Summarise totals the lines that can be, or were, bought and counts the
+// rest.
+func (r *Reorder) Summarise() {
+	r.Total, r.Unavailable, r.Errors, r.Failed = 0, 0, 0, 0
+	for _, line := range r.Lines {
+		switch line.Status {
+		case ReorderAvailable, ReorderAdded:
+			r.Total += line.Cost
+		case ReorderUnavailable:
+			r.Unavailable++
+		case ReorderError:
+			r.Errors++
+		case ReorderFailed:
+			r.Failed++
+		}
+	}
+	r.Total = math.Round(r.Total*100) / 100
+}
+
+// Describe returns a one-line summary such as "12 of 14 items available".
+func (r *Reorder) Describe() string {
+	verb := "added"
+	if r.Preview {
+		verb = "available"
+	}
+	s := fmt.Sprintf("%d of %d items %s", len(r.Lines)-r.Unavailable-r.Errors-r.Failed, len(r.Lines), verb)
+	if r.Unavailable > 0 {
+		s += fmt.Sprintf(", %d unavailable", r.Unavailable)
+	}
+	if r.Errors > 0 {
+		s += fmt.Sprintf(", %d could not be checked", r.Errors)
+	}
+	if r.Failed > 0 {
+		s += fmt.Sprintf(", %d failed", r.Failed)
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package shopping_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/shopping"
+)
+
+func TestNewReorderLine(t *testing.T) {
+	item := datasource.OrderItem{ProductID: "t1", Name: "Tesco Chopped Tomatoes 400g", Quantity: 4}
+	current := datasource.Product{ID: "t1", Name: item.Name, Price: 0.65, Promotion: "4 for £2"}
+	current.Normalise()
+
+	line := shopping.NewReorderLine(item, &current, nil)
+	assert.Equal(t, shopping.ReorderAvailable, line.Status)
+	assert.InDelta(t, 0.65, line.Price, 0.001)
+	assert.InDelta(t, 2.00, line.Cost, 0.001)
+	require.NotNil(t, line.Offer)
+
+	current.Available = datasource.BoolPtr(false)
+	line = shopping.NewReorderLine(item, &current, nil)
+	assert.Equal(t, shopping.ReorderUnavailable, line.Status)
+	assert.Zero(t, line.Cost)
+
+	line = shopping.NewReorderLine(item, nil, errors.New("HTTP 404"))
+	assert.Equal(t, shopping.ReorderError, line.Status)
+	assert.Equal(t, "HTTP 404", line.Error)
+	assert.Equal(t, 4, line.Quantity)
+}
+
+func TestSubstitutesFor(t *testing.T) {
+	line := shopping.ReorderLine{ProductID: "t1", Name: "Tesco Organic Semi Skimmed Milk 2 Pints"}
+	gone := product("t5", "Tesco Organic Semi Skimmed Milk 1 Pint", 0.95)
+	gone.Available = datasource.BoolPtr(false)
+	subs := shopping.SubstitutesFor(line, []datasource.Product{
+		product("t1", "Tesco Organic Semi Skimmed Milk 2 Pints", 1.55),
+		product("t2", "Tesco Semi Skimmed Milk 2 Pints", 1.25),
+		product("t3", "Arla Organic Semi Skimmed Milk 2 Pints", 1.80),
+		product("t4", "Tesco Whole Milk 2 Pints", 1.25),
+		product("t6", "Tesco Penne Pasta 500g", 0.75),
+		gone,
+	})
+
+	require.Len(t, subs, 3)
+	// Equal matches are ordered by price.
+	assert.Equal(t, "t2", subs[0].ProductID)
+	assert.Equal(t, "t3", subs[1].ProductID)
+	assert.Equal(t, "t4", subs[2].ProductID)
+	assert.Greater(t, subs[0].Match, subs[2].Match)
+}
+
+func TestSubstitutesForMatchesSizes(t *testing.T) {
+	line := shopping.ReorderLine{ProductID: "s1", Name: "Sainsbury's Semi Skimmed Milk 2 Pints"}
+	subs := shopping.SubstitutesFor(line, []datasource.Product{
+		product("s2", "Sainsbury's Semi Skimmed Milk 2pt", 1.45),
+		product("s3", "Sainsbury's Semi Skimmed Milk 4pt", 1.65),
+	})
+
+	require.Len(t, subs, 2)
+	assert.Equal(t, "s2", subs[0].ProductID)
+	assert.InDelta(t, 1, subs[0].Match, 0.001)
+	assert.Less(t, subs[1].Match, subs[0].Match)
+}
+
+func TestReorderSummarise(t *testing.T) {
+	r := shopping.Reorder{Lines: []shopping.ReorderLine{
+		{Status: shopping.ReorderAdded, Cost: 2.00},
+		{Status: shopping.ReorderAdded, Cost: 1.45},
+		{Status: shopping.ReorderUnavailable},
+		{Status: shopping.ReorderFailed, Cost: 0.80},
+		{Status: shopping.ReorderError},
+	}}
+	r.Summarise()
+
+	assert.InDelta(t, 3.45, r.Total, 0.001)
+	assert.Equal(t, 1, r.Unavailable)
+	assert.Equal(t, 1, r.Errors)
+	assert.Equal(t, 1, r.Failed)
+	assert.Equal(t, "2 of 5 items added, 1 unavailable, 1 could not be checked, 1 failed", r.Describe())
+}
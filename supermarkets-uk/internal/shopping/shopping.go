@@ -0,0 +1,460 @@
+// Package shopping plans how to buy a shopping list across supermarkets.
+package shopping
+
+import (
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+)
+
+// sizeTolerance lets a pack slightly smaller than requested count as
+// enough, so 1.9L satisfies "2L" rather than requiring two packs.
+const sizeTolerance = 0.1
+
+// Item is a parsed shopping list entry.
+type Item struct {
+	Text  string `json:"text"`
+	Query string `json:"query"`
+	// Size is the requested weight or volume, if one was given.
+	Size *datasource.Quantity `json:"size,omitempty"`
+	// Count is the number of items wanted when no size was given.
+	Count int `json:"count"`
+}
+
+var (
+	listSeparatorRe = regexp.MustCompile(`[,;\n]+`)
+	leadingCountRe  = regexp.MustCompile(`^(\d+)\s*(?:[x×]\s+)?(\D.*)$`)
+)
+
+// ParseList splits a free-form shopping list on commas, semicolons, and
+// newlines and parses each entry.
+func ParseList(list string) []Item {
+	var items []Item
+	for _, part := range listSeparatorRe.Split(list, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, ParseItem(part))
+		}
+	}
+	return items
+}
+
+// ParseItem parses a single entry such as "2L semi-skimmed milk",
+// "500g penne" or "6 eggs".
+func ParseItem(text string) Item {
+	item := Item{Text: text, Query: text, Count: 1}
+	if q, rest, ok := datasource.ExtractQuantity(text); ok && q.Unit != datasource.Count {
+		item.Size = &q
+		item.Query = cleanQuery(rest)
+		return item
+	}
+	if m := leadingCountRe.FindStringSubmatch(text); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			item.Count = n
+			item.Query = cleanQuery(m[2])
+		}
+	}
+	return item
+}
+
+func cleanQuery(s string) string {
+	return strings.Trim(strings.TrimSpace(s), ",-()")
+}
+
+// Option is a way to buy one item at one store.
+type Option struct {
+	Supermarket datasource.SupermarketID `json:"supermarket"`
+	ProductID   string                   `json:"productId"`
+	Name        string                   `json:"name"`
+	URL         string                   `json:"url,omitempty"`
+	Price       float64                  `json:"price"`
+	Packs       int                      `json:"packs"`
+	// Cost is the price of Packs packs after any applied Offer.
+	Cost  float64           `json:"cost"`
+	Offer *datasource.Offer `json:"offer,omitempty"`
+	// SizeMatched is false when the item's size could not be compared and
+	// a single pack was assumed.
+	SizeMatched bool `json:"sizeMatched"`
+}
+
+// ItemPlan is the options found for one item, with at most one option per
+// store. Size-matched options come first, each group cheapest first.
+type ItemPlan struct {
+	Item    Item     `json:"item"`
+	Options []Option `json:"options"`
+}
+
+// Line is an item assigned to a store in a basket.
+type Line struct {
+	Item   string `json:"item"`
+	Option Option `json:"option"`
+}
+
+// Basket is a way of buying the whole list from one or more stores.
+type Basket struct {
+	Stores    []datasource.SupermarketID           `json:"stores"`
+	Total     float64                              `json:"total"`
+	Subtotals map[datasource.SupermarketID]float64 `json:"subtotals"`
+	Lines     []Line                               `json:"lines"`
+	// Missing lists items the stores could not supply.
+	Missing []string `json:"missing,omitempty"`
+	// BelowMinimum lists stores whose subtotal is under their delivery minimum.
+	BelowMinimum []datasource.SupermarketID `json:"belowMinimum,omitempty"`
+}
+
+// Plan is the result of optimising a shopping list.
+type Plan struct {
+	Items []ItemPlan `json:"items"`
+	// CheapestSingleStore is the cheapest store that supplies every item and
+	// meets its delivery minimum.
+	CheapestSingleStore *Basket `json:"cheapestSingleStore,omitempty"`
+	// CheapestSplit is the cheapest way to buy everything from at most
+	// MaxStores stores, each meeting its delivery minimum.
+	CheapestSplit *Basket `json:"cheapestSplit,omitempty"`
+	// StoreBaskets prices the whole list at each store, cheapest first.
+	StoreBaskets []Basket `json:"storeBaskets"`
+	MaxStores    int      `json:"maxStores"`
+	// Unmatched lists items no store could supply.
+	Unmatched []string `json:"unmatched,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// Options control how a plan is made.
+type Options struct {
+	// MaxStores is the most stores the cheapest split may use.
+	MaxStores int
+	// Minimums gives each store's delivery minimum; stores without one have
+	// no minimum.
+	Minimums map[datasource.SupermarketID]float64
+	// LoyaltyPrices applies loyalty card offers such as Clubcard Prices.
+	LoyaltyPrices bool
+}
+
+// MakePlan chooses products for each item from its per-store search results
+// and finds the cheapest single-store and split baskets.
+func MakePlan(items []Item, results [][]datasource.SearchResult, opts Options) *Plan {
+	plan := &Plan{MaxStores: opts.MaxStores, StoreBaskets: []Basket{}}
+	storeSet := map[datasource.SupermarketID]bool{}
+	var stores []datasource.SupermarketID
+
+	for i, item := range items {
+		for _, r := range results[i] {
+			if r.Error == "" && !storeSet[r.Supermarket] {
+				storeSet[r.Supermarket] = true
+				stores = append(stores, r.Supermarket)
+			}
+		}
+		ip, errs := planItem(item, results[i], opts.LoyaltyPrices)
+		plan.Errors = append(plan.Errors, errs...)
+		if len(ip.Options) == 0 {
+			plan.Unmatched = append(plan.Unmatched, item.Text)
+		}
+		plan.Items = append(plan.Items, ip)
+	}
+
+	plan.chooseBaskets(stores, opts.Minimums)
+	return plan
+}
+
+// planItem collects the best option from each store's results for an item.
+func planItem(item Item, results []datasource.SearchResult, loyalty bool) (ItemPlan, []string) {
+	ip := ItemPlan{Item: item, Options: []Option{}}
+	var errs []string
+	for _, r := range results {
+		if r.Error != "" {
+			errs = append(errs, string(r.Supermarket)+" ("+item.Query+"): "+r.Error)
+			continue
+		}
+		if opt, ok := bestOption(item, r.Supermarket, r.Products, loyalty); ok {
+			ip.Options = append(ip.Options, opt)
+		}
+	}
+	sort.SliceStable(ip.Options, func(a, b int) bool {
+		oa, ob := ip.Options[a], ip.Options[b]
+		if oa.SizeMatched != ob.SizeMatched {
+			return oa.SizeMatched
+		}
+		return oa.Cost < ob.Cost
+	})
+	return ip, errs
+}
+
+// chooseBaskets prices the list at each store and finds the cheapest
+// complete single-store and split baskets.
+func (p *Plan) chooseBaskets(stores []datasource.SupermarketID, minimums map[datasource.SupermarketID]float64) {
+	complete := func(b Basket, size int) bool {
+		return len(b.Missing) == len(p.Unmatched) && len(b.BelowMinimum) == 0 && len(b.Subtotals) == size
+	}
+	for _, s := range stores {
+		b := buildBasket(p.Items, []datasource.SupermarketID{s}, minimums)
+		p.StoreBaskets = append(p.StoreBaskets, b)
+		if complete(b, 1) && (p.CheapestSingleStore == nil || b.Total < p.CheapestSingleStore.Total) {
+			single := b
+			p.CheapestSingleStore = &single
+		}
+	}
+	sort.SliceStable(p.StoreBaskets, func(a, b int) bool {
+		ba, bb := p.StoreBaskets[a], p.StoreBaskets[b]
+		if len(ba.Missing) != len(bb.Missing) {
+			return len(ba.Missing) < len(bb.Missing)
+		}
+		return ba.Total < bb.Total
+	})
+
+	// Subsets are visited smallest first, so ties favour fewer stores.
+	forEachSubset(stores, p.MaxStores, func(subset []datasource.SupermarketID) {
+		b := buildBasket(p.Items, subset, minimums)
+		if complete(b, len(subset)) && (p.CheapestSplit == nil || b.Total < p.CheapestSplit.Total-1e-9) {
+			p.CheapestSplit = &b
+		}
+	})
+}
+
+// bestOption returns the cheapest way to buy an item from a store's search
+// results. Products must mention every query word and, when the item has a
+// size, be measured in the same unit; if no product's size is comparable, a
+// single pack of the cheapest relevant product is assumed instead.
+func bestOption(
+	item Item, id datasource.SupermarketID, products []datasource.Product, loyalty bool,
+) (Option, bool) {
+	words := queryWords(item.Query)
+	var best, fallback *Option
+	for _, p := range products {
+		if p.Price <= 0 || (p.Available != nil && !*p.Available) || !matchesWords(p.Name, words) {
+			continue
+		}
+		opt := newOption(item, id, p, loyalty)
+		target := &best
+		if !opt.SizeMatched {
+			target = &fallback
+		}
+		if *target == nil || opt.Cost < (*target).Cost {
+			*target = &opt
+		}
+	}
+	if best == nil {
+		best = fallback
+	}
+	if best == nil {
+		return Option{}, false
+	}
+	return *best, true
+}
+
+// newOption prices enough packs of p to cover the item, applying the best
+// offer for that many packs.
+func newOption(item Item, id datasource.SupermarketID, p datasource.Product, loyalty bool) Option {
+	opt := Option{
+		Supermarket: id,
+		ProductID:   p.ID,
+		Name:        p.Name,
+		URL:         p.URL,
+		Price:       p.Price,
+		Packs:       item.Count,
+		SizeMatched: true,
+	}
+	if item.Size == nil {
+		// Counted items may come in packs, e.g. "6 eggs" as one box of six.
+		want := datasource.Quantity{Amount: float64(item.Count), Unit: datasource.Count, Packs: 1}
+		if packs, ok := packsNeeded(want, p); ok {
+			opt.Packs = packs
+		}
+	} else if packs, ok := packsNeeded(*item.Size, p); ok {
+		opt.Packs = packs
+	} else {
+		opt.Packs = 1
+		opt.SizeMatched = false
+	}
+	opt.Cost, opt.Offer = datasource.EffectiveCost(p, opt.Packs, loyalty)
+	return opt
+}
+
+// packsNeeded returns how many packs of p cover the wanted size.
+func packsNeeded(want datasource.Quantity, p datasource.Product) (int, bool) {
+	if p.Quantity != nil && p.Quantity.Unit == want.Unit && p.Quantity.Total() > 0 {
+		return max(1, int(math.Ceil(want.Total()/p.Quantity.Total()-sizeTolerance))), true
+	}
+	have, ok := datasource.ParseQuantity(p.Name)
+	if !ok || have.Unit != want.Unit {
+		have, ok = datasource.ParseQuantity(p.Weight)
+	}
+	if !ok || have.Unit != want.Unit || have.Total() <= 0 {
+		return 0, false
+	}
+	return max(1, int(math.Ceil(want.Total()/have.Total()-sizeTolerance))), true
+}
+
+var (
+	wordRe = regexp.MustCompile(`[a-z0-9]+(?:\.[0-9]+)?`)
+	// sizeWordRe matches a size written as one word, such as "2pt" or "500g".
+	sizeWordRe = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)([a-z]+)$`)
+	numberRe   = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+)?$`)
+)
+
+// stopWords are ignored when matching product names against a query.
+var stopWords = map[string]bool{"and": true, "of": true, "the": true, "with": true, "a": true}
+
+// unitWords maps the ways a size unit is written in product names to one
+// form, so "2 pints" and "2pt" match.
+var unitWords = map[string]string{
+	"pint": "pt", "pints": "pt", "pt": "pt", "pts": "pt",
+	"litre": "l", "litres": "l", "liter": "l", "liters": "l", "ltr": "l", "l": "l",
+	"millilitre": "ml", "millilitres": "ml", "milliliter": "ml", "milliliters": "ml", "ml": "ml",
+	"centilitre": "cl", "centilitres": "cl", "cl": "cl",
+	"gram": "g", "grams": "g", "g": "g",
+	"kilogram": "kg", "kilograms": "kg", "kilo": "kg", "kilos": "kg", "kg": "kg",
+	"pack": "pk", "packs": "pk", "pk": "pk",
+}
+
+// stem reduces a word to a form shared by its singular and plural, so
+// "eggs" matches "egg", "cherries" matches "cherry", and "tomatoes" matches
+// "tomato".
+func stem(w string) string {
+	if len(w) <= 3 {
+		return w
+	}
+	switch {
+	case strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "i"
+	case strings.HasSuffix(w, "es"):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		w = w[:len(w)-1]
+	}
+	switch {
+	case strings.HasSuffix(w, "e"):
+		return w[:len(w)-1]
+	case strings.HasSuffix(w, "y"):
+		return w[:len(w)-1] + "i"
+	}
+	return w
+}
+
+// normalWords splits text into lowercase words for matching. Plurals are
+// stemmed, and sizes are written as one word with a standard unit, so
+// "2 Pints" and "2pt" both become "2pt".
+func normalWords(text string) []string {
+	raw := wordRe.FindAllString(strings.ToLower(text), -1)
+	words := make([]string, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		w := raw[i]
+		if m := sizeWordRe.FindStringSubmatch(w); m != nil {
+			if unit, ok := unitWords[m[2]]; ok {
+				words = append(words, m[1]+unit)
+				continue
+			}
+		}
+		if numberRe.MatchString(w) && i+1 < len(raw) {
+			if unit, ok := unitWords[raw[i+1]]; ok {
+				words = append(words, w+unit)
+				i++
+				continue
+			}
+		}
+		if unit, ok := unitWords[w]; ok {
+			words = append(words, unit)
+			continue
+		}
+		words = append(words, stem(w))
+	}
+	return words
+}
+
+func queryWords(query string) []string {
+	var words []string
+	for _, w := range normalWords(query) {
+		if !stopWords[w] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// matchesWords reports whether name contains every word, comparing
+// normalised words so case, hyphens, plurals, and how sizes are written
+// don't matter.
+func matchesWords(name string, words []string) bool {
+	have := make(map[string]bool)
+	for _, w := range normalWords(name) {
+		have[w] = true
+	}
+	for _, w := range words {
+		if !have[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// buildBasket assigns each item to the cheapest of the given stores.
+func buildBasket(
+	items []ItemPlan, stores []datasource.SupermarketID, minimums map[datasource.SupermarketID]float64,
+) Basket {
+	allowed := make(map[datasource.SupermarketID]bool, len(stores))
+	for _, s := range stores {
+		allowed[s] = true
+	}
+	b := Basket{
+		Stores:    stores,
+		Subtotals: make(map[datasource.SupermarketID]float64, len(stores)),
+		Lines:     []Line{},
+	}
+	for _, ip := range items {
+		found := false
+		for _, opt := range ip.Options {
+			if allowed[opt.Supermarket] {
+				b.Lines = append(b.Lines, Line{Item: ip.Item.Text, Option: opt})
+				b.Subtotals[opt.Supermarket] += opt.Cost
+				b.Total += opt.Cost
+				found = true
+				break
+			}
+		}
+		if !found {
+			b.Missing = append(b.Missing, ip.Item.Text)
+		}
+	}
+	for _, s := range stores {
+		if sub, ok := b.Subtotals[s]; ok && sub < minimums[s] {
+			b.BelowMinimum = append(b.BelowMinimum, s)
+		}
+	}
+	b.Total = math.Round(b.Total*100) / 100
+	return b
+}
+
+// forEachSubset calls fn with every non-empty subset of stores of at most
+// size n, in order of size.
+func forEachSubset(stores []datasource.SupermarketID, n int, fn func([]datasource.SupermarketID)) {
+	var rec func(start int, cur []datasource.SupermarketID, size int)
+	rec = func(start int, cur []datasource.SupermarketID, size int) {
+		if len(cur) == size {
+			fn(append([]datasource.SupermarketID(nil), cur...))
+			return
+		}
+		for i := start; i < len(stores); i++ {
+			rec(i+1, append(cur, stores[i]), size)
+		}
+	}
+	for size := 1; size <= min(n, len(stores)); size++ {
+		rec(0, nil, size)
+	}
+}
+
+// LoadDeliveryMinimums reads minimum order values for the given supermarkets
+// from <ID>_DELIVERY_MINIMUM environment variables, e.g.
+// TESCO_DELIVERY_MINIMUM=50.
+func LoadDeliveryMinimums(ids []datasource.SupermarketID) map[datasource.SupermarketID]float64 {
+	minimums := make(map[datasource.SupermarketID]float64)
+	for _, id := range ids {
+		val := strings.TrimSpace(os.Getenv(strings.ToUpper(string(id)) + "_DELIVERY_MINIMUM"))
+		if v, err := strconv.ParseFloat(strings.TrimPrefix(val, "£"), 64); err == nil && v > 0 {
+			minimums[id] = v
+		}
+	}
+	return minimums
+}
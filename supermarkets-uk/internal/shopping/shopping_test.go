@@ -0,0 +1,195 @@
+package shopping_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/datasource"
+	"github.com/jbeshir/mcp-servers/supermarkets-uk/internal/shopping"
+)
+
+func TestParseList(t *testing.T) {
+	items := shopping.ParseList("2L semi-skimmed milk, 500g penne;\n6 eggs, bananas")
+	require.Len(t, items, 4)
+
+	require.NotNil(t, items[0].Size)
+	assert.Equal(t, "semi-skimmed milk", items[0].Query)
+	assert.InDelta(t, 2000, items[0].Size.Total(), 0.001)
+	assert.Equal(t, datasource.Millilitres, items[0].Size.Unit)
+
+	require.NotNil(t, items[1].Size)
+	assert.Equal(t, "penne", items[1].Query)
+	assert.Equal(t, datasource.Grams, items[1].Size.Unit)
+
+	assert.Nil(t, items[2].Size)
+	assert.Equal(t, "eggs", items[2].Query)
+	assert.Equal(t, 6, items[2].Count)
+
+	assert.Equal(t, "bananas", items[3].Query)
+	assert.Equal(t, 1, items[3].Count)
+}
+
+func product(id, name string, price float64) datasource.Product {
+	return datasource.Product{ID: id, Name: name, Price: price}
+}
+
+// listResults builds search results for milk and penne at two stores: Tesco
+// is cheaper for milk and Asda for penne.
+func listResults() [][]datasource.SearchResult {
+	return [][]datasource.SearchResult{
+		{
+			{Supermarket: datasource.Tesco, Products: []datasource.Product{
+				product("t1", "Tesco Semi Skimmed Milk 1 Litre", 0.80),
+				product("t2", "Tesco Semi Skimmed Milk 2.272L/4 Pints", 1.45),
+				product("t3", "Tesco Whole Milk 2L", 1.00),
+			}},
+			{Supermarket: datasource.Asda, Products: []datasource.Product{
+				product("a1", "ASDA Semi-Skimmed Milk 2L", 1.65),
+			}},
+		},
+		{
+			{Supermarket: datasource.Tesco, Products: []datasource.Product{
+				product("t4", "Tesco Penne Pasta 500g", 0.75),
+			}},
+			{Supermarket: datasource.Asda, Products: []datasource.Product{
+				product("a2", "ASDA Penne Pasta 1kg", 0.55),
+			}},
+		},
+	}
+}
+
+func TestMakePlan(t *testing.T) {
+	items := shopping.ParseList("2L semi-skimmed milk, 500g penne")
+	plan := shopping.MakePlan(items, listResults(), shopping.Options{MaxStores: 2})
+
+	require.Len(t, plan.Items, 2)
+	milk := plan.Items[0].Options
+	require.Len(t, milk, 2)
+	assert.Equal(t, "t2", milk[0].ProductID)
+	assert.Equal(t, 1, milk[0].Packs)
+	assert.True(t, milk[0].SizeMatched)
+
+	require.NotNil(t, plan.CheapestSingleStore)
+	assert.Equal(t, []datasource.SupermarketID{datasource.Tesco}, plan.CheapestSingleStore.Stores)
+	assert.InDelta(t, 2.20, plan.CheapestSingleStore.Total, 0.001)
+
+	require.NotNil(t, plan.CheapestSplit)
+	assert.ElementsMatch(t, []datasource.SupermarketID{datasource.Tesco, datasource.Asda}, plan.CheapestSplit.Stores)
+	assert.InDelta(t, 2.00, plan.CheapestSplit.Total, 0.001)
+	assert.Len(t, plan.StoreBaskets, 2)
+	assert.Empty(t, plan.Unmatched)
+}
+
+func TestMakePlanPacks(t *testing.T) {
+	items := shopping.ParseList("2L semi-skimmed milk")
+	results := [][]datasource.SearchResult{{
+		{Supermarket: datasource.Tesco, Products: []datasource.Product{
+			product("t1", "Tesco Semi Skimmed Milk 1 Litre", 0.80),
+		}},
+	}}
+	plan := shopping.MakePlan(items, results, shopping.Options{MaxStores: 1})
+
+	require.Len(t, plan.Items[0].Options, 1)
+	assert.Equal(t, 2, plan.Items[0].Options[0].Packs)
+	assert.InDelta(t, 1.60, plan.Items[0].Options[0].Cost, 0.001)
+}
+
+func TestMakePlanHonoursMinimums(t *testing.T) {
+	items := shopping.ParseList("2L semi-skimmed milk, 500g penne")
+	minimums := map[datasource.SupermarketID]float64{datasource.Asda: 1.00}
+	plan := shopping.MakePlan(items, listResults(), shopping.Options{MaxStores: 2, Minimums: minimums})
+
+	// Asda's penne alone is under its minimum, so the split is not allowed.
+	require.NotNil(t, plan.CheapestSplit)
+	assert.Equal(t, []datasource.SupermarketID{datasource.Tesco}, plan.CheapestSplit.Stores)
+
+	minimums[datasource.Tesco] = 10
+	plan = shopping.MakePlan(items, listResults(), shopping.Options{MaxStores: 2, Minimums: minimums})
+	require.NotNil(t, plan.CheapestSingleStore)
+	assert.Equal(t, []datasource.SupermarketID{datasource.Asda}, plan.CheapestSingleStore.Stores)
+	require.NotNil(t, plan.CheapestSplit)
+	assert.Equal(t, []datasource.SupermarketID{datasource.Asda}, plan.CheapestSplit.Stores)
+}
+
+func TestMakePlanUnmatched(t *testing.T) {
+	items := shopping.ParseList("6 eggs, saffron")
+	results := [][]datasource.SearchResult{
+		{{Supermarket: datasource.Tesco, Products: []datasource.Product{
+			product("t1", "Tesco Free Range Eggs 12 Pack", 2.50),
+			product("t2", "Tesco Free Range Eggs 6 Pack", 1.40),
+		}}},
+		{
+			{Supermarket: datasource.Tesco, Products: []datasource.Product{product("t3", "Paprika 50g", 1.00)}},
+			{Supermarket: datasource.Asda, Error: "blocked"},
+		},
+	}
+	plan := shopping.MakePlan(items, results, shopping.Options{MaxStores: 2})
+
+	require.Len(t, plan.Items[0].Options, 1)
+	assert.Equal(t, "t2", plan.Items[0].Options[0].ProductID)
+	assert.Equal(t, 1, plan.Items[0].Options[0].Packs)
+	assert.Equal(t, []string{"saffron"}, plan.Unmatched)
+	assert.Len(t, plan.Errors, 1)
+	require.NotNil(t, plan.CheapestSingleStore)
+	assert.Equal(t, []string{"saffron"}, plan.CheapestSingleStore.Missing)
+}
+
+func TestMakePlanAppliesOffers(t *testing.T) {
+	items := shopping.ParseList("4 x 400g chopped tomatoes")
+	tomatoes := datasource.Product{ID: "t1", Name: "Tesco Chopped Tomatoes 400g", Price: 0.65, Promotion: "4 for £2"}
+	clubcard := datasource.Product{ID: "t2", Name: "Napolina Chopped Tomatoes 400g", Price: 1, Promotion: "Clubcard Price 45p"}
+	tomatoes.Normalise()
+	clubcard.Normalise()
+	results := [][]datasource.SearchResult{{
+		{Supermarket: datasource.Tesco, Products: []datasource.Product{tomatoes, clubcard}},
+	}}
+
+	plan := shopping.MakePlan(items, results, shopping.Options{MaxStores: 1, LoyaltyPrices: true})
+	opt := plan.Items[0].Options[0]
+	assert.Equal(t, "t2", opt.ProductID)
+	assert.Equal(t, 4, opt.Packs)
+	assert.InDelta(t, 1.80, opt.Cost, 0.001)
+	require.NotNil(t, opt.Offer)
+	assert.Equal(t, datasource.LoyaltyPrice, opt.Offer.Kind)
+
+	plan = shopping.MakePlan(items, results, shopping.Options{MaxStores: 1})
+	opt = plan.Items[0].Options[0]
+	assert.Equal(t, "t1", opt.ProductID)
+	assert.InDelta(t, 2.00, opt.Cost, 0.001)
+}
+
+func TestLoadDeliveryMinimums(t *testing.T) {
+	t.Setenv("TESCO_DELIVERY_MINIMUM", "£50")
+	t.Setenv("OCADO_DELIVERY_MINIMUM", "40.5")
+	t.Setenv("ASDA_DELIVERY_MINIMUM", "bogus")
+	t.Setenv("MYSHOP_DELIVERY_MINIMUM", "25")
+
+	ids := append([]datasource.SupermarketID{"myshop"}, datasource.AllSupermarkets...)
+	minimums := shopping.LoadDeliveryMinimums(ids)
+	assert.InDelta(t, 50, minimums[datasource.Tesco], 0.001)
+	assert.InDelta(t, 40.5, minimums[datasource.Ocado], 0.001)
+	assert.InDelta(t, 25, minimums["myshop"], 0.001)
+	_, ok := minimums[datasource.Asda]
+	assert.False(t, ok)
+}
+
+func TestMakePlanNormalisesWords(t *testing.T) {
+	items := shopping.ParseList("cherries, free range eggs")
+	results := [][]datasource.SearchResult{
+		{{Supermarket: datasource.Tesco, Products: []datasource.Product{
+			product("t1", "Tesco Cherry Punnet 400g", 2.00),
+		}}},
+		{{Supermarket: datasource.Tesco, Products: []datasource.Product{
+			product("t2", "Tesco Free-Range Egg Box", 1.50),
+		}}},
+	}
+	plan := shopping.MakePlan(items, results, shopping.Options{MaxStores: 1})
+
+	require.Len(t, plan.Items, 2)
+	require.Len(t, plan.Items[0].Options, 1)
+	assert.Equal(t, "t1", plan.Items[0].Options[0].ProductID)
+	require.Len(t, plan.Items[1].Options, 1)
+	assert.Equal(t, "t2", plan.Items[1].Options[0].ProductID)
+}